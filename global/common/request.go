@@ -16,9 +16,19 @@ type Specs struct {
 	// VD: lấy Product kèm Category, Brand → 1 query thay vì N query
 	Relations []string
 
+	// Preloads: preload có điều kiện — lọc, chọn column, sort, giới hạn số con mỗi cha
+	// VD: chỉ load 5 bài viết đã publish mới nhất của mỗi user
+	// {Name: "Posts", Filters: {"publish": 2}, Sort: "created_at desc", Limit: 5}
+	Preloads []RelationSpec
+
 	// --- Lọc dữ liệu ---
 	// Filters: điều kiện WHERE field = value (exact match)
 	// VD: {"status": "active", "category_id": 5}
+	//
+	// Key dạng "Relation.column" → lọc record cha theo field của quan hệ (whereHas)
+	// Áp dụng cho cả Filters, RangeFilters, InFilters, lồng được nhiều cấp
+	// VD: {"Catalogue.role": "admin"} → users thuộc catalogue có role = admin
+	// VD: {"Category.slug": "phones"} → products thuộc category slug = phones
 	Filters map[string]any
 
 	// RangeFilters: điều kiện WHERE field BETWEEN min AND max
@@ -50,6 +60,12 @@ type Specs struct {
 	Limit  int
 	Offset int
 
+	// --- Page-number pagination ---
+	// Cách viết khác của offset cho front-end: ?page=3&per_page=20 → Limit 20, Offset 40
+	// Page = 0 → không dùng, giữ nguyên Limit / Offset (xem ApplyPage)
+	Page    int
+	PerPage int // 0 = dùng Limit
+
 	// --- Keyset pagination (Cursor-based) ---
 	// Dùng khi: data lớn (>100k), infinite scroll, feed
 	// Cách hoạt động: WHERE id < cursor thay vì OFFSET n
@@ -70,6 +86,16 @@ type RangeFilter struct {
 	Max any // giá trị lớn nhất (nil = không giới hạn trên)
 }
 
+// RelationSpec — cấu hình preload chi tiết cho 1 quan hệ
+// Mọi field đều được validate theo GORM schema của model con
+type RelationSpec struct {
+	Name    string         // tên relation, lồng nhiều cấp bằng dấu chấm (VD: "Catalogue.Users.Profile")
+	Filters map[string]any // WHERE field = value trên bảng con (VD: {"publish": 2})
+	Select  []string       // chỉ lấy các column này (primary key + khóa liên kết tự thêm)
+	Sort    string         // sắp xếp bảng con (VD: "created_at desc")
+	Limit   int            // số con tối đa MỖI record cha, chỉ áp dụng has-many (0 = không giới hạn)
+}
+
 // DefaultSpecs — giá trị mặc định an toàn
 // Mọi module nên bắt đầu từ đây rồi override field cần thiết
 func DefaultSpecs() *Specs {
@@ -77,6 +103,7 @@ func DefaultSpecs() *Specs {
 		Keyword:         "",
 		KeywordFields:   []string{"name", "title"},
 		Relations:       []string{},
+		Preloads:        []RelationSpec{},
 		Filters:         map[string]any{},
 		RangeFilters:    map[string]RangeFilter{},
 		InFilters:       map[string][]any{},
@@ -84,8 +111,26 @@ func DefaultSpecs() *Specs {
 		Sort:            "id desc",
 		Limit:           20,
 		Offset:          0,
+		Page:            0,
+		PerPage:         0,
 		UseKeyset:       false,
 		CursorField:     "id",
 		CursorDirection: "lt",
 	}
 }
+
+// ApplyPage — page-number mode → Limit / Offset (repository gọi trước khi query)
+// VD: Page 3, PerPage 20 → Limit 20, Offset 40; keyset hoặc Page = 0 → giữ nguyên
+func (s Specs) ApplyPage() Specs {
+	if s.UseKeyset || s.Page < 1 {
+		return s
+	}
+	if s.PerPage > 0 {
+		s.Limit = s.PerPage
+	}
+	s.Offset = 0
+	if s.Limit > 0 {
+		s.Offset = (s.Page - 1) * s.Limit
+	}
+	return s
+}
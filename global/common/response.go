@@ -5,8 +5,47 @@ package common
 // Dùng chung cho mọi data models trong dự án
 // ============================================================
 type PaginateResult[T any] struct {
-	Data       []T   `json:"data"`        // mảng data
-	Total      int64 `json:"total"`       // tổng số (offset), -1 (keyset)
-	NextCursor any   `json:"next_cursor"` // cursor cho trang tiếp (keyset)
-	HasMore    bool  `json:"has_more"`    // còn trang tiếp không?
+	Data       []T        `json:"data"`            // mảng data
+	Total      int64      `json:"total"`           // tổng số (offset), -1 (keyset)
+	NextCursor any        `json:"next_cursor"`     // cursor cho trang tiếp (keyset)
+	HasMore    bool       `json:"has_more"`        // còn trang tiếp không?
+	Meta       *PageMeta  `json:"meta,omitempty"`  // chỉ offset / page mode (keyset không có total)
+	Links      *PageLinks `json:"links,omitempty"` // tầng HTTP gắn vào (xem base.PageLinks)
+}
+
+// PageMeta — số trang tính sẵn cho front-end
+// VD: total 45, per_page 20, offset 20 → current_page 2, last_page 3, from 21, to 40
+// Trang rỗng → from = to = 0
+type PageMeta struct {
+	CurrentPage int `json:"current_page"`
+	PerPage     int `json:"per_page"`
+	LastPage    int `json:"last_page"`
+	From        int `json:"from"`
+	To          int `json:"to"`
+}
+
+// NewPageMeta — tính meta từ Limit / Offset đã áp dụng + số record thực tế của trang
+// limit <= 0 (không giới hạn) → 1 trang duy nhất
+func NewPageMeta(total int64, limit, offset, count int) *PageMeta {
+	meta := &PageMeta{CurrentPage: 1, PerPage: limit, LastPage: 1}
+	if limit > 0 {
+		meta.CurrentPage = offset/limit + 1
+		meta.LastPage = max(1, int((total+int64(limit)-1)/int64(limit)))
+	} else {
+		meta.PerPage = count
+	}
+	if count > 0 {
+		meta.From = offset + 1
+		meta.To = offset + count
+	}
+	return meta
+}
+
+// PageLinks — URL các trang, rỗng = không có trang đó (VD: trang đầu không có prev)
+// Keyset không biết trang cuối / trang trước → chỉ first + next
+type PageLinks struct {
+	First string `json:"first,omitempty"`
+	Last  string `json:"last,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
 }
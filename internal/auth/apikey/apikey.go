@@ -0,0 +1,191 @@
+// Package apikey — API key cho worker nội bộ / đối tác (thay cho JWT của user)
+//
+// Định dạng: gbk_<prefix>_<secret>
+// → prefix: 8 ký tự hex, lưu thẳng trong DB, tra cứu bằng unique index
+// → secret: 32 byte ngẫu nhiên base64url
+// → DB chỉ lưu SHA-256 của cả key → lộ DB không dùng được key
+//
+// Key xác thực thành công → auth.Principal{Kind: api_key, Permissions: scopes}
+// → RequirePermission / Policy dùng chung như user
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang-base/internal/auth"
+	"golang-base/internal/event"
+	"golang-base/internal/model"
+	ur "golang-base/internal/repository/user"
+	"golang-base/pkg/secure"
+
+	"gorm.io/gorm"
+)
+
+// KeyPrefix — tiền tố nhận diện key (giúp secret scanner phát hiện key bị lộ)
+const KeyPrefix = "gbk"
+
+// Header — header client gửi key
+const Header = "X-API-Key"
+
+// DefaultCacheTTL — thời gian cache key đã xác thực
+// Thu hồi trong cùng process có hiệu lực ngay (event Revoked), instance khác chậm tối đa TTL
+const DefaultCacheTTL = 30 * time.Second
+
+// ErrInvalidKey — key sai định dạng / không tồn tại / sai secret / hết hạn / bị thu hồi
+var ErrInvalidKey = errors.New("invalid api key")
+
+// Revoked — publish khi thu hồi key
+type Revoked struct {
+	ID     uint64
+	Prefix string
+}
+
+// Generate — sinh key mới, trả về key gốc (đưa cho client 1 lần), prefix, hash
+func Generate() (raw, prefix, hash string, err error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", "", fmt.Errorf("generate api key prefix failed: %w", err)
+	}
+	prefix = hex.EncodeToString(b)
+
+	secret, err := secure.RandomToken(32)
+	if err != nil {
+		return "", "", "", err
+	}
+	raw = KeyPrefix + "_" + prefix + "_" + secret
+	return raw, prefix, secure.HashToken(raw), nil
+}
+
+// Split — tách prefix từ key gốc, sai định dạng → ok=false
+func Split(raw string) (prefix string, ok bool) {
+	rest, ok := strings.CutPrefix(raw, KeyPrefix+"_")
+	if !ok {
+		return "", false
+	}
+	prefix, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(prefix) != 8 || secret == "" {
+		return "", false
+	}
+	return prefix, true
+}
+
+// Principal — key → principal (ID = id của key, Permissions = scopes)
+func Principal(key *model.APIKey) *auth.Principal {
+	return &auth.Principal{
+		ID:          strconv.FormatUint(key.ID, 10),
+		Kind:        auth.KindAPIKey,
+		Permissions: key.Scopes,
+		Attributes:  map[string]any{"api_key_prefix": key.Prefix, "api_key_name": key.Name},
+	}
+}
+
+type cached struct {
+	key       *model.APIKey
+	hash      string
+	expiresAt time.Time
+}
+
+// Authenticator — xác thực key, cache theo prefix, an toàn khi dùng đồng thời
+type Authenticator struct {
+	db         *gorm.DB
+	keys       *ur.APIKeyRepository
+	ttl        time.Duration
+	touchEvery time.Duration
+	now        func() time.Time
+
+	mu    sync.RWMutex
+	cache map[string]cached
+}
+
+func NewAuthenticator(db *gorm.DB, ttl time.Duration) *Authenticator {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return &Authenticator{
+		db:         db,
+		keys:       ur.NewAPIKeyRepository(db),
+		ttl:        ttl,
+		touchEvery: time.Minute,
+		now:        time.Now,
+		cache:      make(map[string]cached),
+	}
+}
+
+// Authenticate — key gốc → principal, cập nhật last_used_at (tối đa 1 lần / phút)
+func (a *Authenticator) Authenticate(ctx context.Context, raw string) (*auth.Principal, error) {
+	prefix, ok := Split(raw)
+	if !ok {
+		return nil, ErrInvalidKey
+	}
+	now := a.now()
+
+	key, hash, err := a.lookup(ctx, prefix, now)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil || subtle.ConstantTimeCompare([]byte(hash), []byte(secure.HashToken(raw))) != 1 {
+		return nil, ErrInvalidKey
+	}
+	if !key.Active(now) {
+		return nil, ErrInvalidKey
+	}
+
+	// last_used_at chỉ mang tính thống kê → lỗi không chặn request
+	_ = a.keys.WithTx(a.db.WithContext(ctx)).TouchLastUsed(key.ID, now, a.touchEvery)
+	return Principal(key), nil
+}
+
+func (a *Authenticator) lookup(ctx context.Context, prefix string, now time.Time) (*model.APIKey, string, error) {
+	a.mu.RLock()
+	c, ok := a.cache[prefix]
+	a.mu.RUnlock()
+	if ok && now.Before(c.expiresAt) {
+		return c.key, c.hash, nil
+	}
+
+	key, err := a.keys.WithTx(a.db.WithContext(ctx)).FindByPrefix(prefix)
+	if err != nil {
+		return nil, "", err
+	}
+	var hash string
+	if key != nil {
+		hash = key.KeyHash
+	}
+
+	a.mu.Lock()
+	// Prefix không tồn tại cũng được cache → dọn entry hết hạn khi phình to
+	if len(a.cache) >= 100_000 {
+		for k, c := range a.cache {
+			if !now.Before(c.expiresAt) {
+				delete(a.cache, k)
+			}
+		}
+	}
+	a.cache[prefix] = cached{key: key, hash: hash, expiresAt: now.Add(a.ttl)}
+	a.mu.Unlock()
+	return key, hash, nil
+}
+
+// Forget — xóa key khỏi cache (sau khi thu hồi / rotate)
+func (a *Authenticator) Forget(prefix string) {
+	a.mu.Lock()
+	delete(a.cache, prefix)
+	a.mu.Unlock()
+}
+
+// Subscribe — xóa cache khi nhận event Revoked, trả về hàm hủy đăng ký
+func (a *Authenticator) Subscribe(bus *event.Bus) func() {
+	return event.Subscribe(bus, "apikey.authenticator", func(ctx context.Context, e Revoked) error {
+		a.Forget(e.Prefix)
+		return nil
+	})
+}
@@ -0,0 +1,83 @@
+package apikey
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"golang-base/internal/auth"
+	"golang-base/internal/model"
+	"golang-base/pkg/secure"
+)
+
+func TestGenerate(t *testing.T) {
+	raw, prefix, hash, err := Generate()
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if !strings.HasPrefix(raw, KeyPrefix+"_"+prefix+"_") || len(prefix) != 8 {
+		t.Fatalf("unexpected key format: %s (prefix %s)", raw, prefix)
+	}
+	// DB chỉ lưu SHA-256 của cả key, không chứa secret
+	if hash != secure.HashToken(raw) || len(hash) != 64 || strings.Contains(hash, raw) {
+		t.Fatalf("unexpected hash %s", hash)
+	}
+
+	other, otherPrefix, otherHash, _ := Generate()
+	if other == raw || otherPrefix == prefix || otherHash == hash {
+		t.Fatal("two generated keys must differ")
+	}
+}
+
+func TestSplit(t *testing.T) {
+	raw, prefix, _, _ := Generate()
+	if got, ok := Split(raw); !ok || got != prefix {
+		t.Fatalf("split %s: got %q, %v", raw, got, ok)
+	}
+
+	for _, bad := range []string{
+		"",
+		"Bearer " + raw,
+		"xyz_0123abcd_secret",   // sai tiền tố
+		"gbk_0123abc_secret",    // prefix 7 ký tự
+		"gbk_0123abcd_",         // thiếu secret
+		"gbk_0123abcd",          // thiếu phần secret
+		"gbk_0123abcdef_secret", // prefix quá dài
+	} {
+		if _, ok := Split(bad); ok {
+			t.Fatalf("%q must be rejected", bad)
+		}
+	}
+}
+
+func TestPrincipalScopes(t *testing.T) {
+	key := &model.APIKey{ID: 7, Name: "worker", Prefix: "0123abcd", Scopes: []string{"catalogue.view"}}
+	p := Principal(key)
+	if p.ID != "7" || p.Kind != auth.KindAPIKey {
+		t.Fatalf("unexpected principal: %+v", p)
+	}
+	if !p.Can("catalogue.view") || p.Can("catalogue.update") {
+		t.Fatalf("principal must only have its scopes: %+v", p.Permissions)
+	}
+}
+
+func TestKeyActive(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	past, future := now.Add(-time.Minute), now.Add(time.Minute)
+
+	cases := []struct {
+		name string
+		key  model.APIKey
+		want bool
+	}{
+		{"no expiry", model.APIKey{}, true},
+		{"not expired", model.APIKey{ExpiresAt: &future}, true},
+		{"expired", model.APIKey{ExpiresAt: &past}, false},
+		{"revoked", model.APIKey{RevokedAt: &past}, false},
+	}
+	for _, tc := range cases {
+		if got := tc.key.Active(now); got != tc.want {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// ============================================================
+// POLICY — phân quyền theo từng record, BaseService tự gọi trước mỗi thao tác
+//
+//	CanView    — FindById
+//	CanCreate  — Create / BulkCreate / Upsert (chưa có record trùng)
+//	CanUpdate  — Update / BulkUpdate / Upsert (đã có record trùng) — current = record trong DB TRƯỚC khi sửa
+//	CanDelete  — Delete / BulkDelete / BulkRestore
+//	Scope      — Paginate: chỉ trả về record principal được xem
+//
+// p = nil khi request chưa xác thực → policy tự quyết (cho xem public hay chặn)
+// Bulk: từ chối 1 record = từ chối cả batch (không xử lý dở dang)
+// ============================================================
+type Policy[T any] interface {
+	CanView(ctx context.Context, p *Principal, record *T) bool
+	CanCreate(ctx context.Context, p *Principal, payload *T) bool
+
+	// changes: payload của Update, nil với BulkUpdate (thay đổi nằm ở map fields)
+	CanUpdate(ctx context.Context, p *Principal, current *T, changes *T) bool
+	CanDelete(ctx context.Context, p *Principal, record *T) bool
+
+	// Scope — điều kiện thêm vào query listing
+	// VD: chỉ thấy bài viết của mình
+	//
+	//	return func(db *gorm.DB) *gorm.DB { return db.Where("user_id = ?", p.ID) }
+	//
+	// nil = KHÔNG giới hạn: Paginate trả về mọi record (AllowAll mặc định trả nil)
+	// → Paginate không hỏi CanView từng record: policy chặn CanView thì phải override Scope cho khớp
+	// → không cho xem gì: trả func(db) { return db.Where("1 = 0") }, đừng trả nil
+	Scope(ctx context.Context, p *Principal) func(*gorm.DB) *gorm.DB
+}
+
+// AllowAll — policy cho phép mọi thứ, embed vào policy của module rồi override method cần chặn
+//
+//	type PostPolicy struct {
+//		auth.AllowAll[model.Post]
+//	}
+//	func (PostPolicy) CanDelete(ctx context.Context, p *auth.Principal, post *model.Post) bool {
+//		return p.HasRole("admin")
+//	}
+type AllowAll[T any] struct{}
+
+func (AllowAll[T]) CanView(ctx context.Context, p *Principal, record *T) bool    { return true }
+func (AllowAll[T]) CanCreate(ctx context.Context, p *Principal, payload *T) bool { return true }
+func (AllowAll[T]) CanUpdate(ctx context.Context, p *Principal, current *T, changes *T) bool {
+	return true
+}
+func (AllowAll[T]) CanDelete(ctx context.Context, p *Principal, record *T) bool { return true }
+func (AllowAll[T]) Scope(ctx context.Context, p *Principal) func(*gorm.DB) *gorm.DB {
+	return nil
+}
@@ -0,0 +1,75 @@
+// Package auth — danh tính của người gọi (Principal) + phân quyền theo record (Policy)
+//
+// Luồng:
+//
+//	AuthMiddleware xác thực request → auth.WithPrincipal(ctx, principal)
+//	→ Controller truyền c.Request.Context() xuống Service
+//	→ BaseService đọc auth.PrincipalFrom(ctx) → hỏi Policy trước mỗi thao tác
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrUnauthenticated — chưa xác thực (không có principal) mà thao tác yêu cầu đăng nhập
+	ErrUnauthenticated = errors.New("unauthenticated")
+
+	// ErrForbidden — đã xác thực nhưng không có quyền với record này
+	ErrForbidden = errors.New("forbidden")
+)
+
+// loại principal
+const (
+	KindUser    = "user"    // người dùng đăng nhập
+	KindAPIKey  = "api_key" // gọi bằng API key
+	KindService = "service" // tác vụ nội bộ (cronjob, worker)
+)
+
+// Principal — ai đang thực hiện thao tác
+type Principal struct {
+	ID          string         // subject: user id, api key id...
+	Kind        string         // KindUser / KindAPIKey / KindService
+	Roles       []string       // VD: ["admin"]
+	Permissions []string       // VD: ["user_catalogues.update"]
+	Attributes  map[string]any // thông tin thêm (tenant, catalogue id...)
+}
+
+// HasRole — principal có role này không
+func (p *Principal) HasRole(role string) bool {
+	return p != nil && slices.Contains(p.Roles, role)
+}
+
+// Wildcard — permission bao trùm mọi quyền (super admin)
+const Wildcard = "*"
+
+// Can — principal có permission này không
+// Hỗ trợ wildcard: "*" → mọi quyền, "catalogue.*" → mọi quyền bắt đầu bằng "catalogue."
+func (p *Principal) Can(permission string) bool {
+	if p == nil {
+		return false
+	}
+	return slices.ContainsFunc(p.Permissions, func(granted string) bool {
+		if granted == permission || granted == Wildcard {
+			return true
+		}
+		prefix, ok := strings.CutSuffix(granted, ".*")
+		return ok && strings.HasPrefix(permission, prefix+".")
+	})
+}
+
+type principalKey struct{}
+
+// WithPrincipal — gắn principal vào ctx
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFrom — lấy principal từ ctx, ok=false nếu request chưa xác thực
+func PrincipalFrom(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
@@ -0,0 +1,234 @@
+// Package rbac — phân quyền theo nhóm: user → user_catalogue → permissions
+//
+//	users.user_catalogue_id ──► user_catalogues (role) ──► user_catalogue_permissions ──► permissions
+//
+// Resolver tra permission của user, cache trong memory:
+// → user → catalogue id       (xóa khi user đổi nhóm / bị xóa)
+// → catalogue → role + quyền  (xóa khi catalogue đổi / gán lại permission)
+//
+// Invalidate qua event bus (Subscribe) → chỉ áp dụng trong 1 process
+// Chạy nhiều instance → TTL giới hạn thời gian instance khác dùng cache cũ
+package rbac
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"golang-base/internal/event"
+	"golang-base/internal/model"
+	ur "golang-base/internal/repository/user"
+
+	"gorm.io/gorm"
+)
+
+// DefaultTTL — thời gian sống tối đa của 1 entry cache
+const DefaultTTL = 5 * time.Minute
+
+// PermissionsChanged — publish sau khi gán lại permission cho catalogue
+type PermissionsChanged struct {
+	CatalogueID uint
+}
+
+// Grants — quyền của 1 user
+type Grants struct {
+	CatalogueID uint     // 0 = chưa phân nhóm
+	Role        string   // role của catalogue, VD: "admin"
+	Permissions []string // đã sort theo tên
+
+	RequireTwoFactor bool // nhóm bắt buộc bật 2FA
+}
+
+type entry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+type catalogueGrants struct {
+	role             string
+	permissions      []string
+	requireTwoFactor bool
+}
+
+// Resolver — tra quyền của user, an toàn khi dùng đồng thời
+type Resolver struct {
+	db          *gorm.DB
+	ttl         time.Duration
+	now         func() time.Time
+	mu          sync.RWMutex
+	users       map[uint]entry[uint] // user id → catalogue id
+	catalogues  map[uint]entry[catalogueGrants]
+	permissions *ur.PermissionRepository
+}
+
+func NewResolver(db *gorm.DB, ttl time.Duration) *Resolver {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	return &Resolver{
+		db:          db,
+		ttl:         ttl,
+		now:         time.Now,
+		users:       make(map[uint]entry[uint]),
+		catalogues:  make(map[uint]entry[catalogueGrants]),
+		permissions: ur.NewPermissionRepository(db),
+	}
+}
+
+// Resolve — role + permission của user (user không tồn tại → Grants rỗng)
+func (r *Resolver) Resolve(ctx context.Context, userID uint) (*Grants, error) {
+	catalogueID, err := r.catalogueOf(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if catalogueID == 0 {
+		return &Grants{Permissions: []string{}}, nil
+	}
+
+	grants, err := r.grantsOf(ctx, catalogueID)
+	if err != nil {
+		return nil, err
+	}
+	return &Grants{
+		CatalogueID: catalogueID,
+		Role:        grants.role,
+		Permissions: slices.Clone(grants.permissions),
+
+		RequireTwoFactor: grants.requireTwoFactor,
+	}, nil
+}
+
+func (r *Resolver) catalogueOf(ctx context.Context, userID uint) (uint, error) {
+	if v, ok := lookup(r, r.users, userID); ok {
+		return v, nil
+	}
+
+	var user model.User
+	err := r.db.WithContext(ctx).Select("id", "user_catalogue_id").Where("id = ?", userID).Limit(1).Find(&user).Error
+	if err != nil {
+		return 0, fmt.Errorf("load user catalogue failed: %w", err)
+	}
+	var catalogueID uint
+	if user.UserCatalogueID != nil {
+		catalogueID = *user.UserCatalogueID
+	}
+
+	store(r, r.users, userID, catalogueID)
+	return catalogueID, nil
+}
+
+func (r *Resolver) grantsOf(ctx context.Context, catalogueID uint) (catalogueGrants, error) {
+	if v, ok := lookup(r, r.catalogues, catalogueID); ok {
+		return v, nil
+	}
+
+	var catalogue model.UserCatalogue
+	err := r.db.WithContext(ctx).Select("id", "role", "require_two_factor").Where("id = ?", catalogueID).Limit(1).Find(&catalogue).Error
+	if err != nil {
+		return catalogueGrants{}, fmt.Errorf("load catalogue failed: %w", err)
+	}
+	names, err := r.permissions.WithTx(r.db.WithContext(ctx)).NamesByCatalogue(catalogueID)
+	if err != nil {
+		return catalogueGrants{}, err
+	}
+
+	if names == nil {
+		names = []string{}
+	}
+	grants := catalogueGrants{role: catalogue.Role, permissions: names, requireTwoFactor: catalogue.RequireTwoFactor}
+	store(r, r.catalogues, catalogueID, grants)
+	return grants, nil
+}
+
+// ============================================================
+// INVALIDATE
+// ============================================================
+
+// InvalidateUser — user đổi nhóm / bị xóa
+func (r *Resolver) InvalidateUser(userID uint) {
+	r.mu.Lock()
+	delete(r.users, userID)
+	r.mu.Unlock()
+}
+
+// InvalidateCatalogue — catalogue đổi role / permission / bị xóa
+func (r *Resolver) InvalidateCatalogue(catalogueID uint) {
+	r.mu.Lock()
+	delete(r.catalogues, catalogueID)
+	r.mu.Unlock()
+}
+
+// Flush — xóa toàn bộ cache (bulk update không biết ID cụ thể)
+func (r *Resolver) Flush() {
+	r.mu.Lock()
+	clear(r.users)
+	clear(r.catalogues)
+	r.mu.Unlock()
+}
+
+// Subscribe — tự invalidate theo entity event của BaseService + PermissionsChanged
+// Trả về hàm hủy đăng ký
+func (r *Resolver) Subscribe(bus *event.Bus) func() {
+	unsubscribes := []func(){
+		event.Subscribe(bus, "rbac.user_updated", func(ctx context.Context, e event.EntityUpdated[model.User, uint]) error {
+			if e.IsBulk() {
+				r.Flush()
+			} else {
+				r.InvalidateUser(e.ID)
+			}
+			return nil
+		}),
+		event.Subscribe(bus, "rbac.user_deleted", func(ctx context.Context, e event.EntityDeleted[model.User, uint]) error {
+			r.InvalidateUser(e.ID)
+			return nil
+		}),
+		event.Subscribe(bus, "rbac.catalogue_updated", func(ctx context.Context, e event.EntityUpdated[model.UserCatalogue, uint]) error {
+			if e.IsBulk() {
+				r.Flush()
+			} else {
+				r.InvalidateCatalogue(e.ID)
+			}
+			return nil
+		}),
+		event.Subscribe(bus, "rbac.catalogue_upserted", func(ctx context.Context, e event.EntityUpserted[model.UserCatalogue, uint]) error {
+			r.Flush() // ID có thể là zero value
+			return nil
+		}),
+		event.Subscribe(bus, "rbac.catalogue_deleted", func(ctx context.Context, e event.EntityDeleted[model.UserCatalogue, uint]) error {
+			r.Flush() // user trong nhóm bị set NULL (FK ON DELETE SET NULL)
+			return nil
+		}),
+		event.Subscribe(bus, "rbac.catalogue_restored", func(ctx context.Context, e event.EntityRestored[model.UserCatalogue, uint]) error {
+			r.InvalidateCatalogue(e.ID)
+			return nil
+		}),
+		event.Subscribe(bus, "rbac.permissions_changed", func(ctx context.Context, e PermissionsChanged) error {
+			r.InvalidateCatalogue(e.CatalogueID)
+			return nil
+		}),
+	}
+	return func() {
+		for _, unsubscribe := range unsubscribes {
+			unsubscribe()
+		}
+	}
+}
+
+func lookup[V any](r *Resolver, m map[uint]entry[V], key uint) (V, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := m[key]
+	if !ok || !r.now().Before(e.expiresAt) {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+func store[V any](r *Resolver, m map[uint]entry[V], key uint, value V) {
+	r.mu.Lock()
+	m[key] = entry[V]{value: value, expiresAt: r.now().Add(r.ttl)}
+	r.mu.Unlock()
+}
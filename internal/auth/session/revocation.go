@@ -0,0 +1,125 @@
+// Package session — danh sách session bị thu hồi, AuthMiddleware hỏi trước khi tin access token
+//
+// Access token JWT tự hợp lệ tới khi hết hạn → không thu hồi được
+// → token mang claim "sid", mỗi request kiểm tra session đó còn sống không
+//
+// Kiểm tra DB mỗi request quá tốn → cache local:
+// → session còn sống: cache AliveTTL ngắn (mặc định 15s) → instance khác biết bị thu hồi chậm tối đa AliveTTL
+// → session đã thu hồi: cache tới khi mọi access token của nó hết hạn (không bao giờ sống lại)
+// → thu hồi trong cùng process (event Revoked) → cập nhật cache NGAY
+package session
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang-base/internal/event"
+	"golang-base/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// DefaultAliveTTL — thời gian cache kết quả "session còn sống"
+const DefaultAliveTTL = 15 * time.Second
+
+// Revoked — publish khi thu hồi session (đăng xuất, đăng xuất mọi nơi, admin kill, reuse refresh token...)
+type Revoked struct {
+	UserID     uint
+	SessionIDs []string
+}
+
+type entry struct {
+	revoked   bool
+	expiresAt time.Time
+}
+
+// RevocationList — implement token.RevocationChecker, an toàn khi dùng đồng thời
+type RevocationList struct {
+	db         *gorm.DB
+	aliveTTL   time.Duration
+	revokedTTL time.Duration // >= access token TTL
+	now        func() time.Time
+
+	mu    sync.RWMutex
+	cache map[string]entry
+}
+
+// NewRevocationList — revokedTTL nên bằng access token TTL (+ leeway)
+func NewRevocationList(db *gorm.DB, aliveTTL, revokedTTL time.Duration) *RevocationList {
+	if aliveTTL <= 0 {
+		aliveTTL = DefaultAliveTTL
+	}
+	if revokedTTL < aliveTTL {
+		revokedTTL = aliveTTL
+	}
+	return &RevocationList{
+		db:         db,
+		aliveTTL:   aliveTTL,
+		revokedTTL: revokedTTL,
+		now:        time.Now,
+		cache:      make(map[string]entry),
+	}
+}
+
+// IsRevoked — session đã thu hồi / hết hạn / không tồn tại → true
+func (l *RevocationList) IsRevoked(ctx context.Context, sessionID string) (bool, error) {
+	now := l.now()
+
+	l.mu.RLock()
+	e, ok := l.cache[sessionID]
+	l.mu.RUnlock()
+	if ok && now.Before(e.expiresAt) {
+		return e.revoked, nil
+	}
+
+	var sessions []model.Session
+	err := l.db.WithContext(ctx).
+		Select("id", "revoked_at", "expires_at").
+		Where("id = ?", sessionID).
+		Limit(1).
+		Find(&sessions).Error
+	if err != nil {
+		return false, fmt.Errorf("check session failed: %w", err)
+	}
+	revoked := len(sessions) == 0 || sessions[0].RevokedAt != nil || !sessions[0].ExpiresAt.After(now)
+
+	l.store(sessionID, revoked, now)
+	return revoked, nil
+}
+
+// MarkRevoked — đánh dấu thu hồi trong cache local (không ghi DB)
+func (l *RevocationList) MarkRevoked(sessionIDs ...string) {
+	now := l.now()
+	for _, id := range sessionIDs {
+		l.store(id, true, now)
+	}
+}
+
+// Subscribe — cập nhật cache khi nhận event Revoked, trả về hàm hủy đăng ký
+func (l *RevocationList) Subscribe(bus *event.Bus) func() {
+	return event.Subscribe(bus, "session.revocation_list", func(ctx context.Context, e Revoked) error {
+		l.MarkRevoked(e.SessionIDs...)
+		return nil
+	})
+}
+
+func (l *RevocationList) store(id string, revoked bool, now time.Time) {
+	ttl := l.aliveTTL
+	if revoked {
+		ttl = l.revokedTTL
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// Dọn entry hết hạn khi cache phình to → không rò bộ nhớ theo số session
+	if len(l.cache) >= 100_000 {
+		for k, e := range l.cache {
+			if !now.Before(e.expiresAt) {
+				delete(l.cache, k)
+			}
+		}
+	}
+	l.cache[id] = entry{revoked: revoked, expiresAt: now.Add(ttl)}
+}
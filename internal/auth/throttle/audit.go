@@ -0,0 +1,80 @@
+package throttle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"golang-base/internal/event"
+	"golang-base/internal/model"
+)
+
+// AuditableType — auditable_type của sự kiện đăng nhập trong audit_logs
+const AuditableType = "login"
+
+// AuditTrail — ghi sự kiện của Throttler ra log + bảng audit_logs (async, không làm chậm request)
+// Trả về hàm hủy đăng ký
+func AuditTrail(bus *event.Bus, db *gorm.DB, log *zap.Logger) func() {
+	unsubscribes := []func(){
+		event.SubscribeAsync(bus, "throttle.audit_failed", func(ctx context.Context, e LoginFailed) error {
+			log.Warn("login failed", zap.String("account", e.Account), zap.String("ip", e.IP), zap.Int64("failures", e.Failures))
+			return writeAudit(ctx, db, model.AuditActionLoginFailed, subject(e.Account, e.IP), map[string]any{
+				"ip":       e.IP,
+				"failures": e.Failures,
+			})
+		}),
+		event.SubscribeAsync(bus, "throttle.audit_locked", func(ctx context.Context, e AccountLocked) error {
+			log.Warn("account locked", zap.String("account", e.Account), zap.String("ip", e.IP), zap.Time("until", e.Until))
+			return writeAudit(ctx, db, model.AuditActionLocked, e.Account, map[string]any{
+				"ip":    e.IP,
+				"until": e.Until.Format(time.RFC3339),
+			})
+		}),
+		event.SubscribeAsync(bus, "throttle.audit_blocked", func(ctx context.Context, e IPBlocked) error {
+			log.Warn("ip blocked", zap.String("ip", e.IP), zap.Time("until", e.Until))
+			return writeAudit(ctx, db, model.AuditActionBlocked, e.IP, map[string]any{
+				"until": e.Until.Format(time.RFC3339),
+			})
+		}),
+		event.SubscribeAsync(bus, "throttle.audit_unlocked", func(ctx context.Context, e AccountUnlocked) error {
+			log.Info("account unlocked", zap.String("account", e.Account), zap.String("by", e.By))
+			return writeAudit(ctx, db, model.AuditActionUnlocked, e.Account, map[string]any{
+				"by": e.By,
+			})
+		}),
+	}
+	return func() {
+		for _, unsubscribe := range unsubscribes {
+			unsubscribe()
+		}
+	}
+}
+
+func writeAudit(ctx context.Context, db *gorm.DB, action, id string, data map[string]any) error {
+	changes, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("marshal audit changes failed: %w", err)
+	}
+	log := model.AuditLog{
+		AuditableType: AuditableType,
+		AuditableID:   id,
+		Action:        action,
+		Changes:       string(changes),
+	}
+	if err := db.WithContext(ctx).Create(&log).Error; err != nil {
+		return fmt.Errorf("write audit log failed: %w", err)
+	}
+	return nil
+}
+
+// subject — tài khoản, rỗng thì lấy IP
+func subject(account, ip string) string {
+	if account != "" {
+		return account
+	}
+	return ip
+}
@@ -0,0 +1,180 @@
+package throttle
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Store — nơi lưu bộ đếm / khóa, mọi key đều có TTL
+// Chạy nhiều instance → dùng RedisStore để các instance thấy chung bộ đếm
+type Store interface {
+	// Incr — tăng bộ đếm, key mới sống window (không gia hạn khi tăng tiếp)
+	Incr(ctx context.Context, key string, window time.Duration) (int64, error)
+	// Get — value + TTL còn lại, không có → "", 0
+	Get(ctx context.Context, key string) (string, time.Duration, error)
+	Set(ctx context.Context, key, value string, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// ============================================================
+// MEMORY STORE — trong process, mất khi restart, không chia sẻ giữa instance
+// Phù hợp: 1 instance, môi trường dev / test
+// ============================================================
+
+type memoryEntry struct {
+	value     string
+	expiresAt time.Time
+}
+
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	now     func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	store := &MemoryStore{entries: make(map[string]memoryEntry), now: time.Now}
+
+	// Cleanup key hết hạn mỗi 5 phút (giống RateLimiter)
+	go func() {
+		ticker := time.NewTicker(5 * time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			store.cleanup()
+		}
+	}()
+
+	return store
+}
+
+func (s *MemoryStore) Incr(ctx context.Context, key string, window time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	e, ok := s.entries[key]
+	if !ok || !now.Before(e.expiresAt) {
+		e = memoryEntry{value: "0", expiresAt: now.Add(window)}
+	}
+	n, _ := strconv.ParseInt(e.value, 10, 64)
+	n++
+	e.value = strconv.FormatInt(n, 10)
+	s.entries[key] = e
+	return n, nil
+}
+
+func (s *MemoryStore) Get(ctx context.Context, key string) (string, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok {
+		return "", 0, nil
+	}
+	ttl := e.expiresAt.Sub(s.now())
+	if ttl <= 0 {
+		delete(s.entries, key)
+		return "", 0, nil
+	}
+	return e.value, ttl, nil
+}
+
+func (s *MemoryStore) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	s.mu.Lock()
+	s.entries[key] = memoryEntry{value: value, expiresAt: s.now().Add(ttl)}
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, keys ...string) error {
+	s.mu.Lock()
+	for _, key := range keys {
+		delete(s.entries, key)
+	}
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *MemoryStore) cleanup() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	for key, e := range s.entries {
+		if !now.Before(e.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+// ============================================================
+// REDIS STORE — chia sẻ giữa các instance
+// Key: "{prefix}:{key}", VD: throttle:fail:acct:an@x.com
+// ============================================================
+
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewRedisStore(client *redis.Client, prefix string) *RedisStore {
+	if prefix == "" {
+		prefix = "throttle"
+	}
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+func (s *RedisStore) Incr(ctx context.Context, key string, window time.Duration) (int64, error) {
+	key = s.key(key)
+	pipe := s.client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.ExpireNX(ctx, key, window) // chỉ đặt TTL lần đầu → cửa sổ cố định
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("redis incr failed: %w", err)
+	}
+	return incr.Val(), nil
+}
+
+func (s *RedisStore) Get(ctx context.Context, key string) (string, time.Duration, error) {
+	key = s.key(key)
+	pipe := s.client.Pipeline()
+	get := pipe.Get(ctx, key)
+	ttl := pipe.PTTL(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return "", 0, fmt.Errorf("redis get failed: %w", err)
+	}
+	if get.Err() == redis.Nil || ttl.Val() <= 0 {
+		return "", 0, nil
+	}
+	return get.Val(), ttl.Val(), nil
+}
+
+func (s *RedisStore) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	if err := s.client.Set(ctx, s.key(key), value, ttl).Err(); err != nil {
+		return fmt.Errorf("redis set failed: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisStore) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = s.key(key)
+	}
+	if err := s.client.Del(ctx, prefixed...).Err(); err != nil {
+		return fmt.Errorf("redis del failed: %w", err)
+	}
+	return nil
+}
+
+func (s *RedisStore) key(key string) string {
+	return s.prefix + ":" + key
+}
@@ -0,0 +1,282 @@
+// Package throttle — chống dò mật khẩu trên endpoint xác thực
+//
+// RateLimiter toàn cục chỉ đếm theo IP → không chặn được:
+// → credential stuffing: nhiều IP, mỗi IP thử vài lần
+// → tấn công nhắm 1 tài khoản từ nhiều IP
+//
+// Throttler đếm lần đăng nhập sai theo CẢ tài khoản lẫn IP:
+//
+//	sai <= FreeAttempts              → không chặn
+//	sai > FreeAttempts (tài khoản)   → phải chờ BaseDelay * 2^(n-FreeAttempts-1) (tối đa MaxDelay) mới thử tiếp
+//	sai >= MaxAttempts (tài khoản)   → khóa LockoutDuration, event AccountLocked kèm unlock token
+//	sai >= IPMaxAttempts (IP)        → chặn IP LockoutDuration, event IPBlocked
+//
+// Đăng nhập đúng → xóa bộ đếm của tài khoản.
+// IP không bị delay (nhiều user chung NAT), chỉ bị chặn khi vượt ngưỡng cao hơn hẳn.
+// Tài khoản không tồn tại cũng bị đếm / khóa như thường → không dò được email
+package throttle
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang-base/internal/event"
+	"golang-base/pkg/secure"
+)
+
+var (
+	// ErrTooManyAttempts — đang trong thời gian chờ (progressive delay)
+	ErrTooManyAttempts = errors.New("too many failed attempts, please try again later")
+
+	// ErrLocked — tài khoản / IP bị khóa tạm thời
+	ErrLocked = errors.New("temporarily locked due to too many failed attempts")
+
+	// ErrInvalidUnlockToken — unlock token sai / hết hạn / đã dùng
+	ErrInvalidUnlockToken = errors.New("invalid or expired unlock token")
+)
+
+// RetryError — bị chặn, RetryAfter: bao lâu nữa được thử lại (header Retry-After)
+type RetryError struct {
+	Err        error // ErrTooManyAttempts | ErrLocked
+	RetryAfter time.Duration
+}
+
+func (e *RetryError) Error() string { return e.Err.Error() }
+func (e *RetryError) Unwrap() error { return e.Err }
+
+// ============================================================
+// EVENT — AuditTrail ghi log + audit_logs, module mail gửi link mở khóa
+// ============================================================
+
+// LoginFailed — 1 lần xác thực sai
+type LoginFailed struct {
+	Account  string // rỗng = chỉ đếm theo IP (VD: nhập sai mã 2FA)
+	IP       string
+	Failures int64 // số lần sai của tài khoản (hoặc IP) trong cửa sổ
+}
+
+// AccountLocked — tài khoản bị khóa tạm thời
+// UnlockToken CHỈ có ở đây → gửi link mở khóa cho chủ tài khoản
+type AccountLocked struct {
+	Account     string
+	IP          string
+	Until       time.Time
+	UnlockToken string
+}
+
+// IPBlocked — IP bị chặn tạm thời
+type IPBlocked struct {
+	IP    string
+	Until time.Time
+}
+
+// AccountUnlocked — mở khóa bằng link (By rỗng) hoặc admin mở (By = id admin)
+type AccountUnlocked struct {
+	Account string
+	By      string
+}
+
+// Config — ngưỡng chặn, để 0 → giá trị mặc định
+type Config struct {
+	FreeAttempts    int           // số lần sai chưa bị delay (mặc định 3)
+	MaxAttempts     int           // số lần sai / tài khoản trước khi khóa (mặc định 5)
+	IPMaxAttempts   int           // số lần sai / IP trước khi chặn (mặc định 50)
+	Window          time.Duration // cửa sổ đếm (mặc định 15 phút)
+	LockoutDuration time.Duration // thời gian khóa (mặc định 15 phút)
+	BaseDelay       time.Duration // delay lần đầu (mặc định 1 giây)
+	MaxDelay        time.Duration // delay tối đa (mặc định 1 phút)
+}
+
+func (c Config) withDefaults() Config {
+	if c.FreeAttempts <= 0 {
+		c.FreeAttempts = 3
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 5
+	}
+	if c.IPMaxAttempts <= 0 {
+		c.IPMaxAttempts = 50
+	}
+	if c.Window <= 0 {
+		c.Window = 15 * time.Minute
+	}
+	if c.LockoutDuration <= 0 {
+		c.LockoutDuration = 15 * time.Minute
+	}
+	if c.BaseDelay <= 0 {
+		c.BaseDelay = time.Second
+	}
+	if c.MaxDelay <= 0 {
+		c.MaxDelay = time.Minute
+	}
+	return c
+}
+
+// Throttler — an toàn khi dùng đồng thời (trạng thái nằm trong Store)
+type Throttler struct {
+	store Store
+	cfg   Config
+	bus   *event.Bus
+	now   func() time.Time
+}
+
+func New(store Store, cfg Config, bus *event.Bus) *Throttler {
+	return &Throttler{store: store, cfg: cfg.withDefaults(), bus: bus, now: time.Now}
+}
+
+// Check — gọi TRƯỚC khi kiểm tra mật khẩu, bị chặn → *RetryError
+func (t *Throttler) Check(ctx context.Context, account, ip string) error {
+	account = normalize(account)
+	keys := []struct {
+		key string
+		err error
+	}{
+		{"lock:ip:" + ip, ErrLocked},
+		{"lock:acct:" + account, ErrLocked},
+		{"delay:acct:" + account, ErrTooManyAttempts},
+	}
+	for _, k := range keys {
+		if strings.HasSuffix(k.key, ":") {
+			continue // account / ip rỗng
+		}
+		_, ttl, err := t.store.Get(ctx, k.key)
+		if err != nil {
+			return err
+		}
+		if ttl > 0 {
+			return &RetryError{Err: k.err, RetryAfter: ttl}
+		}
+	}
+	return nil
+}
+
+// Fail — ghi nhận 1 lần sai, account rỗng → chỉ đếm theo IP
+func (t *Throttler) Fail(ctx context.Context, account, ip string) error {
+	account = normalize(account)
+	now := t.now()
+
+	var failures int64
+	if ip != "" {
+		n, err := t.store.Incr(ctx, "fail:ip:"+ip, t.cfg.Window)
+		if err != nil {
+			return err
+		}
+		failures = n
+		if n >= int64(t.cfg.IPMaxAttempts) {
+			if err := t.store.Set(ctx, "lock:ip:"+ip, "1", t.cfg.LockoutDuration); err != nil {
+				return err
+			}
+			event.Publish(ctx, t.bus, IPBlocked{IP: ip, Until: now.Add(t.cfg.LockoutDuration)})
+		}
+	}
+
+	if account != "" {
+		n, err := t.store.Incr(ctx, "fail:acct:"+account, t.cfg.Window)
+		if err != nil {
+			return err
+		}
+		failures = n
+		if n >= int64(t.cfg.MaxAttempts) {
+			if err := t.lock(ctx, account, ip, now); err != nil {
+				return err
+			}
+		} else if err := t.delay(ctx, "delay:acct:"+account, n); err != nil {
+			return err
+		}
+	}
+
+	event.Publish(ctx, t.bus, LoginFailed{Account: account, IP: ip, Failures: failures})
+	return nil
+}
+
+// Succeed — đăng nhập đúng → xóa bộ đếm + delay của tài khoản (bộ đếm IP giữ nguyên)
+func (t *Throttler) Succeed(ctx context.Context, account string) error {
+	account = normalize(account)
+	return t.store.Delete(ctx, "fail:acct:"+account, "delay:acct:"+account)
+}
+
+// Unlock — admin mở khóa tài khoản, by: id người mở
+func (t *Throttler) Unlock(ctx context.Context, account, by string) error {
+	account = normalize(account)
+	if err := t.clear(ctx, account); err != nil {
+		return err
+	}
+	event.Publish(ctx, t.bus, AccountUnlocked{Account: account, By: by})
+	return nil
+}
+
+// Redeem — chủ tài khoản mở khóa bằng token trong link, trả về tài khoản được mở
+func (t *Throttler) Redeem(ctx context.Context, token string) (string, error) {
+	if token == "" {
+		return "", ErrInvalidUnlockToken
+	}
+	key := "unlock:" + secure.HashToken(token)
+	account, ttl, err := t.store.Get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	if ttl <= 0 || account == "" {
+		return "", ErrInvalidUnlockToken
+	}
+	if err := t.store.Delete(ctx, key); err != nil {
+		return "", err
+	}
+	if err := t.clear(ctx, account); err != nil {
+		return "", err
+	}
+	event.Publish(ctx, t.bus, AccountUnlocked{Account: account})
+	return account, nil
+}
+
+// LockedFor — tài khoản còn bị khóa bao lâu, 0 = không khóa
+func (t *Throttler) LockedFor(ctx context.Context, account string) (time.Duration, error) {
+	_, ttl, err := t.store.Get(ctx, "lock:acct:"+normalize(account))
+	return ttl, err
+}
+
+func (t *Throttler) lock(ctx context.Context, account, ip string, now time.Time) error {
+	if err := t.store.Set(ctx, "lock:acct:"+account, "1", t.cfg.LockoutDuration); err != nil {
+		return err
+	}
+	token, err := secure.RandomToken(32)
+	if err != nil {
+		return err
+	}
+	// token sống bằng thời gian khóa, hết khóa thì link cũng vô nghĩa
+	if err := t.store.Set(ctx, "unlock:"+secure.HashToken(token), account, t.cfg.LockoutDuration); err != nil {
+		return err
+	}
+	event.Publish(ctx, t.bus, AccountLocked{
+		Account:     account,
+		IP:          ip,
+		Until:       now.Add(t.cfg.LockoutDuration),
+		UnlockToken: token,
+	})
+	return nil
+}
+
+// delay — lần sai thứ n vượt FreeAttempts → chờ BaseDelay * 2^(n-FreeAttempts-1)
+func (t *Throttler) delay(ctx context.Context, key string, n int64) error {
+	over := n - int64(t.cfg.FreeAttempts)
+	if over <= 0 {
+		return nil
+	}
+	d := time.Duration(float64(t.cfg.BaseDelay) * math.Pow(2, float64(over-1)))
+	if d <= 0 || d > t.cfg.MaxDelay {
+		d = t.cfg.MaxDelay
+	}
+	return t.store.Set(ctx, key, strconv.FormatInt(n, 10), d)
+}
+
+func (t *Throttler) clear(ctx context.Context, account string) error {
+	return t.store.Delete(ctx, "lock:acct:"+account, "fail:acct:"+account, "delay:acct:"+account)
+}
+
+// normalize — email không phân biệt hoa thường / khoảng trắng
+func normalize(account string) string {
+	return strings.ToLower(strings.TrimSpace(account))
+}
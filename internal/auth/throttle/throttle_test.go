@@ -0,0 +1,189 @@
+package throttle
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"golang-base/internal/event"
+)
+
+// clock — đồng hồ giả dùng chung cho Throttler + MemoryStore
+type clock struct{ now time.Time }
+
+func (c *clock) Now() time.Time          { return c.now }
+func (c *clock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestThrottler(t *testing.T, cfg Config) (*Throttler, *clock, *event.Bus) {
+	t.Helper()
+	clk := &clock{now: time.Unix(1_700_000_000, 0)}
+	store := NewMemoryStore()
+	store.now = clk.Now
+	bus := event.NewBus(zap.NewNop())
+	th := New(store, cfg, bus)
+	th.now = clk.Now
+	return th, clk, bus
+}
+
+// retryAfter — Check bị chặn với lỗi want, trả về RetryAfter
+func retryAfter(t *testing.T, err, want error) time.Duration {
+	t.Helper()
+	var retry *RetryError
+	if !errors.As(err, &retry) || !errors.Is(err, want) {
+		t.Fatalf("expected RetryError(%v), got %v", want, err)
+	}
+	return retry.RetryAfter
+}
+
+func TestProgressiveDelay(t *testing.T) {
+	ctx := context.Background()
+	th, clk, _ := newTestThrottler(t, Config{FreeAttempts: 3, MaxAttempts: 10, BaseDelay: time.Second, MaxDelay: 4 * time.Second})
+
+	for i := 0; i < 3; i++ {
+		if err := th.Fail(ctx, "an@example.com", "1.1.1.1"); err != nil {
+			t.Fatalf("fail: %v", err)
+		}
+	}
+	if err := th.Check(ctx, "an@example.com", "1.1.1.1"); err != nil {
+		t.Fatalf("free attempts must not be delayed: %v", err)
+	}
+
+	// Lần sai thứ 4, 5, 6, 7 → chờ 1s, 2s, 4s, 4s (MaxDelay)
+	for _, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second} {
+		_ = th.Fail(ctx, "an@example.com", "1.1.1.1")
+		if got := retryAfter(t, th.Check(ctx, "An@Example.com ", "2.2.2.2"), ErrTooManyAttempts); got != want {
+			t.Fatalf("expected Retry-After %v, got %v", want, got)
+		}
+		clk.Advance(want)
+		if err := th.Check(ctx, "an@example.com", "1.1.1.1"); err != nil {
+			t.Fatalf("delay must expire after %v: %v", want, err)
+		}
+	}
+
+	// Đăng nhập đúng → xóa bộ đếm, lần sai kế tiếp lại được miễn phí
+	_ = th.Succeed(ctx, "an@example.com")
+	_ = th.Fail(ctx, "an@example.com", "1.1.1.1")
+	if err := th.Check(ctx, "an@example.com", "1.1.1.1"); err != nil {
+		t.Fatalf("counter must reset after success: %v", err)
+	}
+}
+
+func TestCountingWindow(t *testing.T) {
+	ctx := context.Background()
+	th, clk, _ := newTestThrottler(t, Config{FreeAttempts: 3, MaxAttempts: 4, Window: 10 * time.Minute})
+
+	// 3 lần sai rồi chờ hết cửa sổ → bộ đếm về 0, lần sai thứ 4 không khóa
+	for i := 0; i < 3; i++ {
+		_ = th.Fail(ctx, "an@example.com", "1.1.1.1")
+	}
+	clk.Advance(10 * time.Minute)
+	_ = th.Fail(ctx, "an@example.com", "1.1.1.1")
+	if err := th.Check(ctx, "an@example.com", "1.1.1.1"); err != nil {
+		t.Fatalf("failures outside the window must not count: %v", err)
+	}
+}
+
+func TestLockAndUnlockToken(t *testing.T) {
+	ctx := context.Background()
+	th, clk, bus := newTestThrottler(t, Config{FreeAttempts: 10, MaxAttempts: 3, LockoutDuration: 15 * time.Minute})
+
+	var locked []AccountLocked
+	event.Subscribe(bus, "test.locked", func(_ context.Context, e AccountLocked) error {
+		locked = append(locked, e)
+		return nil
+	})
+
+	for i := 0; i < 3; i++ {
+		_ = th.Fail(ctx, "an@example.com", "1.1.1.1")
+	}
+	if len(locked) != 1 || locked[0].UnlockToken == "" || locked[0].Account != "an@example.com" {
+		t.Fatalf("expected 1 AccountLocked event with unlock token, got %+v", locked)
+	}
+
+	// Khóa theo tài khoản → IP khác cũng bị chặn, Retry-After = thời gian khóa còn lại
+	clk.Advance(5 * time.Minute)
+	if got := retryAfter(t, th.Check(ctx, "an@example.com", "9.9.9.9"), ErrLocked); got != 10*time.Minute {
+		t.Fatalf("expected Retry-After 10m, got %v", got)
+	}
+	if ttl, _ := th.LockedFor(ctx, "AN@example.com"); ttl != 10*time.Minute {
+		t.Fatalf("LockedFor: expected 10m, got %v", ttl)
+	}
+	// Tài khoản khác cùng IP không bị ảnh hưởng
+	if err := th.Check(ctx, "binh@example.com", "1.1.1.1"); err != nil {
+		t.Fatalf("other account must not be locked: %v", err)
+	}
+
+	if _, err := th.Redeem(ctx, "wrong-token"); !errors.Is(err, ErrInvalidUnlockToken) {
+		t.Fatalf("wrong token: expected ErrInvalidUnlockToken, got %v", err)
+	}
+	account, err := th.Redeem(ctx, locked[0].UnlockToken)
+	if err != nil || account != "an@example.com" {
+		t.Fatalf("redeem: got %q, %v", account, err)
+	}
+	if err := th.Check(ctx, "an@example.com", "1.1.1.1"); err != nil {
+		t.Fatalf("account must be unlocked: %v", err)
+	}
+	// Token chỉ dùng 1 lần
+	if _, err := th.Redeem(ctx, locked[0].UnlockToken); !errors.Is(err, ErrInvalidUnlockToken) {
+		t.Fatalf("reused token: expected ErrInvalidUnlockToken, got %v", err)
+	}
+}
+
+func TestUnlockTokenExpiresWithLock(t *testing.T) {
+	ctx := context.Background()
+	th, clk, bus := newTestThrottler(t, Config{MaxAttempts: 1, LockoutDuration: time.Minute})
+
+	var token string
+	event.Subscribe(bus, "test.locked", func(_ context.Context, e AccountLocked) error {
+		token = e.UnlockToken
+		return nil
+	})
+	_ = th.Fail(ctx, "an@example.com", "1.1.1.1")
+
+	clk.Advance(time.Minute)
+	if err := th.Check(ctx, "an@example.com", "1.1.1.1"); err != nil {
+		t.Fatalf("lock must expire: %v", err)
+	}
+	if _, err := th.Redeem(ctx, token); !errors.Is(err, ErrInvalidUnlockToken) {
+		t.Fatalf("expired token: expected ErrInvalidUnlockToken, got %v", err)
+	}
+}
+
+// Bước 2FA chưa biết email → Fail / Check với account rỗng
+// Chỉ bộ đếm của IP đó tăng: không có bucket chung "" cho mọi user, không delay, không khóa tài khoản nào
+func TestIPOnlyFailures(t *testing.T) {
+	ctx := context.Background()
+	th, _, bus := newTestThrottler(t, Config{FreeAttempts: 1, MaxAttempts: 2, IPMaxAttempts: 20})
+
+	var locked int
+	event.Subscribe(bus, "test.locked", func(_ context.Context, e AccountLocked) error {
+		locked++
+		return nil
+	})
+
+	for i := 0; i < 19; i++ {
+		_ = th.Fail(ctx, "", "10.0.0.1")
+	}
+	if locked != 0 {
+		t.Fatalf("IP-only failures must never lock an account, got %d locks", locked)
+	}
+	// Dưới IPMaxAttempts → user khác sau cùng NAT vẫn đăng nhập / verify được
+	for _, account := range []string{"", "an@example.com", "binh@example.com"} {
+		if err := th.Check(ctx, account, "10.0.0.1"); err != nil {
+			t.Fatalf("check %q behind NAT: %v", account, err)
+		}
+	}
+
+	// Vượt IPMaxAttempts → chỉ chặn IP đó, IP khác không ảnh hưởng
+	_ = th.Fail(ctx, "", "10.0.0.1")
+	retryAfter(t, th.Check(ctx, "", "10.0.0.1"), ErrLocked)
+	if err := th.Check(ctx, "", "10.0.0.2"); err != nil {
+		t.Fatalf("other IP must not be blocked: %v", err)
+	}
+	if err := th.Check(ctx, "an@example.com", "10.0.0.2"); err != nil {
+		t.Fatalf("account must not be locked by IP-only failures: %v", err)
+	}
+}
@@ -0,0 +1,332 @@
+// Package token — ký / xác thực access token JWT
+//
+// Thuật toán:
+// → HS256: ký + verify bằng secret dùng chung (1 service)
+// → RS256 / EdDSA: ký bằng private key, verify bằng public key
+// → service khác verify qua JWKS (GET /.well-known/jwks.json), không cần biết secret
+//
+// Access token ngắn hạn (mặc định 15 phút), refresh token là chuỗi ngẫu nhiên lưu hash trong DB
+// → xem service/auth
+package token
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"golang-base/internal/auth"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Thuật toán hỗ trợ
+const (
+	HS256 = "HS256"
+	RS256 = "RS256"
+	EdDSA = "EdDSA"
+)
+
+// MinSecretLength — secret HS256 ngắn hơn → từ chối khởi động (RFC 7518: key >= độ dài hash)
+const MinSecretLength = 32
+
+var (
+	// ErrInvalidToken — token sai chữ ký / sai định dạng / sai iss, aud
+	ErrInvalidToken = errors.New("invalid token")
+
+	// ErrExpiredToken — token hết hạn, client dùng refresh token để lấy token mới
+	ErrExpiredToken = errors.New("token expired")
+
+	// ErrSigningDisabled — chỉ có public key (service chỉ verify), không ký được
+	ErrSigningDisabled = errors.New("token signing is disabled: no private key")
+)
+
+// Config — cấu hình ký / verify token
+type Config struct {
+	Algorithm      string        // HS256 (mặc định) | RS256 | EdDSA
+	Secret         string        // HS256
+	PrivateKeyFile string        // RS256 / EdDSA: PEM (PKCS#1 / PKCS#8)
+	PublicKeyFile  string        // RS256 / EdDSA: PEM, bỏ trống → lấy từ private key
+	KeyID          string        // header "kid", khớp với JWKS
+	Issuer         string        // claim "iss"
+	Audience       []string      // claim "aud", verify: token phải chứa ít nhất 1 audience
+	AccessTTL      time.Duration // mặc định 15 phút
+	RefreshTTL     time.Duration // mặc định 30 ngày
+	Leeway         time.Duration // lệch giờ cho phép giữa các server
+}
+
+// RevocationChecker — kiểm tra session của token đã bị thu hồi chưa (xem auth/session)
+type RevocationChecker interface {
+	IsRevoked(ctx context.Context, sessionID string) (bool, error)
+}
+
+// Phương thức xác thực trong claim "amr" (RFC 8176)
+const (
+	AMRPassword = "pwd" // mật khẩu
+	AMROTP      = "otp" // mã TOTP / mã dự phòng
+)
+
+// Claims — payload của access token
+type Claims struct {
+	jwt.RegisteredClaims
+	SessionID   string   `json:"sid,omitempty"` // session đăng nhập, rỗng = token không gắn session
+	Kind        string   `json:"kind,omitempty"`
+	AMR         []string `json:"amr,omitempty"` // VD: ["pwd", "otp"] = đã qua 2FA
+	Roles       []string `json:"roles,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+// Principal — chuyển claims thành auth.Principal
+// sub → ID, sid → Attributes["session_id"], amr → Attributes["amr"]
+func (c *Claims) Principal() *auth.Principal {
+	kind := c.Kind
+	if kind == "" {
+		kind = auth.KindUser
+	}
+	p := &auth.Principal{
+		ID:          c.Subject,
+		Kind:        kind,
+		Roles:       c.Roles,
+		Permissions: c.Permissions,
+	}
+	if c.SessionID != "" || len(c.AMR) > 0 {
+		p.Attributes = make(map[string]any, 2)
+	}
+	if c.SessionID != "" {
+		p.Attributes["session_id"] = c.SessionID
+	}
+	if len(c.AMR) > 0 {
+		p.Attributes["amr"] = c.AMR
+	}
+	return p
+}
+
+// Manager — ký + verify access token, dùng chung toàn app (an toàn khi dùng đồng thời)
+type Manager struct {
+	cfg       Config
+	method    jwt.SigningMethod
+	signKey   any // []byte | *rsa.PrivateKey | ed25519.PrivateKey, nil = chỉ verify
+	verifyKey any // []byte | *rsa.PublicKey | ed25519.PublicKey
+	parser    *jwt.Parser
+	now       func() time.Time
+}
+
+func NewManager(cfg Config) (*Manager, error) {
+	if cfg.Algorithm == "" {
+		cfg.Algorithm = HS256
+	}
+	if cfg.AccessTTL <= 0 {
+		cfg.AccessTTL = 15 * time.Minute
+	}
+	if cfg.RefreshTTL <= 0 {
+		cfg.RefreshTTL = 30 * 24 * time.Hour
+	}
+
+	m := &Manager{cfg: cfg, now: time.Now}
+	if err := m.loadKeys(); err != nil {
+		return nil, err
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{cfg.Algorithm}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(cfg.Leeway),
+		jwt.WithTimeFunc(func() time.Time { return m.now() }),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if len(cfg.Audience) > 0 {
+		opts = append(opts, jwt.WithAudience(cfg.Audience...))
+	}
+	m.parser = jwt.NewParser(opts...)
+	return m, nil
+}
+
+// loadKeys — đọc secret / key file theo thuật toán
+func (m *Manager) loadKeys() error {
+	switch m.cfg.Algorithm {
+	case HS256:
+		if len(m.cfg.Secret) < MinSecretLength {
+			return fmt.Errorf("jwt secret must be at least %d bytes", MinSecretLength)
+		}
+		m.method = jwt.SigningMethodHS256
+		m.signKey = []byte(m.cfg.Secret)
+		m.verifyKey = m.signKey
+		return nil
+	case RS256:
+		m.method = jwt.SigningMethodRS256
+	case EdDSA:
+		m.method = jwt.SigningMethodEdDSA
+	default:
+		return fmt.Errorf("unsupported jwt algorithm: %s", m.cfg.Algorithm)
+	}
+
+	if m.cfg.PrivateKeyFile != "" {
+		pem, err := os.ReadFile(m.cfg.PrivateKeyFile)
+		if err != nil {
+			return fmt.Errorf("read jwt private key failed: %w", err)
+		}
+		var key crypto.Signer
+		if m.cfg.Algorithm == RS256 {
+			key, err = jwt.ParseRSAPrivateKeyFromPEM(pem)
+		} else {
+			var k crypto.PrivateKey
+			k, err = jwt.ParseEdPrivateKeyFromPEM(pem)
+			key, _ = k.(crypto.Signer)
+		}
+		if err != nil {
+			return fmt.Errorf("parse jwt private key failed: %w", err)
+		}
+		m.signKey = key
+		m.verifyKey = key.Public()
+	}
+
+	if m.cfg.PublicKeyFile != "" {
+		pem, err := os.ReadFile(m.cfg.PublicKeyFile)
+		if err != nil {
+			return fmt.Errorf("read jwt public key failed: %w", err)
+		}
+		if m.cfg.Algorithm == RS256 {
+			m.verifyKey, err = jwt.ParseRSAPublicKeyFromPEM(pem)
+		} else {
+			m.verifyKey, err = jwt.ParseEdPublicKeyFromPEM(pem)
+		}
+		if err != nil {
+			return fmt.Errorf("parse jwt public key failed: %w", err)
+		}
+	}
+
+	if m.verifyKey == nil {
+		return fmt.Errorf("jwt %s requires private_key_file or public_key_file", m.cfg.Algorithm)
+	}
+	return nil
+}
+
+// AccessTTL / RefreshTTL — thời hạn token theo config
+func (m *Manager) AccessTTL() time.Duration  { return m.cfg.AccessTTL }
+func (m *Manager) RefreshTTL() time.Duration { return m.cfg.RefreshTTL }
+
+// Sign — ký access token, tự điền iss, aud, iat, nbf, exp, jti nếu claims chưa có
+func (m *Manager) Sign(claims Claims) (string, time.Time, error) {
+	if m.signKey == nil {
+		return "", time.Time{}, ErrSigningDisabled
+	}
+
+	now := m.now()
+	expiresAt := now.Add(m.cfg.AccessTTL)
+	if claims.Issuer == "" {
+		claims.Issuer = m.cfg.Issuer
+	}
+	if len(claims.Audience) == 0 {
+		claims.Audience = m.cfg.Audience
+	}
+	if claims.ID == "" {
+		claims.ID = uuid.NewString()
+	}
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	claims.NotBefore = jwt.NewNumericDate(now)
+	claims.ExpiresAt = jwt.NewNumericDate(expiresAt)
+
+	t := jwt.NewWithClaims(m.method, claims)
+	if m.cfg.KeyID != "" {
+		t.Header["kid"] = m.cfg.KeyID
+	}
+	signed, err := t.SignedString(m.signKey)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("sign token failed: %w", err)
+	}
+	return signed, expiresAt, nil
+}
+
+// Parse — verify chữ ký, alg, exp, nbf, iat, iss, aud → claims
+func (m *Manager) Parse(raw string) (*Claims, error) {
+	claims := &Claims{}
+	_, err := m.parser.ParseWithClaims(raw, claims, func(t *jwt.Token) (any, error) {
+		if kid, ok := t.Header["kid"].(string); ok && m.cfg.KeyID != "" && kid != m.cfg.KeyID {
+			return nil, fmt.Errorf("unknown kid %q", kid)
+		}
+		return m.verifyKey, nil
+	})
+	switch {
+	case err == nil:
+		return claims, nil
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return nil, ErrExpiredToken
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+}
+
+// BearerToken — "Bearer xxx" → "xxx" (không phân biệt hoa thường), sai định dạng → ""
+func BearerToken(header string) string {
+	scheme, raw, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(raw)
+}
+
+// ============================================================
+// JWKS — public key cho service khác verify token (RFC 7517)
+// HS256 → không công khai key nào (secret không được lộ)
+// ============================================================
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`   // RSA modulus
+	E   string `json:"e,omitempty"`   // RSA exponent
+	Crv string `json:"crv,omitempty"` // OKP curve
+	X   string `json:"x,omitempty"`   // OKP public key
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+func (m *Manager) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	switch key := m.verifyKey.(type) {
+	case *rsa.PublicKey:
+		set.Keys = append(set.Keys, JWK{
+			Kty: "RSA",
+			Kid: m.cfg.KeyID,
+			Use: "sig",
+			Alg: RS256,
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(bigEndian(key.E)),
+		})
+	case ed25519.PublicKey:
+		set.Keys = append(set.Keys, JWK{
+			Kty: "OKP",
+			Kid: m.cfg.KeyID,
+			Use: "sig",
+			Alg: EdDSA,
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(key),
+		})
+	}
+	return set
+}
+
+// bigEndian — exponent RSA sang byte big-endian, bỏ byte 0 ở đầu (65537 → AQAB)
+func bigEndian(n int) []byte {
+	b := []byte{byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n)}
+	i := slices.IndexFunc(b, func(x byte) bool { return x != 0 })
+	if i < 0 {
+		return []byte{0}
+	}
+	return b[i:]
+}
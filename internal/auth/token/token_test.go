@@ -0,0 +1,120 @@
+package token
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testSecret = "unit-test-secret-0123456789abcdef"
+
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+	m, err := NewManager(Config{Secret: testSecret, Issuer: "test", AccessTTL: time.Minute})
+	if err != nil {
+		t.Fatalf("new manager: %v", err)
+	}
+	return m
+}
+
+func TestParseValidToken(t *testing.T) {
+	m := newTestManager(t)
+	raw, _, err := m.Sign(Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "15"}, SessionID: "sid-1"})
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	claims, err := m.Parse(raw)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if claims.Subject != "15" || claims.SessionID != "sid-1" {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+}
+
+func TestParseExpiredToken(t *testing.T) {
+	m := newTestManager(t)
+	raw, expiresAt, err := m.Sign(Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "15"}})
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+
+	m.now = func() time.Time { return expiresAt.Add(time.Second) }
+	if _, err := m.Parse(raw); !errors.Is(err, ErrExpiredToken) {
+		t.Fatalf("expected ErrExpiredToken, got %v", err)
+	}
+}
+
+func TestParseTamperedToken(t *testing.T) {
+	m := newTestManager(t)
+	raw, _, err := m.Sign(Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "15"}, Roles: []string{"member"}})
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	parts := strings.Split(raw, ".")
+
+	// Sửa payload (leo quyền admin), giữ nguyên chữ ký
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	forged := strings.Replace(string(payload), `"member"`, `"admin"`, 1)
+	parts[1] = base64.RawURLEncoding.EncodeToString([]byte(forged))
+	if _, err := m.Parse(strings.Join(parts, ".")); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("tampered payload: expected ErrInvalidToken, got %v", err)
+	}
+
+	// Ký bằng secret khác
+	other, err := NewManager(Config{Secret: "another-secret-0123456789abcdefgh", Issuer: "test"})
+	if err != nil {
+		t.Fatalf("new manager: %v", err)
+	}
+	raw, _, _ = other.Sign(Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "15"}})
+	if _, err := m.Parse(raw); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("foreign secret: expected ErrInvalidToken, got %v", err)
+	}
+}
+
+func TestParseWrongAlgorithm(t *testing.T) {
+	m := newTestManager(t)
+	now := time.Now()
+	claims := Claims{RegisteredClaims: jwt.RegisteredClaims{
+		Subject:   "15",
+		Issuer:    "test",
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+	}}
+
+	// Cùng secret nhưng HS512 → alg không nằm trong danh sách cho phép
+	hs512, err := jwt.NewWithClaims(jwt.SigningMethodHS512, claims).SignedString([]byte(testSecret))
+	if err != nil {
+		t.Fatalf("sign HS512: %v", err)
+	}
+	if _, err := m.Parse(hs512); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("HS512: expected ErrInvalidToken, got %v", err)
+	}
+
+	// alg "none" — không chữ ký
+	none, err := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatalf("sign none: %v", err)
+	}
+	if _, err := m.Parse(none); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("none: expected ErrInvalidToken, got %v", err)
+	}
+}
+
+func TestParseWrongIssuer(t *testing.T) {
+	m := newTestManager(t)
+	raw, _, err := m.Sign(Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "15", Issuer: "someone-else"}})
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if _, err := m.Parse(raw); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("expected ErrInvalidToken, got %v", err)
+	}
+}
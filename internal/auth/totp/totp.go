@@ -0,0 +1,154 @@
+// Package totp — mã xác thực 2 bước theo thời gian (RFC 6238, tương thích Google Authenticator / Authy)
+//
+//	code = HOTP(secret, floor(unix / 30))   → 6 chữ số, HMAC-SHA1 (RFC 4226)
+//
+// → secret: 20 byte ngẫu nhiên, encode base32 để user nhập tay / quét QR (otpauth://)
+// → DB chỉ lưu secret đã mã hóa (secure.Cipher), lộ DB không sinh được mã
+// → Verify chấp nhận lệch ±1 bước (đồng hồ điện thoại lệch) và trả về bước đã dùng
+// → lưu lại bước đó, lần sau chỉ nhận bước lớn hơn → 1 mã không dùng được 2 lần
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang-base/pkg/secure"
+)
+
+const (
+	Period = 30 * time.Second // mỗi mã sống 30 giây
+	Digits = 6
+	Skew   = 1 // số bước lệch cho phép mỗi phía
+)
+
+// ErrInvalidCode — mã sai / hết hạn / đã dùng
+var ErrInvalidCode = errors.New("invalid two-factor code")
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Config — Issuer hiện trong app Authenticator, EncryptionKey mã hóa secret trong DB (>= 32 byte)
+type Config struct {
+	Issuer        string
+	EncryptionKey string
+}
+
+// Key — secret vừa sinh khi user bật 2FA
+type Key struct {
+	Secret string // base32, hiện cho user nhập tay
+	Sealed string // đã mã hóa, lưu DB
+	URI    string // otpauth://totp/..., client render thành QR code
+}
+
+// Manager — sinh / kiểm tra mã TOTP, an toàn khi dùng đồng thời
+type Manager struct {
+	issuer string
+	cipher *secure.Cipher
+}
+
+func NewManager(cfg Config) (*Manager, error) {
+	if cfg.Issuer == "" {
+		return nil, errors.New("totp issuer is required")
+	}
+	c, err := secure.NewCipher(cfg.EncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("totp encryption key: %w", err)
+	}
+	return &Manager{issuer: cfg.Issuer, cipher: c}, nil
+}
+
+// Generate — secret mới cho account (thường là email)
+func (m *Manager) Generate(account string) (*Key, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("generate totp secret failed: %w", err)
+	}
+	secret := encoding.EncodeToString(b)
+
+	sealed, err := m.cipher.Encrypt(secret)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{Secret: secret, Sealed: sealed, URI: m.URI(account, secret)}, nil
+}
+
+// URI — định dạng Key URI của Google Authenticator
+// VD: otpauth://totp/chat-service:an@x.com?secret=...&issuer=chat-service&algorithm=SHA1&digits=6&period=30
+func (m *Manager) URI(account, secret string) string {
+	q := url.Values{}
+	q.Set("secret", secret)
+	q.Set("issuer", m.issuer)
+	q.Set("algorithm", "SHA1")
+	q.Set("digits", fmt.Sprint(Digits))
+	q.Set("period", fmt.Sprint(int(Period.Seconds())))
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + m.issuer + ":" + account,
+		RawQuery: q.Encode(),
+	}
+	return u.String()
+}
+
+// Verify — kiểm tra mã với secret đã mã hóa
+// Chỉ nhận bước > lastStep (chống dùng lại), trả về bước khớp để lưu làm lastStep mới
+func (m *Manager) Verify(sealed, code string, now time.Time, lastStep int64) (int64, error) {
+	code = strings.ReplaceAll(strings.TrimSpace(code), " ", "")
+	if len(code) != Digits {
+		return 0, ErrInvalidCode
+	}
+	secret, err := m.cipher.Decrypt(sealed)
+	if err != nil {
+		return 0, err
+	}
+	key, err := encoding.DecodeString(secret)
+	if err != nil {
+		return 0, fmt.Errorf("decode totp secret failed: %w", err)
+	}
+
+	current := Step(now)
+	for step := current - Skew; step <= current+Skew; step++ {
+		if step <= lastStep {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(hotp(key, step)), []byte(code)) == 1 {
+			return step, nil
+		}
+	}
+	return 0, ErrInvalidCode
+}
+
+// Step — số thứ tự bước 30 giây của thời điểm t
+func Step(t time.Time) int64 {
+	return t.Unix() / int64(Period.Seconds())
+}
+
+// Code — mã tại thời điểm t từ secret base32 (test / công cụ dev)
+func Code(secret string, t time.Time) (string, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", fmt.Errorf("decode totp secret failed: %w", err)
+	}
+	return hotp(key, Step(t)), nil
+}
+
+// hotp — RFC 4226: HMAC-SHA1(counter) → dynamic truncation → 6 chữ số
+func hotp(key []byte, counter int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(counter))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", Digits, value%1_000_000)
+}
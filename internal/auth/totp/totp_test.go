@@ -0,0 +1,140 @@
+package totp
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"golang-base/pkg/secure"
+)
+
+func newTestManager(t *testing.T, key string) *Manager {
+	t.Helper()
+	m, err := NewManager(Config{Issuer: "test", EncryptionKey: key})
+	if err != nil {
+		t.Fatalf("new manager: %v", err)
+	}
+	return m
+}
+
+// RFC 6238 phụ lục B (SHA1, secret "12345678901234567890"), 6 chữ số cuối của mã 8 chữ số
+func TestCodeRFC6238(t *testing.T) {
+	secret := encoding.EncodeToString([]byte("12345678901234567890"))
+	vectors := map[int64]string{
+		59:         "287082",
+		1111111109: "081804",
+		1234567890: "005924",
+		2000000000: "279037",
+	}
+	for unix, want := range vectors {
+		got, err := Code(secret, time.Unix(unix, 0))
+		if err != nil {
+			t.Fatalf("code: %v", err)
+		}
+		if got != want {
+			t.Fatalf("t=%d: expected %s, got %s", unix, want, got)
+		}
+	}
+}
+
+func TestVerifySkew(t *testing.T) {
+	m := newTestManager(t, "unit-test-2fa-key-0123456789abcdef")
+	key, err := m.Generate("an@example.com")
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	now := time.Unix(1_700_000_000, 0)
+
+	cases := []struct {
+		name   string
+		offset time.Duration
+		ok     bool
+	}{
+		{"current step", 0, true},
+		{"previous step", -Period, true},
+		{"next step", Period, true},
+		{"two steps behind", -2 * Period, false},
+		{"two steps ahead", 2 * Period, false},
+	}
+	for _, tc := range cases {
+		code, _ := Code(key.Secret, now.Add(tc.offset))
+		step, err := m.Verify(key.Sealed, code, now, 0)
+		if tc.ok {
+			if err != nil || step != Step(now.Add(tc.offset)) {
+				t.Fatalf("%s: expected step %d, got %d (%v)", tc.name, Step(now.Add(tc.offset)), step, err)
+			}
+			continue
+		}
+		if !errors.Is(err, ErrInvalidCode) {
+			t.Fatalf("%s: expected ErrInvalidCode, got step %d (%v)", tc.name, step, err)
+		}
+	}
+}
+
+func TestVerifyRejectsReplay(t *testing.T) {
+	m := newTestManager(t, "unit-test-2fa-key-0123456789abcdef")
+	key, _ := m.Generate("an@example.com")
+	now := time.Unix(1_700_000_000, 0)
+	code, _ := Code(key.Secret, now)
+
+	step, err := m.Verify(key.Sealed, code, now, 0)
+	if err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+	// Cùng mã, vẫn trong cửa sổ 30 giây → đã dùng
+	if _, err := m.Verify(key.Sealed, code, now.Add(10*time.Second), step); !errors.Is(err, ErrInvalidCode) {
+		t.Fatalf("replay: expected ErrInvalidCode, got %v", err)
+	}
+	// Mã của bước trước bước đã dùng (skew) cũng không nhận
+	previous, _ := Code(key.Secret, now.Add(-Period))
+	if _, err := m.Verify(key.Sealed, previous, now, step); !errors.Is(err, ErrInvalidCode) {
+		t.Fatalf("older step: expected ErrInvalidCode, got %v", err)
+	}
+	// Bước kế tiếp vẫn dùng được
+	next, _ := Code(key.Secret, now.Add(Period))
+	if _, err := m.Verify(key.Sealed, next, now.Add(Period), step); err != nil {
+		t.Fatalf("next step: %v", err)
+	}
+}
+
+func TestVerifyNormalizesInput(t *testing.T) {
+	m := newTestManager(t, "unit-test-2fa-key-0123456789abcdef")
+	key, _ := m.Generate("an@example.com")
+	now := time.Unix(1_700_000_000, 0)
+	code, _ := Code(key.Secret, now)
+
+	if _, err := m.Verify(key.Sealed, " "+code[:3]+" "+code[3:]+" ", now, 0); err != nil {
+		t.Fatalf("spaced code: %v", err)
+	}
+	if _, err := m.Verify(key.Sealed, code[:5], now, 0); !errors.Is(err, ErrInvalidCode) {
+		t.Fatalf("short code: expected ErrInvalidCode, got %v", err)
+	}
+}
+
+func TestSecretEncryption(t *testing.T) {
+	m := newTestManager(t, "unit-test-2fa-key-0123456789abcdef")
+	key, err := m.Generate("an@example.com")
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if key.Sealed == key.Secret || strings.Contains(key.Sealed, key.Secret) {
+		t.Fatal("sealed secret leaks plaintext")
+	}
+	if !strings.Contains(key.URI, "secret="+key.Secret) || !strings.HasPrefix(key.URI, "otpauth://totp/") {
+		t.Fatalf("unexpected uri: %s", key.URI)
+	}
+
+	// Round-trip: giải mã ra đúng secret đã hiện cho user
+	plain, err := m.cipher.Decrypt(key.Sealed)
+	if err != nil || plain != key.Secret {
+		t.Fatalf("decrypt: expected %s, got %s (%v)", key.Secret, plain, err)
+	}
+
+	// Sai key mã hóa (VD: đổi TWO_FACTOR_ENCRYPTION_KEY) → không verify được, không lẫn với mã sai
+	other := newTestManager(t, "another-2fa-key-0123456789abcdefgh")
+	code, _ := Code(key.Secret, time.Now())
+	if _, err := other.Verify(key.Sealed, code, time.Now(), 0); !errors.Is(err, secure.ErrDecrypt) {
+		t.Fatalf("wrong key: expected secure.ErrDecrypt, got %v", err)
+	}
+}
@@ -1,18 +1,511 @@
 package base
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"golang-base/internal/serializer"
+	si "golang-base/internal/service/interfaces"
+	"golang-base/pkg/apperror"
+	response "golang-base/pkg/response"
+	"golang-base/pkg/validation"
+
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm/schema"
 )
 
+// ============================================================
+// BASE CONTROLLER — CRUD HTTP dùng chung trên IBaseService
+//
+//	GET    /resources             Index        (phân trang + filter, xem ParseSpecs)
+//	POST   /resources             Store
+//	POST   /resources/bulk        BulkStore    body: [{...}, {...}]
+//	PATCH  /resources/bulk        BulkUpdate   body: {"ids": [1, 2], "fields": {"publish": 2}}
+//	DELETE /resources/bulk        BulkDestroy  body: {"ids": [1, 2]}
+//	GET    /resources/:id         Show
+//	PUT    /resources/:id         Update       (ghi đè toàn bộ, kể cả zero value)
+//	PATCH  /resources/:id         Patch        (chỉ field có giá trị, zero value bị bỏ qua)
+//	DELETE /resources/:id         Destroy
+//	POST   /resources/:id/restore Restore      (model có soft delete)
+//
+// GET trả ETag (If-None-Match khớp → 304), PUT / PATCH / DELETE nhận If-Match (lệch → 412), xem etag.go
+//
+// Đăng ký route: routers.RegisterResource(group, "/resources", controller)
+// Module cần logic riêng → embed *BaseController rồi override đúng handler đó
+//
+// T: model, K: kiểu primary key (uint, string, uuid.UUID...)
+// ============================================================
+
+// Options — whitelist những gì client được gửi lên
+// Filterable / Sortable / Searchable / Relations để trống → không cho dùng
+type Options struct {
+	Key        string   // column primary key, mặc định "id"
+	Fillable   []string // field (json) client được gửi khi tạo / sửa, rỗng → mọi field trừ Guarded
+	Filterable []string // ?filter[field]=...
+	Sortable   []string // ?sort=field (primary key luôn sort được)
+	Searchable []string // column tìm theo ?keyword=
+	Relations  []string // ?include=Relation
+	MaxLimit   int      // trần ?limit=, mặc định MaxLimit
+	MaxBulk    int      // số phần tử tối đa mỗi request bulk, mặc định MaxBulk
+}
+
+// MaxBulk — số phần tử tối đa mỗi request bulk
+const MaxBulk = 100
+
+// Guarded — field client KHÔNG BAO GIỜ gửi được khi Fillable rỗng
+var Guarded = []string{"id", "created_at", "updated_at", "deleted_at"}
+
+func (o Options) key() string {
+	if o.Key == "" {
+		return "id"
+	}
+	return o.Key
+}
+
+func (o Options) maxLimit() int {
+	if o.MaxLimit <= 0 {
+		return MaxLimit
+	}
+	return o.MaxLimit
+}
+
+func (o Options) maxBulk() int {
+	if o.MaxBulk <= 0 {
+		return MaxBulk
+	}
+	return o.MaxBulk
+}
+
+// visible — bỏ field Serializer ẩn với principal
+func visible[T any](ctx context.Context, s *serializer.Serializer[T], fields []string) []string {
+	return slices.DeleteFunc(slices.Clone(fields), func(field string) bool {
+		return s.Hidden(ctx, field)
+	})
+}
+
+// fillable — field được ghi từ request
+func (o Options) fillable(field string) bool {
+	if len(o.Fillable) > 0 {
+		return slices.Contains(o.Fillable, field)
+	}
+	return field != o.key() && !slices.Contains(Guarded, field)
+}
+
 // handle <=> controller
-type BaseController[T any] struct {
+type BaseController[T any, K comparable] struct {
+	Service    si.IBaseService[T, K]
+	Serializer *serializer.Serializer[T] // nil → trả thẳng model
+	Options    Options
+}
+
+// NewBaseController — serializer nil → response là model (theo json tag)
+func NewBaseController[T any, K comparable](service si.IBaseService[T, K], serializer *serializer.Serializer[T], opts Options) *BaseController[T, K] {
+	return &BaseController[T, K]{Service: service, Serializer: serializer, Options: opts}
 }
 
-func NewBaseController[T any](c *gin.Context) *BaseController[T] {
-	return &BaseController[T]{}
+// queryOptions — Options của request: filter / sort / tìm kiếm trên field Serializer ẩn với principal
+// bị coi như không có trong whitelist (VD: non-admin gửi filter[role] → 400)
+func (h *BaseController[T, K]) queryOptions(c *gin.Context) Options {
+	if h.Serializer == nil {
+		return h.Options
+	}
+	opts := h.Options
+	ctx := c.Request.Context()
+	opts.Filterable = visible(ctx, h.Serializer, opts.Filterable)
+	opts.Sortable = visible(ctx, h.Serializer, opts.Sortable)
+	opts.Searchable = visible(ctx, h.Serializer, opts.Searchable)
+	return opts
 }
 
-// c === context
-func (h *BaseController[T]) Paginate(c *gin.Context) {
+// BulkIDsInput — body của BulkDestroy
+type BulkIDsInput[K comparable] struct {
+	IDs []K `json:"ids"`
+}
+
+// BulkUpdateInput — body của BulkUpdate, fields: json name → giá trị mới (controller tự đổi sang column)
+type BulkUpdateInput[K comparable] struct {
+	IDs    []K                        `json:"ids"`
+	Fields map[string]json.RawMessage `json:"fields"`
+}
+
+// ============================================================
+// READ
+// ============================================================
+
+// Index — GET /resources
+func (h *BaseController[T, K]) Index(c *gin.Context) {
+	specs, err := ParseSpecs[K](c, h.queryOptions(c))
+	if err != nil {
+		badRequest(c, apperror.ErrInvalidQuery, err)
+		return
+	}
+	result, err := h.Service.Paginate(c.Request.Context(), specs)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	PageLinks(c, specs, result)
+	var page any = result
+	if h.Serializer != nil {
+		// Transform trước khi tính ETag → hash đúng JSON gửi đi (Transformer chỉ có field unexported → mọi trang cùng 1 hash)
+		page = h.Serializer.Page(result).Transform(c)
+	}
+	if NotModified(c, ContentETag(page)) {
+		return
+	}
+	response.OK(c, page)
+}
+
+// Show — GET /resources/:id
+func (h *BaseController[T, K]) Show(c *gin.Context) {
+	id, ok := h.bindID(c)
+	if !ok {
+		return
+	}
+	record, ok := h.find(c, id)
+	if !ok {
+		return
+	}
+	if NotModified(c, h.etag(c, record)) {
+		return
+	}
+	response.OK(c, h.present(record))
+}
+
+// ============================================================
+// WRITE — PUT / PATCH / DELETE có If-Match → lệch ETag hiện tại thì 412 (xem IfMatchFunc)
+// ============================================================
+
+// Store — POST /resources
+func (h *BaseController[T, K]) Store(c *gin.Context) {
+	var payload T
+	if err := h.bindPayload(c, &payload); err != nil {
+		RespondError(c, BindError(err))
+		return
+	}
+	if err := h.Service.Create(c.Request.Context(), &payload); err != nil {
+		RespondError(c, err)
+		return
+	}
+	response.Created(c, h.present(&payload))
+}
+
+// Update — PUT /resources/:id, ghi đè toàn bộ record
+func (h *BaseController[T, K]) Update(c *gin.Context) {
+	h.update(c, h.Service.Replace)
+}
+
+// Patch — PATCH /resources/:id, chỉ field có giá trị
+// Lưu ý: zero value (false, 0, "") bị bỏ qua → muốn set về zero value dùng PUT
+func (h *BaseController[T, K]) Patch(c *gin.Context) {
+	h.update(c, h.Service.Update)
+}
+
+func (h *BaseController[T, K]) update(c *gin.Context, save func(ctx context.Context, id K, payload *T) error) {
+	id, ok := h.bindID(c)
+	if !ok {
+		return
+	}
+	if _, ok := h.find(c, id); !ok {
+		return
+	}
+
+	var payload T
+	if err := h.bindPayload(c, &payload); err != nil {
+		RespondError(c, BindError(err))
+		return
+	}
+	if err := save(h.ifMatch(c), id, &payload); err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	// Đọc lại → response có đủ field (payload PATCH chỉ có field client gửi) + ETag mới cho lần sửa sau
+	record, ok := h.find(c, id)
+	if !ok {
+		return
+	}
+	c.Header("ETag", h.etag(c, record))
+	c.Writer.Header().Add("Vary", "Authorization")
+	response.OK(c, h.present(record))
+}
+
+// Destroy — DELETE /resources/:id
+func (h *BaseController[T, K]) Destroy(c *gin.Context) {
+	id, ok := h.bindID(c)
+	if !ok {
+		return
+	}
+	if _, ok := h.find(c, id); !ok {
+		return
+	}
+	if err := h.Service.Delete(h.ifMatch(c), id); err != nil {
+		RespondError(c, err)
+		return
+	}
+	response.OK(c, nil)
+}
+
+// Restore — POST /resources/:id/restore, record chưa bị xóa / không tồn tại → 404
+func (h *BaseController[T, K]) Restore(c *gin.Context) {
+	id, ok := h.bindID(c)
+	if !ok {
+		return
+	}
+	affected, err := h.Service.BulkRestore(c.Request.Context(), []K{id})
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	if affected == 0 {
+		RespondError(c, apperror.ErrNotFound)
+		return
+	}
+	record, ok := h.find(c, id)
+	if !ok {
+		return
+	}
+	response.OK(c, h.present(record))
+}
+
+// ============================================================
+// BULK — tối đa Options.MaxBulk phần tử mỗi request
+// ============================================================
+
+// BulkStore — POST /resources/bulk
+func (h *BaseController[T, K]) BulkStore(c *gin.Context) {
+	var items []json.RawMessage
+	if !BindJSON(c, &items) {
+		return
+	}
+	if err := h.checkBulkSize(len(items)); err != nil {
+		badRequest(c, apperror.ErrInvalidBody, err)
+		return
+	}
+
+	payloads := make([]T, len(items))
+	for i, item := range items {
+		if err := h.fill(item, &payloads[i]); err != nil {
+			e := BindError(err)
+			if verrs, ok := e.Details.(validation.Errors); ok {
+				e = e.WithDetails(verrs.Prefix(fmt.Sprintf("[%d]", i)))
+			}
+			RespondError(c, e)
+			return
+		}
+	}
+	if err := h.Service.BulkCreate(c.Request.Context(), payloads); err != nil {
+		RespondError(c, err)
+		return
+	}
+	if h.Serializer != nil {
+		response.Created(c, h.Serializer.Many(payloads))
+		return
+	}
+	response.Created(c, payloads)
+}
+
+// BulkUpdate — PATCH /resources/bulk, cùng 1 bộ giá trị cho mọi id
+// Key của fields là json name → đổi sang column theo schema của T trước khi xuống service
+// Giá trị được validate theo tag `validate` của model (chỉ field có giá trị)
+func (h *BaseController[T, K]) BulkUpdate(c *gin.Context) {
+	var in BulkUpdateInput[K]
+	if !BindJSON(c, &in) {
+		return
+	}
+	if err := h.checkBulkSize(len(in.IDs)); err != nil {
+		badRequest(c, apperror.ErrInvalidBody, err)
+		return
+	}
+
+	if len(in.Fields) == 0 {
+		RespondError(c, apperror.ErrInvalidBody.WithDetails(validation.Errors{validation.NewFieldError("fields", "required", "")}))
+		return
+	}
+
+	// json name → column: key không phải column của T (relation, json:"-", gõ sai) hoặc ngoài Fillable → 422
+	// KHÔNG bỏ qua im lặng: client tưởng đã cập nhật mà thật ra không
+	columns, err := columnsOf[T]()
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	var unknown validation.Errors
+	for _, field := range slices.Sorted(maps.Keys(in.Fields)) {
+		if _, ok := columns[field]; !ok || !h.Options.fillable(field) {
+			unknown = append(unknown, validation.NewFieldError("fields."+field, "writable", ""))
+		}
+	}
+	if len(unknown) > 0 {
+		RespondError(c, unknown)
+		return
+	}
+
+	values := make(map[string]any, len(in.Fields))
+	fields := make(map[string]any, len(in.Fields)) // theo column, truyền xuống service
+	for field, raw := range in.Fields {
+		var value any
+		if err := json.Unmarshal(raw, &value); err != nil {
+			badRequest(c, apperror.ErrInvalidBody, fmt.Errorf("%s: %w", field, err))
+			return
+		}
+		values[field] = value
+		fields[columns[field]] = value
+	}
+
+	// Validate bằng chính model: decode fields vào T rồi chạy rule của field có giá trị
+	body, _ := json.Marshal(values)
+	var probe T
+	if err := json.Unmarshal(body, &probe); err != nil {
+		RespondError(c, BindError(err))
+		return
+	}
+	if err := validation.Partial(c.Request.Context(), &probe); err != nil {
+		RespondError(c, err)
+		return
+	}
+
+	affected, err := h.Service.BulkUpdate(c.Request.Context(), map[string]any{h.Options.key(): in.IDs}, fields)
+	if err != nil {
+		RespondError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"affected": affected})
+}
+
+// BulkDestroy — DELETE /resources/bulk
+func (h *BaseController[T, K]) BulkDestroy(c *gin.Context) {
+	var in BulkIDsInput[K]
+	if !BindJSON(c, &in) {
+		return
+	}
+	if err := h.checkBulkSize(len(in.IDs)); err != nil {
+		badRequest(c, apperror.ErrInvalidBody, err)
+		return
+	}
+	if err := h.Service.BulkDelete(c.Request.Context(), in.IDs); err != nil {
+		RespondError(c, err)
+		return
+	}
+	response.OK(c, nil)
+}
+
+// ============================================================
+// HELPERS
+// ============================================================
+
+// BindID — đọc path param ":id" và parse sang kiểu key K
+// VD: GET /user-catalogues/15 → uint(15), GET /orders/0190b6a1-... → uuid.UUID
+func (h *BaseController[T, K]) BindID(c *gin.Context) (K, error) {
+	return ParseID[K](c.Param("id"))
+}
+
+// bindID — BindID, lỗi → 400 luôn
+func (h *BaseController[T, K]) bindID(c *gin.Context) (K, bool) {
+	id, err := h.BindID(c)
+	if err != nil {
+		badRequest(c, apperror.ErrInvalidID, err)
+		return id, false
+	}
+	return id, true
+}
+
+// find — đọc record qua service (có policy), không có → 404
+func (h *BaseController[T, K]) find(c *gin.Context, id K) (*T, bool) {
+	record, err := h.Service.FindById(c.Request.Context(), id)
+	if err != nil {
+		RespondError(c, err)
+		return nil, false
+	}
+	if record == nil {
+		RespondError(c, apperror.ErrNotFound)
+		return nil, false
+	}
+	return record, true
+}
+
+// bindPayload — đọc body JSON vào payload, chỉ giữ field fillable
+func (h *BaseController[T, K]) bindPayload(c *gin.Context, payload *T) error {
+	var raw json.RawMessage
+	if err := c.ShouldBindJSON(&raw); err != nil {
+		return err
+	}
+	return h.fill(raw, payload)
+}
+
+// fill — chống mass assignment: bỏ field ngoài Fillable (id, created_at...) trước khi decode vào T
+// Lỗi là lỗi decode gốc của encoding/json → BindError dựng được lỗi field (body không phải object, sai kiểu...)
+func (h *BaseController[T, K]) fill(raw json.RawMessage, payload *T) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return err
+	}
+	for field := range fields {
+		if !h.Options.fillable(field) {
+			delete(fields, field)
+		}
+	}
+	body, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, payload)
+}
+
+// schemaCache — schema GORM đã parse, dùng cho columnsOf
+var schemaCache sync.Map
+
+// columnsOf — json name → column trong DB của T
+// Chỉ field có column: relation (VD: permissions) và field json:"-" không có mặt
+func columnsOf[T any]() (map[string]string, error) {
+	s, err := schema.Parse(new(T), &schemaCache, schema.NamingStrategy{})
+	if err != nil {
+		return nil, fmt.Errorf("parse schema failed: %w", err)
+	}
+	columns := make(map[string]string, len(s.Fields))
+	for _, f := range s.Fields {
+		if f.DBName == "" {
+			continue
+		}
+		if name := validation.FieldName(f.StructField); name != "" {
+			columns[name] = f.DBName
+		}
+	}
+	return columns, nil
+}
+
+func (h *BaseController[T, K]) checkBulkSize(n int) error {
+	if n == 0 {
+		return errors.New("at least 1 item is required")
+	}
+	if n > h.Options.maxBulk() {
+		return fmt.Errorf("at most %d items per request", h.Options.maxBulk())
+	}
+	return nil
+}
+
+// present — serializer nếu có, không thì model
+func (h *BaseController[T, K]) present(record *T) any {
+	if h.Serializer != nil {
+		return h.Serializer.One(record)
+	}
+	return record
+}
+
+// etag — ETag của record theo đúng body gửi cho request này (role, owner, ?fields= của serializer)
+func (h *BaseController[T, K]) etag(c *gin.Context, record *T) string {
+	if h.Serializer != nil {
+		return RepresentationETag(record, h.Serializer.One(record).Transform(c))
+	}
+	return ETag(record)
+}
 
+// ifMatch — If-Match so với cùng ETag mà Show trả
+func (h *BaseController[T, K]) ifMatch(c *gin.Context) context.Context {
+	return IfMatchFunc(c, func(current *T) string { return h.etag(c, current) })
 }
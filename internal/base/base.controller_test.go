@@ -0,0 +1,214 @@
+package base
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang-base/internal/auth"
+	"golang-base/internal/model"
+	"golang-base/internal/serializer"
+	si "golang-base/internal/service/interfaces"
+
+	"github.com/gin-gonic/gin"
+)
+
+// testArticle — json name khác column (headline → title), có field json:"-" và relation
+type testArticle struct {
+	ID          uint           `json:"id"`
+	Title       string         `json:"headline" validate:"omitempty,max=10"`
+	AuthorName  string         `json:"author_name"`
+	Publish     uint           `json:"publish"  gorm:"column:status" validate:"omitempty,oneof=0 1 2"`
+	Secret      string         `json:"-"`
+	Tags        []testTag      `json:"tags"     gorm:"many2many:test_article_tags"`
+	Attachments map[string]any `json:"attachments" gorm:"-"`
+}
+
+type testTag struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+// bulkService — chỉ implement BulkUpdate, ghi lại tham số nhận được
+type bulkService struct {
+	si.IBaseService[testArticle, uint]
+	conditions map[string]any
+	fields     map[string]any
+}
+
+func (s *bulkService) BulkUpdate(ctx context.Context, conditions map[string]any, fields map[string]any) (int64, error) {
+	s.conditions, s.fields = conditions, fields
+	return 2, nil
+}
+
+func TestBulkUpdateMapsFieldsToColumns(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	call := func(opts Options, body string) (*httptest.ResponseRecorder, *bulkService) {
+		service := &bulkService{}
+		router := gin.New()
+		router.PATCH("/articles/bulk", NewBaseController[testArticle, uint](service, nil, opts).BulkUpdate)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPatch, "/articles/bulk", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w, service
+	}
+
+	t.Run("json name to column", func(t *testing.T) {
+		w, service := call(Options{}, `{"ids": [1, 2], "fields": {"headline": "Go", "author_name": "An", "publish": 2}}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d %s", w.Code, w.Body)
+		}
+		want := map[string]any{"title": "Go", "author_name": "An", "status": float64(2)}
+		if !maps.Equal(service.fields, want) {
+			t.Fatalf("expected columns %v, got %v", want, service.fields)
+		}
+		if ids, _ := json.Marshal(service.conditions["id"]); string(ids) != "[1,2]" {
+			t.Fatalf("unexpected conditions %v", service.conditions)
+		}
+	})
+
+	rejected := []struct {
+		name  string
+		opts  Options
+		field string
+	}{
+		{"relation", Options{}, "tags"},
+		{"no column", Options{}, "attachments"},
+		{"unknown", Options{}, "permissions"},
+		{"go name instead of json name", Options{}, "Title"},
+		{"column name instead of json name", Options{}, "status"},
+		{"json dash", Options{}, "Secret"},
+		{"guarded", Options{}, "id"},
+		{"not in fillable", Options{Fillable: []string{"headline"}}, "publish"},
+	}
+	for _, tc := range rejected {
+		t.Run(tc.name, func(t *testing.T) {
+			w, service := call(tc.opts, `{"ids": [1], "fields": {"headline": "Go", "`+tc.field+`": 1}}`)
+			if w.Code != http.StatusUnprocessableEntity || !strings.Contains(w.Body.String(), `"fields.`+tc.field+`"`) {
+				t.Fatalf("expected 422 on fields.%s, got %d %s", tc.field, w.Code, w.Body)
+			}
+			if service.fields != nil {
+				t.Fatalf("rejected request must not reach the service, got %v", service.fields)
+			}
+		})
+	}
+
+	t.Run("validates by json name", func(t *testing.T) {
+		w, service := call(Options{}, `{"ids": [1], "fields": {"headline": "way too long headline"}}`)
+		if w.Code != http.StatusUnprocessableEntity || !strings.Contains(w.Body.String(), `"headline"`) || service.fields != nil {
+			t.Fatalf("expected 422 on headline, got %d %s", w.Code, w.Body)
+		}
+	})
+
+	t.Run("empty fields", func(t *testing.T) {
+		if w, _ := call(Options{}, `{"ids": [1], "fields": {}}`); w.Code == http.StatusOK {
+			t.Fatalf("expected error for empty fields, got %d", w.Code)
+		}
+	})
+}
+
+// userStore — 1 user trong bộ nhớ, Update / Delete kiểm tra Precondition như BaseService
+type userStore struct {
+	si.IBaseService[model.User, uint]
+	user   model.User
+	writes int
+}
+
+func (s *userStore) FindById(ctx context.Context, id uint) (*model.User, error) {
+	user := s.user
+	return &user, nil
+}
+
+func (s *userStore) Update(ctx context.Context, id uint, payload *model.User) error {
+	if check, ok := si.PreconditionFrom[model.User](ctx); ok && !check(&s.user) {
+		return si.ErrPreconditionFailed
+	}
+	s.user.Name = payload.Name
+	s.user.UpdatedAt = s.user.UpdatedAt.Add(time.Second)
+	s.writes++
+	return nil
+}
+
+func TestShowETagFollowsRepresentation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := &userStore{user: model.User{Name: "An", Email: "an@example.com"}}
+	service.user.ID = 2
+	service.user.UpdatedAt = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Header X-User: "<id>[,role]" → principal của request
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if raw := c.GetHeader("X-User"); raw != "" {
+			id, roles, _ := strings.Cut(raw, ",")
+			p := &auth.Principal{ID: id, Kind: auth.KindUser}
+			if roles != "" {
+				p.Roles = []string{roles}
+			}
+			c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), p))
+		}
+	})
+	controller := NewBaseController[model.User, uint](service, serializer.User, Options{Fillable: []string{"name"}})
+	router.GET("/users/:id", controller.Show)
+	router.PATCH("/users/:id", controller.Patch)
+
+	do := func(method, target, user string, header http.Header, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-User", user)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	show := func(target, user string) string {
+		t.Helper()
+		w := do(http.MethodGet, target, user, nil, "")
+		if w.Code != http.StatusOK || w.Header().Get("ETag") == "" || w.Header().Get("Vary") != "Authorization" {
+			t.Fatalf("expected 200 with ETag and Vary, got %d %v", w.Code, w.Header())
+		}
+		return w.Header().Get("ETag")
+	}
+
+	owner := show("/users/2", "2")
+	other := show("/users/2", "3")
+	admin := show("/users/2", "1,admin")
+	fields := show("/users/2?fields=id,name", "2")
+
+	// Chủ sở hữu thấy email, user khác không → ETag khác; ?fields= khác body → ETag khác
+	if owner == other || other == fields || owner == fields {
+		t.Fatalf("expected distinct ETags per representation, got owner %s other %s fields %s", owner, other, fields)
+	}
+	// Admin và chủ sở hữu nhận cùng body → cùng ETag
+	if owner != admin {
+		t.Fatalf("same body must give the same ETag, got %s and %s", owner, admin)
+	}
+
+	// If-None-Match của người khác không được 304
+	if w := do(http.MethodGet, "/users/2", "3", http.Header{"If-None-Match": {owner}}, ""); w.Code != http.StatusOK {
+		t.Fatalf("foreign ETag: expected 200, got %d", w.Code)
+	}
+	if w := do(http.MethodGet, "/users/2", "2", http.Header{"If-None-Match": {owner}}, ""); w.Code != http.StatusNotModified {
+		t.Fatalf("own ETag: expected 304, got %d", w.Code)
+	}
+
+	// If-Match so cùng ETag với Show: ETag của view khác → 412, đúng view → ghi + ETag mới
+	if w := do(http.MethodPatch, "/users/2", "2", http.Header{"If-Match": {fields}}, `{"name": "An 2"}`); w.Code != http.StatusPreconditionFailed || service.writes != 0 {
+		t.Fatalf("mismatched If-Match: expected 412, got %d %s", w.Code, w.Body)
+	}
+	w := do(http.MethodPatch, "/users/2", "2", http.Header{"If-Match": {owner}}, `{"name": "An 2"}`)
+	if w.Code != http.StatusOK || service.writes != 1 {
+		t.Fatalf("matching If-Match: expected 200, got %d %s", w.Code, w.Body)
+	}
+	if next := w.Header().Get("ETag"); next == owner || next != show("/users/2", "2") {
+		t.Fatalf("expected the new ETag to match the next Show, got %s", next)
+	}
+}
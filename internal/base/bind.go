@@ -0,0 +1,80 @@
+package base
+
+import (
+	"errors"
+
+	"golang-base/pkg/apperror"
+	"golang-base/pkg/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// ============================================================
+// BINDING — đọc body JSON, lỗi trả về cùng format với lỗi validate của service
+//
+//	{"field": "email", "rule": "required", "message": "email là bắt buộc"}
+//	{"field": "age", "rule": "type", "param": "number", "message": "age phải có kiểu number"}
+//	{"field": "body", "rule": "json", "message": "body không phải JSON hợp lệ"}
+//
+// Message theo Accept-Language (RespondError → apperror.Error.Localize)
+// ============================================================
+
+func init() {
+	// Tag `binding` của Gin: tên field trong lỗi theo json tag, giống engine của validation
+	if engine, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		engine.RegisterTagNameFunc(validation.FieldName)
+	}
+}
+
+// BindJSON — ShouldBindJSON, lỗi → đã trả response, handler chỉ cần return
+//
+//	var in us.LoginInput
+//	if !base.BindJSON(c, &in) {
+//		return
+//	}
+//
+// Controller import package này với alias c, *gin.Context tên ctx → c.BindJSON(ctx, &in)
+func BindJSON(c *gin.Context, out any) bool {
+	if err := c.ShouldBindJSON(out); err != nil {
+		RespondError(c, BindError(err))
+		return false
+	}
+	return true
+}
+
+// BindError — lỗi bind / decode body → *apperror.Error, details là validation.Errors
+//
+//	tag `binding` không pass   → 422 VALIDATION_FAILED
+//	JSON hỏng, rỗng, sai kiểu → 400 INVALID_BODY
+func BindError(err error) *apperror.Error {
+	if verrs, ok := bindingErrors(err); ok {
+		return apperror.ErrValidation.Wrap(err).WithDetails(verrs)
+	}
+	if verrs, ok := validation.FromJSON(err); ok {
+		return apperror.ErrInvalidBody.Wrap(err).WithDetails(verrs)
+	}
+	return apperror.ErrInvalidBody.Wrap(err).WithDetails(err.Error())
+}
+
+// bindingErrors — lỗi validator của Gin (struct hoặc slice struct) → validation.Errors
+func bindingErrors(err error) (validation.Errors, bool) {
+	var verrs validator.ValidationErrors
+	if errors.As(err, &verrs) {
+		return validation.FromValidator(verrs), true
+	}
+
+	// Slice: Gin chỉ giữ lỗi của phần tử sai, không giữ vị trí → gộp chung, không có tiền tố [i]
+	var slice binding.SliceValidationError
+	if !errors.As(err, &slice) {
+		return nil, false
+	}
+	var all validation.Errors
+	for _, item := range slice {
+		if errors.As(item, &verrs) {
+			all = append(all, validation.FromValidator(verrs)...)
+		}
+	}
+	return all, len(all) > 0
+}
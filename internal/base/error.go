@@ -0,0 +1,72 @@
+package base
+
+import (
+	"golang-base/internal/auth"
+	"golang-base/internal/repository"
+	si "golang-base/internal/service/interfaces"
+	"golang-base/pkg/apperror"
+	response "golang-base/pkg/response"
+	"golang-base/pkg/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Sentinel của tầng dưới dùng chung cho mọi module → code chung
+// Module tự Match sentinel riêng của mình (VD: controller/user/errors.go)
+func init() {
+	apperror.ErrUnauthenticated.Match(auth.ErrUnauthenticated)
+	apperror.ErrForbidden.Match(auth.ErrForbidden)
+	apperror.ErrNotFound.Match(repository.ErrNotFound)
+	apperror.ErrConflict.Match(repository.ErrDuplicateKey)
+	apperror.ErrPreconditionFailed.Match(si.ErrPreconditionFailed)
+}
+
+// RespondError — map lỗi từ service sang HTTP response qua apperror.From
+//
+//	*apperror.Error          → status + code của nó
+//	sentinel đã Match        → code đã gắn (VD: repository.ErrNotFound → 404 NOT_FOUND)
+//	validation.Errors        → 422 + danh sách lỗi field
+//	còn lại                  → 500 (không lộ chi tiết lỗi ra client)
+//
+// Message lỗi field theo Accept-Language (vi, en; ngôn ngữ khác → validation.DefaultLanguage)
+//
+// Handler cũng có thể chỉ c.Error(err) rồi return → middlewares.ErrorHandler trả response
+func RespondError(c *gin.Context, err error) {
+	_ = c.Error(err) // giữ lỗi gốc trong c.Errors (RequestLogger ghi lại), response không lộ chi tiết
+	response.AppError(c, apperror.From(err).Localize(validation.Language(c.GetHeader("Accept-Language"))))
+}
+
+// CurrentUserID — ID của user đang đăng nhập (principal loại user), parse sang kiểu K
+func CurrentUserID[K comparable](c *gin.Context) (K, bool) {
+	var zero K
+	p, ok := auth.PrincipalFrom(c.Request.Context())
+	if !ok || p.Kind != auth.KindUser {
+		return zero, false
+	}
+	id, err := ParseID[K](p.ID)
+	if err != nil {
+		return zero, false
+	}
+	return id, true
+}
+
+// RequireUserID — CurrentUserID, không phải user đăng nhập → 401 UNAUTHENTICATED (đã trả response, handler chỉ cần return)
+//
+//	userID, ok := base.RequireUserID[uint](c)
+//	if !ok {
+//		return
+//	}
+//
+// Controller import package này với alias c, *gin.Context tên ctx → c.RequireUserID[uint](ctx)
+func RequireUserID[K comparable](c *gin.Context) (K, bool) {
+	id, ok := CurrentUserID[K](c)
+	if !ok {
+		RespondError(c, apperror.ErrUnauthenticated)
+	}
+	return id, ok
+}
+
+// badRequest — input sai (query / body / id): code cụ thể + mô tả lỗi trong errors
+func badRequest(c *gin.Context, code *apperror.Error, err error) {
+	RespondError(c, code.Wrap(err).WithDetails(err.Error()))
+}
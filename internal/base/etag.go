@@ -0,0 +1,137 @@
+package base
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	si "golang-base/internal/service/interfaces"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================================
+// ETAG — conditional request (RFC 9110)
+//
+//	GET    /resources/15                            → 200, ETag: "9f2c..."
+//	GET    /resources/15  If-None-Match: "9f2c..."  → 304, không body
+//	PATCH  /resources/15  If-Match: "9f2c..."       → record đã đổi → 412 PRECONDITION_FAILED
+//
+// ETag của record: updated_at (+ version nếu model có field Version) → không cần serialize cả record
+// → updated_at TIMESTAMP chỉ chính xác tới giây: 2 lần ghi trong cùng 1 giây cho cùng ETag
+// → model cần If-Match chặt (VD: UserCatalogue) thêm field Version, repository tự tăng mỗi lần ghi
+// Model không có UpdatedAt, list → hash nội dung JSON
+//
+// Controller có Serializer: ETag record trộn thêm JSON đã serialize (RepresentationETag)
+// → cùng record nhưng khác role / owner / ?fields= → body khác → ETag khác, không 304 nhầm body của người khác
+// → response có ETag luôn kèm Vary: Authorization (cache dùng chung không trả body của user này cho user khác)
+//
+// If-Match được kiểm tra trong transaction của service (si.Precondition)
+// → 2 client cùng sửa 1 record: client ghi sau nhận 412 thay vì âm thầm ghi đè
+// ============================================================
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// ETag — ETag mạnh của 1 record, tính từ record ĐỌC TỪ DB
+// (record vừa Create còn updated_at độ chính xác nano giây, DB lưu tới giây → ETag lệch)
+func ETag(record any) string {
+	v := reflect.Indirect(reflect.ValueOf(record))
+	if v.Kind() == reflect.Struct {
+		updated := v.FieldByName("UpdatedAt")
+		if updated.IsValid() && updated.Type() == timeType && !updated.IsZero() {
+			raw := fmt.Sprint(updated.Interface().(time.Time).UnixNano())
+			if version := v.FieldByName("Version"); version.IsValid() {
+				raw += "-" + fmt.Sprint(version.Interface())
+			}
+			return hashETag([]byte(raw))
+		}
+	}
+	return ContentETag(record)
+}
+
+// RepresentationETag — ETag của record (updated_at / version) + body đã Transform gửi cho client
+// VD: admin thấy email, user khác không → 2 ETag khác nhau dù cùng updated_at
+func RepresentationETag(record any, body any) string {
+	content := ContentETag(body)
+	if content == "" {
+		return ""
+	}
+	return hashETag([]byte(ETag(record) + content))
+}
+
+// ContentETag — ETag theo nội dung JSON (list, DTO...)
+// v phải là data đã Transform (response.Transformer marshal ra "{}" → mọi response cùng ETag)
+func ContentETag(v any) string {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return hashETag(body)
+}
+
+func hashETag(raw []byte) string {
+	sum := sha256.Sum256(raw)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// NotModified — set header ETag, If-None-Match khớp → 304 (true: đã trả response, handler return luôn)
+// Gọi trước response.OK
+func NotModified(c *gin.Context, etag string) bool {
+	if etag == "" {
+		return false
+	}
+	c.Header("ETag", etag)
+	c.Writer.Header().Add("Vary", "Authorization")
+	if !matchETag(c.GetHeader("If-None-Match"), etag, true) {
+		return false
+	}
+	c.AbortWithStatus(http.StatusNotModified)
+	return true
+}
+
+// IfMatch — ctx của request, có header If-Match → kèm si.Precondition cho Update / Replace / Delete
+//
+//	if err := h.Service.Delete(IfMatch[T](c), id); err != nil { ... } // lệch ETag → 412
+func IfMatch[T any](c *gin.Context) context.Context {
+	return IfMatchFunc(c, func(current *T) string { return ETag(current) })
+}
+
+// IfMatchFunc — như IfMatch, ETag tính bằng etag (phải cùng cách với ETag đã trả ở GET, VD: RepresentationETag)
+// etag chạy trong transaction của service với record hiện tại, principal + ?fields= của request ghi
+// → If-Match gửi tới đúng URL (cùng ?fields=) đã GET, bằng cùng token
+func IfMatchFunc[T any](c *gin.Context, etag func(current *T) string) context.Context {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		return c.Request.Context()
+	}
+	return si.WithPrecondition(c.Request.Context(), si.Precondition[T](func(current *T) bool {
+		return matchETag(header, etag(current), false)
+	}))
+}
+
+// matchETag — header (danh sách ETag hoặc "*") có chứa etag không
+// weak: If-None-Match so sánh yếu (bỏ W/), If-Match so sánh mạnh (ETag W/ không bao giờ khớp)
+func matchETag(header, etag string, weak bool) bool {
+	for _, item := range strings.Split(header, ",") {
+		item = strings.TrimSpace(item)
+		if item == "*" {
+			return true
+		}
+		if strings.HasPrefix(item, "W/") {
+			if !weak {
+				continue
+			}
+			item = item[2:]
+		}
+		if item == etag {
+			return true
+		}
+	}
+	return false
+}
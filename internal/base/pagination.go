@@ -0,0 +1,144 @@
+package base
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang-base/global/common"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================================
+// PAGE LINKS — URL first / last / prev / next cho kết quả phân trang
+//
+// Gắn vào body (result.Links) VÀ header Link theo RFC 8288:
+//
+//	Link: <https://api.example.com/v1/users?page=1&per_page=20>; rel="first",
+//	      <https://api.example.com/v1/users?page=3&per_page=20>; rel="next"
+//
+// URL giữ nguyên query hiện tại (filter, sort, keyword...), chỉ thay tham số phân trang
+// → page mode giữ page / per_page, offset mode giữ limit / offset, keyset giữ cursor
+// ============================================================
+
+// PageLinks — gọi sau Paginate, trước response.OK (header phải set trước khi ghi body)
+func PageLinks[T any](c *gin.Context, specs common.Specs, result *common.PaginateResult[T]) {
+	if result == nil {
+		return
+	}
+
+	var links *common.PageLinks
+	switch {
+	case specs.UseKeyset:
+		links = keysetLinks(c, result)
+	case specs.Page > 0:
+		links = pageNumberLinks(c, result)
+	default:
+		links = offsetLinks(c, specs, result)
+	}
+	if links == nil {
+		return
+	}
+
+	result.Links = links
+	if header := linkHeader(links); header != "" {
+		c.Header("Link", header)
+	}
+}
+
+// pageNumberLinks — ?page=N&per_page=L
+func pageNumberLinks[T any](c *gin.Context, result *common.PaginateResult[T]) *common.PageLinks {
+	meta := result.Meta
+	if meta == nil {
+		return nil
+	}
+	page := func(n int) string {
+		return pageURL(c, map[string]string{"page": strconv.Itoa(n), "per_page": strconv.Itoa(meta.PerPage)}, "limit", "offset")
+	}
+
+	links := &common.PageLinks{First: page(1), Last: page(meta.LastPage)}
+	if meta.CurrentPage > 1 {
+		links.Prev = page(min(meta.CurrentPage-1, meta.LastPage))
+	}
+	if meta.CurrentPage < meta.LastPage {
+		links.Next = page(meta.CurrentPage + 1)
+	}
+	return links
+}
+
+// offsetLinks — ?limit=L&offset=O, trang cuối = offset chia hết cho limit
+func offsetLinks[T any](c *gin.Context, specs common.Specs, result *common.PaginateResult[T]) *common.PageLinks {
+	if specs.Limit <= 0 || result.Meta == nil {
+		return nil // không giới hạn → chỉ có 1 trang
+	}
+	limit := specs.Limit
+	offset := func(n int) string {
+		return pageURL(c, map[string]string{"limit": strconv.Itoa(limit), "offset": strconv.Itoa(n)}, "page", "per_page")
+	}
+
+	links := &common.PageLinks{First: offset(0), Last: offset((result.Meta.LastPage - 1) * limit)}
+	if specs.Offset > 0 {
+		links.Prev = offset(max(0, specs.Offset-limit))
+	}
+	if result.HasMore {
+		links.Next = offset(specs.Offset + limit)
+	}
+	return links
+}
+
+// keysetLinks — cursor chỉ đi 1 chiều → không có last / prev
+func keysetLinks[T any](c *gin.Context, result *common.PaginateResult[T]) *common.PageLinks {
+	links := &common.PageLinks{First: pageURL(c, map[string]string{"cursor": ""}, "offset", "page")}
+	if result.HasMore && result.NextCursor != nil {
+		links.Next = pageURL(c, map[string]string{"cursor": fmt.Sprint(result.NextCursor)}, "offset", "page")
+	}
+	return links
+}
+
+// pageURL — URL tuyệt đối của request hiện tại, set các tham số trong params, bỏ các tham số drop
+func pageURL(c *gin.Context, params map[string]string, drop ...string) string {
+	query := c.Request.URL.Query()
+	for _, key := range drop {
+		query.Del(key)
+	}
+	for key, value := range params {
+		query.Set(key, value)
+	}
+
+	u := url.URL{
+		Scheme:   requestScheme(c),
+		Host:     c.Request.Host,
+		Path:     c.Request.URL.Path,
+		RawQuery: query.Encode(),
+	}
+	return u.String()
+}
+
+// requestScheme — sau reverse proxy (TLS terminate ở proxy) dựa vào X-Forwarded-Proto
+func requestScheme(c *gin.Context) string {
+	if c.Request.TLS != nil {
+		return "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto == "https" || proto == "http" {
+		return proto
+	}
+	return "http"
+}
+
+// linkHeader — <url>; rel="first", <url>; rel="next"...
+func linkHeader(links *common.PageLinks) string {
+	var parts []string
+	for _, link := range []struct{ rel, url string }{
+		{"first", links.First},
+		{"prev", links.Prev},
+		{"next", links.Next},
+		{"last", links.Last},
+	} {
+		if link.url != "" {
+			parts = append(parts, fmt.Sprintf("<%s>; rel=%q", link.url, link.rel))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,102 @@
+package base
+
+import (
+	"encoding"
+	"fmt"
+	"strconv"
+
+	"golang-base/pkg/apperror"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================================
+// ROUTE BINDING — parse path param / cursor thành kiểu key K
+//
+// Hỗ trợ:
+// → số nguyên: uint, uint64, uint32, int, int64, int32
+// → string: ULID, slug, mã tự sinh...
+// → mọi kiểu implement encoding.TextUnmarshaler: uuid.UUID, ulid.ULID...
+// ============================================================
+
+// ParseID — parse chuỗi raw sang K
+// VD: ParseID[uint]("15") → 15
+// VD: ParseID[uuid.UUID]("0190b6a1-7c2d-7d3e-9f00-5b2c8a1e4f10") → uuid.UUID
+func ParseID[K comparable](raw string) (K, error) {
+	var id K
+	if raw == "" {
+		return id, fmt.Errorf("id is required")
+	}
+
+	switch p := any(&id).(type) {
+	case *string:
+		*p = raw
+	case *uint:
+		v, err := strconv.ParseUint(raw, 10, strconv.IntSize)
+		if err != nil {
+			return id, fmt.Errorf("invalid id %q: %w", raw, err)
+		}
+		*p = uint(v)
+	case *uint64:
+		v, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return id, fmt.Errorf("invalid id %q: %w", raw, err)
+		}
+		*p = v
+	case *uint32:
+		v, err := strconv.ParseUint(raw, 10, 32)
+		if err != nil {
+			return id, fmt.Errorf("invalid id %q: %w", raw, err)
+		}
+		*p = uint32(v)
+	case *int:
+		v, err := strconv.ParseInt(raw, 10, strconv.IntSize)
+		if err != nil {
+			return id, fmt.Errorf("invalid id %q: %w", raw, err)
+		}
+		*p = int(v)
+	case *int64:
+		v, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return id, fmt.Errorf("invalid id %q: %w", raw, err)
+		}
+		*p = v
+	case *int32:
+		v, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil {
+			return id, fmt.Errorf("invalid id %q: %w", raw, err)
+		}
+		*p = int32(v)
+	case encoding.TextUnmarshaler:
+		if err := p.UnmarshalText([]byte(raw)); err != nil {
+			return id, fmt.Errorf("invalid id %q: %w", raw, err)
+		}
+	default:
+		return id, fmt.Errorf("unsupported id type %T", id)
+	}
+	return id, nil
+}
+
+// BindParam — path param sang K, sai → 400 INVALID_ID (đã trả response, handler chỉ cần return)
+// VD: DELETE /v1/users/abc/sessions → BindParam[uint](c, "id") → 400
+func BindParam[K comparable](c *gin.Context, name string) (K, bool) {
+	id, err := ParseID[K](c.Param(name))
+	if err != nil {
+		badRequest(c, apperror.ErrInvalidID, err)
+		return id, false
+	}
+	return id, true
+}
+
+// ParseIDs — parse nhiều id (VD: từ query "?ids=1,2,3" đã split)
+func ParseIDs[K comparable](raws []string) ([]K, error) {
+	ids := make([]K, 0, len(raws))
+	for _, raw := range raws {
+		id, err := ParseID[K](raw)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,97 @@
+package base
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestParseID(t *testing.T) {
+	if id, err := ParseID[uint]("15"); err != nil || id != 15 {
+		t.Fatalf("uint: got %d, %v", id, err)
+	}
+	if id, err := ParseID[int64]("-7"); err != nil || id != -7 {
+		t.Fatalf("int64: got %d, %v", id, err)
+	}
+	if id, err := ParseID[string]("01J2Y6ZQ7M3K8N9P0R1S2T3V4W"); err != nil || id != "01J2Y6ZQ7M3K8N9P0R1S2T3V4W" {
+		t.Fatalf("string: got %q, %v", id, err)
+	}
+	want := uuid.MustParse("0190b6a1-7c2d-7d3e-9f00-5b2c8a1e4f10")
+	if id, err := ParseID[uuid.UUID](want.String()); err != nil || id != want {
+		t.Fatalf("uuid: got %v, %v", id, err)
+	}
+
+	invalid := []struct {
+		name  string
+		parse func() error
+	}{
+		{"empty", func() error { _, err := ParseID[uint](""); return err }},
+		{"negative uint", func() error { _, err := ParseID[uint]("-1"); return err }},
+		{"not a number", func() error { _, err := ParseID[uint]("abc"); return err }},
+		{"uint32 overflow", func() error { _, err := ParseID[uint32]("4294967296"); return err }},
+		{"int32 overflow", func() error { _, err := ParseID[int32]("2147483648"); return err }},
+		{"sql in numeric id", func() error { _, err := ParseID[uint64]("1 OR 1=1"); return err }},
+		{"bad uuid", func() error { _, err := ParseID[uuid.UUID]("not-a-uuid"); return err }},
+		{"empty string key", func() error { _, err := ParseID[string](""); return err }},
+		{"unsupported type", func() error { _, err := ParseID[float64]("1.5"); return err }},
+	}
+	for _, tc := range invalid {
+		if err := tc.parse(); err == nil {
+			t.Fatalf("%s: expected error", tc.name)
+		}
+	}
+}
+
+func TestParseIDs(t *testing.T) {
+	ids, err := ParseIDs[uint]([]string{"1", "2", "3"})
+	if err != nil || len(ids) != 3 || ids[2] != 3 {
+		t.Fatalf("got %v, %v", ids, err)
+	}
+	if _, err := ParseIDs[uint]([]string{"1", "x"}); err == nil {
+		t.Fatal("one invalid id must fail the whole list")
+	}
+}
+
+func TestBindParam(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/items/:id", func(c *gin.Context) {
+		id, ok := BindParam[uuid.UUID](c, "id")
+		if !ok {
+			return
+		}
+		c.String(http.StatusOK, id.String())
+	})
+
+	call := func(raw string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/items/"+raw, nil))
+		return w
+	}
+	if w := call("0190b6a1-7c2d-7d3e-9f00-5b2c8a1e4f10"); w.Code != http.StatusOK {
+		t.Fatalf("valid uuid: expected 200, got %d", w.Code)
+	}
+	if w := call("15"); w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "INVALID_ID") {
+		t.Fatalf("invalid uuid: expected 400 INVALID_ID, got %d %s", w.Code, w.Body)
+	}
+}
+
+func TestParseSpecsCursorUsesKeyType(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	parse := func(query string) error {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/items?"+query, nil)
+		_, err := ParseSpecs[uuid.UUID](c, Options{})
+		return err
+	}
+	if err := parse("cursor=0190b6a1-7c2d-7d3e-9f00-5b2c8a1e4f10"); err != nil {
+		t.Fatalf("uuid cursor: %v", err)
+	}
+	if err := parse("cursor=15"); err == nil {
+		t.Fatal("numeric cursor on uuid key must be rejected")
+	}
+}
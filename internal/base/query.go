@@ -0,0 +1,198 @@
+package base
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang-base/global/common"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================================
+// QUERY → SPECS — query string của Index sang common.Specs
+//
+//	?keyword=admin                 → Keyword (tìm trên Options.Searchable)
+//	?filter[role]=admin            → Filters
+//	?filter[id][in]=1,2,3          → InFilters
+//	?filter[created_at][gte]=...   → RangeFilters.Min (lte → Max)
+//	?sort=-created_at,name         → "created_at desc, name asc"
+//	?include=Permissions           → Relations
+//	?limit=20&offset=40            → offset pagination
+//	?page=3&per_page=20            → page-number pagination (= limit 20, offset 40)
+//	?cursor=                       → keyset pagination (trang đầu), ?cursor=15 → trang sau (parse theo kiểu key K)
+//
+// Field không nằm trong whitelist của Options → 400, KHÔNG lặng lẽ bỏ qua
+// (client gõ sai tên filter mà vẫn nhận 200 + toàn bộ data thì khó phát hiện)
+// ============================================================
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// ParseSpecs — dựng Specs từ query string theo whitelist của opts
+// K: kiểu primary key, cursor sai kiểu (VD: ?cursor=abc với key uint) → lỗi như path id
+func ParseSpecs[K comparable](c *gin.Context, opts Options) (common.Specs, error) {
+	specs := *common.DefaultSpecs()
+	specs.KeywordFields = opts.Searchable
+	specs.Keyword = strings.TrimSpace(c.Query("keyword"))
+	specs.Limit = DefaultLimit
+
+	query := c.Request.URL.Query()
+
+	if err := parseFilters(query, opts, &specs); err != nil {
+		return specs, err
+	}
+
+	if raw := c.Query("include"); raw != "" {
+		for _, relation := range splitList(raw) {
+			if !slices.Contains(opts.Relations, relation) {
+				return specs, fmt.Errorf("include %q is not allowed", relation)
+			}
+			specs.Relations = append(specs.Relations, relation)
+		}
+	}
+
+	// per_page là tên khác của limit (đi cùng page)
+	for _, param := range []string{"limit", "per_page"} {
+		if raw := c.Query(param); raw != "" {
+			limit, err := strconv.Atoi(raw)
+			if err != nil || limit < 1 {
+				return specs, fmt.Errorf("invalid %s %q", param, raw)
+			}
+			specs.Limit = min(limit, opts.maxLimit())
+		}
+	}
+
+	page := 0
+	if raw := c.Query("page"); raw != "" {
+		var err error
+		if page, err = strconv.Atoi(raw); err != nil || page < 1 {
+			return specs, fmt.Errorf("invalid page %q", raw)
+		}
+	}
+
+	// Keyset: cursor có mặt (kể cả rỗng = trang đầu) → chỉ sort được theo primary key
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		if page > 0 {
+			return specs, fmt.Errorf("page cannot be combined with cursor")
+		}
+		specs.UseKeyset = true
+		specs.CursorField = opts.key()
+		specs.Sort = opts.key() + " desc"
+		if cursor != "" {
+			value, err := ParseID[K](cursor)
+			if err != nil {
+				return specs, fmt.Errorf("invalid cursor %q", cursor)
+			}
+			specs.CursorValue = value
+		}
+		switch sort := c.Query("sort"); sort {
+		case "", "-" + opts.key():
+		case opts.key():
+			specs.CursorDirection = "gt"
+			specs.Sort = opts.key() + " asc"
+		default:
+			return specs, fmt.Errorf("cursor pagination only supports sort=%s or sort=-%s", opts.key(), opts.key())
+		}
+		return specs, nil
+	}
+
+	if page > 0 {
+		if c.Query("offset") != "" {
+			return specs, fmt.Errorf("page cannot be combined with offset")
+		}
+		specs.Page, specs.PerPage = page, specs.Limit
+	} else if raw := c.Query("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return specs, fmt.Errorf("invalid offset %q", raw)
+		}
+		specs.Offset = offset
+	}
+
+	if raw := c.Query("sort"); raw != "" {
+		sort, err := parseSort(raw, opts)
+		if err != nil {
+			return specs, err
+		}
+		specs.Sort = sort
+	}
+	return specs, nil
+}
+
+// parseFilters — filter[field]=v, filter[field][in]=a,b, filter[field][gte|lte]=v
+// Không dùng c.QueryMap: gin cắt key ở dấu "]" đầu tiên → filter[id][in] thành "id"
+func parseFilters(query map[string][]string, opts Options, specs *common.Specs) error {
+	for key, values := range query {
+		if !strings.HasPrefix(key, "filter[") || !strings.HasSuffix(key, "]") || len(values) == 0 {
+			continue
+		}
+		parts := strings.Split(strings.TrimSuffix(strings.TrimPrefix(key, "filter["), "]"), "][")
+		field, value := parts[0], values[0]
+		if !slices.Contains(opts.Filterable, field) {
+			return fmt.Errorf("filter %q is not allowed", field)
+		}
+
+		op := ""
+		if len(parts) == 2 {
+			op = parts[1]
+		} else if len(parts) > 2 {
+			return fmt.Errorf("invalid filter %q", key)
+		}
+
+		switch op {
+		case "", "eq":
+			specs.Filters[field] = value
+		case "in":
+			items := splitList(value)
+			list := make([]any, len(items))
+			for i, item := range items {
+				list[i] = item
+			}
+			specs.InFilters[field] = list
+		case "gte", "lte":
+			rf := specs.RangeFilters[field]
+			if op == "gte" {
+				rf.Min = value
+			} else {
+				rf.Max = value
+			}
+			specs.RangeFilters[field] = rf
+		default:
+			return fmt.Errorf("unsupported filter operator %q", op)
+		}
+	}
+	return nil
+}
+
+// parseSort — "-created_at,name" → "created_at desc, name asc"
+func parseSort(raw string, opts Options) (string, error) {
+	items := splitList(raw)
+	orders := make([]string, 0, len(items))
+	for _, item := range items {
+		field, direction := item, "asc"
+		if strings.HasPrefix(item, "-") {
+			field, direction = item[1:], "desc"
+		}
+		if field != opts.key() && !slices.Contains(opts.Sortable, field) {
+			return "", fmt.Errorf("sort %q is not allowed", field)
+		}
+		orders = append(orders, field+" "+direction)
+	}
+	return strings.Join(orders, ", "), nil
+}
+
+// splitList — "a, b,,c" → ["a", "b", "c"]
+func splitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,82 @@
+package user
+
+import (
+	c "golang-base/internal/base"
+	"golang-base/internal/model"
+	"golang-base/internal/serializer"
+	us "golang-base/internal/service/user"
+	response "golang-base/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyController — quản lý API key (permission apikey.view / apikey.manage)
+type APIKeyController struct {
+	service *us.APIKeyService
+}
+
+func NewAPIKeyController(service *us.APIKeyService) *APIKeyController {
+	return &APIKeyController{service: service}
+}
+
+// List — GET /v1/api-keys
+func (h *APIKeyController) List(ctx *gin.Context) {
+	keys, err := h.service.List(ctx.Request.Context())
+	if err != nil {
+		c.RespondError(ctx, err)
+		return
+	}
+	response.OK(ctx, serializer.APIKey.Many(keys))
+}
+
+// Create — POST /v1/api-keys
+func (h *APIKeyController) Create(ctx *gin.Context) {
+	var in us.CreateAPIKeyInput
+	if !c.BindJSON(ctx, &in) {
+		return
+	}
+	key, raw, err := h.service.Create(ctx.Request.Context(), in)
+	if err != nil {
+		c.RespondError(ctx, err)
+		return
+	}
+	response.Created(ctx, issued(ctx, key, raw))
+}
+
+// Rotate — POST /v1/api-keys/:id/rotate
+func (h *APIKeyController) Rotate(ctx *gin.Context) {
+	id, ok := c.BindParam[uint64](ctx, "id")
+	if !ok {
+		return
+	}
+	var in us.RotateAPIKeyInput
+	if ctx.Request.ContentLength > 0 {
+		if !c.BindJSON(ctx, &in) {
+			return
+		}
+	}
+	key, raw, err := h.service.Rotate(ctx.Request.Context(), id, in)
+	if err != nil {
+		c.RespondError(ctx, err)
+		return
+	}
+	response.Created(ctx, issued(ctx, key, raw))
+}
+
+// Revoke — DELETE /v1/api-keys/:id
+func (h *APIKeyController) Revoke(ctx *gin.Context) {
+	id, ok := c.BindParam[uint64](ctx, "id")
+	if !ok {
+		return
+	}
+	if err := h.service.Revoke(ctx.Request.Context(), id); err != nil {
+		c.RespondError(ctx, err)
+		return
+	}
+	response.OK(ctx, nil)
+}
+
+// issued — metadata + key gốc, client phải lưu lại ngay (không xem lại được)
+func issued(ctx *gin.Context, key *model.APIKey, raw string) gin.H {
+	return gin.H{"api_key": serializer.APIKey.Serialize(ctx.Request.Context(), key, nil), "key": raw}
+}
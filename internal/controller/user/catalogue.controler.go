@@ -1,18 +1,59 @@
 package user
 
 import (
+	"context"
+
 	c "golang-base/internal/base"
+	"golang-base/internal/model"
+	"golang-base/internal/serializer"
+	us "golang-base/internal/service/user"
+	response "golang-base/pkg/response"
 
 	"github.com/gin-gonic/gin"
 )
 
+// UserCatalogueController — CRUD nhóm user: handler chuẩn lấy từ BaseController,
+// chỉ viết thêm publish / unpublish hàng loạt
+//
+//	GET /v1/user-catalogues?keyword=admin&filter[role]=admin&filter[publish]=2&sort=-created_at
+//
+// filter[role] chỉ dành cho admin: serializer ẩn role với user khác → BaseController bỏ khỏi Filterable
 type UserCatalogueController struct {
-	c.BaseController[UserCatalogueController]
+	*c.BaseController[model.UserCatalogue, uint]
+	service *us.CatalogueService
+}
+
+func NewUserCatalogueController(service *us.CatalogueService) *UserCatalogueController {
+	return &UserCatalogueController{
+		BaseController: c.NewBaseController(service, serializer.UserCatalogue, c.Options{
+			Fillable:   []string{"name", "slug", "description", "role", "publish", "require_two_factor"},
+			Filterable: []string{"role", "publish"},
+			Sortable:   []string{"name", "publish", "created_at", "updated_at"},
+			Searchable: []string{"name", "slug"},
+		}),
+		service: service,
+	}
+}
+
+// Publish — POST /v1/user-catalogues/bulk/publish
+func (h *UserCatalogueController) Publish(ctx *gin.Context) {
+	h.setPublish(ctx, h.service.Publish)
 }
 
-func NewUserCatalogueController() *UserCatalogueController {
-	return &UserCatalogueController{}
+// Unpublish — POST /v1/user-catalogues/bulk/unpublish, chuyển về draft
+func (h *UserCatalogueController) Unpublish(ctx *gin.Context) {
+	h.setPublish(ctx, h.service.Unpublish)
 }
 
-func (h *UserCatalogueController) Paginate(c *gin.Context) {
+func (h *UserCatalogueController) setPublish(ctx *gin.Context, apply func(ctx context.Context, in us.BulkPublishInput) (int64, error)) {
+	var in us.BulkPublishInput
+	if !c.BindJSON(ctx, &in) {
+		return
+	}
+	affected, err := apply(ctx.Request.Context(), in)
+	if err != nil {
+		c.RespondError(ctx, err)
+		return
+	}
+	response.OK(ctx, gin.H{"affected": affected})
 }
@@ -0,0 +1,38 @@
+package user
+
+import (
+	"net/http"
+
+	"golang-base/internal/auth/throttle"
+	us "golang-base/internal/service/user"
+	"golang-base/pkg/apperror"
+)
+
+// Mã lỗi của module user — Match sentinel của service → base.RespondError tự map, controller không cần switch
+var (
+	// Đăng nhập / token
+	ErrInvalidCredentials        = apperror.Define(http.StatusUnauthorized, "INVALID_CREDENTIALS", "user.invalid_credentials", "invalid credentials").Match(us.ErrInvalidCredentials)
+	ErrInvalidRefreshToken       = apperror.Define(http.StatusUnauthorized, "INVALID_REFRESH_TOKEN", "user.invalid_refresh_token", "invalid or expired refresh token").Match(us.ErrInvalidRefreshToken)
+	ErrRefreshTokenReused        = apperror.Define(http.StatusUnauthorized, "REFRESH_TOKEN_REUSED", "user.refresh_token_reused", "refresh token reuse detected").Match(us.ErrRefreshTokenReused)
+	ErrInvalidResetToken         = apperror.Define(http.StatusBadRequest, "INVALID_RESET_TOKEN", "user.invalid_reset_token", "invalid or expired reset token").Match(us.ErrInvalidResetToken)
+	ErrTooManyAttempts           = apperror.Define(http.StatusTooManyRequests, "TOO_MANY_ATTEMPTS", "user.too_many_attempts", "too many failed attempts, please try again later").Match(throttle.ErrTooManyAttempts)
+	ErrAccountLocked             = apperror.Define(http.StatusTooManyRequests, "ACCOUNT_LOCKED", "user.account_locked", "temporarily locked due to too many failed attempts").Match(throttle.ErrLocked)
+	ErrInvalidUnlockToken        = apperror.Define(http.StatusBadRequest, "INVALID_UNLOCK_TOKEN", "user.invalid_unlock_token", "invalid or expired unlock token").Match(throttle.ErrInvalidUnlockToken)
+	ErrInvalidTwoFactorChallenge = apperror.Define(http.StatusUnauthorized, "INVALID_TWO_FACTOR_CHALLENGE", "user.invalid_two_factor_challenge", "invalid or expired two-factor challenge").Match(us.ErrInvalidTwoFactorChallenge)
+	ErrInvalidTwoFactorCode      = apperror.Define(http.StatusUnauthorized, "INVALID_TWO_FACTOR_CODE", "user.invalid_two_factor_code", "invalid two-factor code").Match(us.ErrInvalidTwoFactorCode)
+	ErrTokenWithoutSession       = apperror.Define(http.StatusBadRequest, "TOKEN_WITHOUT_SESSION", "user.token_without_session", "token has no session")
+
+	// 2FA
+	ErrTwoFactorEnabled    = apperror.Define(http.StatusBadRequest, "TWO_FACTOR_ALREADY_ENABLED", "user.two_factor_already_enabled", "two-factor authentication already enabled").Match(us.ErrTwoFactorEnabled)
+	ErrTwoFactorNotEnabled = apperror.Define(http.StatusBadRequest, "TWO_FACTOR_NOT_ENABLED", "user.two_factor_not_enabled", "two-factor authentication not enabled").Match(us.ErrTwoFactorNotEnabled)
+	ErrTwoFactorMandatory  = apperror.Define(http.StatusForbidden, "TWO_FACTOR_MANDATORY", "user.two_factor_mandatory", "two-factor authentication is required for your role").Match(us.ErrTwoFactorRequired)
+
+	// Không tìm thấy
+	ErrUserNotFound      = apperror.Define(http.StatusNotFound, "USER_NOT_FOUND", "user.not_found", "user not found").Match(us.ErrUserNotFound)
+	ErrSessionNotFound   = apperror.Define(http.StatusNotFound, "SESSION_NOT_FOUND", "user.session_not_found", "session not found").Match(us.ErrSessionNotFound)
+	ErrAPIKeyNotFound    = apperror.Define(http.StatusNotFound, "API_KEY_NOT_FOUND", "user.api_key_not_found", "api key not found").Match(us.ErrAPIKeyNotFound)
+	ErrCatalogueNotFound = apperror.Define(http.StatusNotFound, "CATALOGUE_NOT_FOUND", "catalogue.not_found", "user catalogue not found").Match(us.ErrCatalogueNotFound)
+
+	// Catalogue — errors giữ danh sách lỗi field như VALIDATION_FAILED
+	ErrCatalogueSlugTaken = apperror.Define(http.StatusUnprocessableEntity, "CATALOGUE_SLUG_TAKEN", "catalogue.slug_taken", "catalogue slug already taken").Match(us.ErrSlugTaken)
+)
@@ -0,0 +1,58 @@
+package user
+
+import (
+	c "golang-base/internal/base"
+	us "golang-base/internal/service/user"
+	response "golang-base/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LockoutController — mở khóa tài khoản bị khóa do đăng nhập sai nhiều lần
+type LockoutController struct {
+	service *us.LockoutService
+}
+
+func NewLockoutController(service *us.LockoutService) *LockoutController {
+	return &LockoutController{service: service}
+}
+
+// Unlock — POST /v1/auth/unlock, token trong mail gửi khi tài khoản bị khóa
+func (h *LockoutController) Unlock(ctx *gin.Context) {
+	var in us.UnlockAccountInput
+	if !c.BindJSON(ctx, &in) {
+		return
+	}
+	if err := h.service.Unlock(ctx.Request.Context(), in); err != nil {
+		c.RespondError(ctx, err)
+		return
+	}
+	response.OK(ctx, nil)
+}
+
+// Status — GET /v1/users/:id/lockout
+func (h *LockoutController) Status(ctx *gin.Context) {
+	userID, ok := c.BindParam[uint](ctx, "id")
+	if !ok {
+		return
+	}
+	status, err := h.service.Status(ctx.Request.Context(), userID)
+	if err != nil {
+		c.RespondError(ctx, err)
+		return
+	}
+	response.OK(ctx, status)
+}
+
+// Release — DELETE /v1/users/:id/lockout, admin mở khóa
+func (h *LockoutController) Release(ctx *gin.Context) {
+	userID, ok := c.BindParam[uint](ctx, "id")
+	if !ok {
+		return
+	}
+	if err := h.service.Release(ctx.Request.Context(), userID); err != nil {
+		c.RespondError(ctx, err)
+		return
+	}
+	response.OK(ctx, nil)
+}
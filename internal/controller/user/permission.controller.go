@@ -0,0 +1,75 @@
+package user
+
+import (
+	"golang-base/internal/auth"
+	c "golang-base/internal/base"
+	us "golang-base/internal/service/user"
+	response "golang-base/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PermissionController — permission + gán permission cho user catalogue
+type PermissionController struct {
+	service *us.PermissionService
+}
+
+func NewPermissionController(service *us.PermissionService) *PermissionController {
+	return &PermissionController{service: service}
+}
+
+// List — GET /v1/permissions
+func (h *PermissionController) List(ctx *gin.Context) {
+	permissions, err := h.service.List(ctx.Request.Context())
+	if err != nil {
+		c.RespondError(ctx, err)
+		return
+	}
+	response.OK(ctx, permissions)
+}
+
+// Catalogue — GET /v1/user-catalogues/:id/permissions
+func (h *PermissionController) Catalogue(ctx *gin.Context) {
+	id, ok := c.BindParam[uint](ctx, "id")
+	if !ok {
+		return
+	}
+	names, err := h.service.CataloguePermissions(ctx.Request.Context(), id)
+	if err != nil {
+		c.RespondError(ctx, err)
+		return
+	}
+	response.OK(ctx, gin.H{"permissions": names})
+}
+
+// SyncCatalogue — PUT /v1/user-catalogues/:id/permissions
+// Body: {"permissions": ["user.view", "catalogue.update"]} → thay thế toàn bộ
+func (h *PermissionController) SyncCatalogue(ctx *gin.Context) {
+	id, ok := c.BindParam[uint](ctx, "id")
+	if !ok {
+		return
+	}
+	var in us.SyncPermissionsInput
+	if !c.BindJSON(ctx, &in) {
+		return
+	}
+	names, err := h.service.SyncCatalogue(ctx.Request.Context(), id, in)
+	if err != nil {
+		c.RespondError(ctx, err)
+		return
+	}
+	response.OK(ctx, gin.H{"permissions": names})
+}
+
+// Mine — GET /v1/me/permissions, role + permission của user đang đăng nhập
+func (h *PermissionController) Mine(ctx *gin.Context) {
+	p, ok := auth.PrincipalFrom(ctx.Request.Context())
+	if !ok {
+		c.RespondError(ctx, auth.ErrUnauthenticated)
+		return
+	}
+	response.OK(ctx, gin.H{
+		"roles":       p.Roles,
+		"permissions": p.Permissions,
+	})
+}
@@ -0,0 +1,136 @@
+package user
+
+import (
+	"golang-base/internal/auth"
+	c "golang-base/internal/base"
+	"golang-base/internal/serializer"
+	us "golang-base/internal/service/user"
+	response "golang-base/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SessionController — đăng xuất + quản lý session đăng nhập
+type SessionController struct {
+	service *us.SessionService
+}
+
+func NewSessionController(service *us.SessionService) *SessionController {
+	return &SessionController{service: service}
+}
+
+// ============================================================
+// USER ĐANG ĐĂNG NHẬP
+// ============================================================
+
+// Logout — POST /v1/auth/logout, thu hồi session hiện tại
+func (h *SessionController) Logout(ctx *gin.Context) {
+	userID, ok := c.RequireUserID[uint](ctx)
+	if !ok {
+		return
+	}
+	sessionID := currentSessionID(ctx)
+	if sessionID == "" {
+		c.RespondError(ctx, ErrTokenWithoutSession)
+		return
+	}
+	if err := h.service.Revoke(ctx.Request.Context(), userID, sessionID); err != nil {
+		c.RespondError(ctx, err)
+		return
+	}
+	response.OK(ctx, nil)
+}
+
+// LogoutAll — POST /v1/auth/logout-all, thu hồi mọi session (kể cả hiện tại)
+func (h *SessionController) LogoutAll(ctx *gin.Context) {
+	userID, ok := c.RequireUserID[uint](ctx)
+	if !ok {
+		return
+	}
+	h.revokeAll(ctx, userID)
+}
+
+// Mine — GET /v1/me/sessions
+func (h *SessionController) Mine(ctx *gin.Context) {
+	userID, ok := c.RequireUserID[uint](ctx)
+	if !ok {
+		return
+	}
+	h.list(ctx, userID)
+}
+
+// RevokeMine — DELETE /v1/me/sessions/:sid, đăng xuất 1 thiết bị khác
+func (h *SessionController) RevokeMine(ctx *gin.Context) {
+	userID, ok := c.RequireUserID[uint](ctx)
+	if !ok {
+		return
+	}
+	h.revoke(ctx, userID)
+}
+
+// ============================================================
+// ADMIN — session của user bất kỳ (permission session.view / session.revoke)
+// ============================================================
+
+// List — GET /v1/users/:id/sessions
+func (h *SessionController) List(ctx *gin.Context) {
+	userID, ok := c.BindParam[uint](ctx, "id")
+	if !ok {
+		return
+	}
+	h.list(ctx, userID)
+}
+
+// Revoke — DELETE /v1/users/:id/sessions/:sid
+func (h *SessionController) Revoke(ctx *gin.Context) {
+	userID, ok := c.BindParam[uint](ctx, "id")
+	if !ok {
+		return
+	}
+	h.revoke(ctx, userID)
+}
+
+// RevokeAll — DELETE /v1/users/:id/sessions, kill switch cho tài khoản bị chiếm
+func (h *SessionController) RevokeAll(ctx *gin.Context) {
+	userID, ok := c.BindParam[uint](ctx, "id")
+	if !ok {
+		return
+	}
+	h.revokeAll(ctx, userID)
+}
+
+func (h *SessionController) list(ctx *gin.Context, userID uint) {
+	sessions, err := h.service.List(ctx.Request.Context(), userID)
+	if err != nil {
+		c.RespondError(ctx, err)
+		return
+	}
+	response.OK(ctx, serializer.Session.Many(sessions))
+}
+
+func (h *SessionController) revoke(ctx *gin.Context, userID uint) {
+	if err := h.service.Revoke(ctx.Request.Context(), userID, ctx.Param("sid")); err != nil {
+		c.RespondError(ctx, err)
+		return
+	}
+	response.OK(ctx, nil)
+}
+
+func (h *SessionController) revokeAll(ctx *gin.Context, userID uint) {
+	revoked, err := h.service.RevokeAll(ctx.Request.Context(), userID)
+	if err != nil {
+		c.RespondError(ctx, err)
+		return
+	}
+	response.OK(ctx, gin.H{"revoked": revoked})
+}
+
+// currentSessionID — session của access token đang dùng
+func currentSessionID(ctx *gin.Context) string {
+	p, ok := auth.PrincipalFrom(ctx.Request.Context())
+	if !ok {
+		return ""
+	}
+	id, _ := p.Attributes["session_id"].(string)
+	return id
+}
@@ -0,0 +1,100 @@
+package user
+
+import (
+	c "golang-base/internal/base"
+	us "golang-base/internal/service/user"
+	response "golang-base/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TwoFactorController — user tự bật / tắt 2FA, tạo lại mã dự phòng
+type TwoFactorController struct {
+	service *us.TwoFactorService
+}
+
+func NewTwoFactorController(service *us.TwoFactorService) *TwoFactorController {
+	return &TwoFactorController{service: service}
+}
+
+// Status — GET /v1/me/two-factor
+func (h *TwoFactorController) Status(ctx *gin.Context) {
+	userID, ok := c.RequireUserID[uint](ctx)
+	if !ok {
+		return
+	}
+	status, err := h.service.Status(ctx.Request.Context(), userID)
+	if err != nil {
+		c.RespondError(ctx, err)
+		return
+	}
+	response.OK(ctx, status)
+}
+
+// Enroll — POST /v1/me/two-factor, sinh secret + otpauth URI để quét QR
+func (h *TwoFactorController) Enroll(ctx *gin.Context) {
+	userID, ok := c.RequireUserID[uint](ctx)
+	if !ok {
+		return
+	}
+	enrollment, err := h.service.Enroll(ctx.Request.Context(), userID)
+	if err != nil {
+		c.RespondError(ctx, err)
+		return
+	}
+	response.Created(ctx, enrollment)
+}
+
+// Confirm — POST /v1/me/two-factor/confirm, nhập mã đầu tiên để kích hoạt
+// Access token hiện tại chưa có amr "otp" → client gọi /auth/refresh để lấy token mới
+func (h *TwoFactorController) Confirm(ctx *gin.Context) {
+	userID, ok := c.RequireUserID[uint](ctx)
+	if !ok {
+		return
+	}
+	var in us.ConfirmTwoFactorInput
+	if !c.BindJSON(ctx, &in) {
+		return
+	}
+	codes, err := h.service.Confirm(ctx.Request.Context(), userID, currentSessionID(ctx), in)
+	if err != nil {
+		c.RespondError(ctx, err)
+		return
+	}
+	response.OK(ctx, gin.H{"recovery_codes": codes})
+}
+
+// Disable — DELETE /v1/me/two-factor
+func (h *TwoFactorController) Disable(ctx *gin.Context) {
+	userID, ok := c.RequireUserID[uint](ctx)
+	if !ok {
+		return
+	}
+	var in us.DisableTwoFactorInput
+	if !c.BindJSON(ctx, &in) {
+		return
+	}
+	if err := h.service.Disable(ctx.Request.Context(), userID, in); err != nil {
+		c.RespondError(ctx, err)
+		return
+	}
+	response.OK(ctx, nil)
+}
+
+// RegenerateRecoveryCodes — POST /v1/me/two-factor/recovery-codes
+func (h *TwoFactorController) RegenerateRecoveryCodes(ctx *gin.Context) {
+	userID, ok := c.RequireUserID[uint](ctx)
+	if !ok {
+		return
+	}
+	var in us.RegenerateRecoveryCodesInput
+	if !c.BindJSON(ctx, &in) {
+		return
+	}
+	codes, err := h.service.RegenerateRecoveryCodes(ctx.Request.Context(), userID, in)
+	if err != nil {
+		c.RespondError(ctx, err)
+		return
+	}
+	response.OK(ctx, gin.H{"recovery_codes": codes})
+}
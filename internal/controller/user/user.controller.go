@@ -0,0 +1,264 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+
+	"golang-base/internal/auth"
+	"golang-base/internal/auth/throttle"
+	c "golang-base/internal/base"
+	"golang-base/internal/model"
+	"golang-base/internal/serializer"
+	us "golang-base/internal/service/user"
+	"golang-base/pkg/apperror"
+	response "golang-base/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserController — đăng ký, đăng nhập (kèm bước 2FA, chống dò mật khẩu), hồ sơ, mật khẩu
+type UserController struct {
+	service   *us.UserService
+	tokens    *us.TokenService
+	twoFactor *us.TwoFactorService
+	lockout   *us.LockoutService
+}
+
+func NewUserController(service *us.UserService, tokens *us.TokenService, twoFactor *us.TwoFactorService, lockout *us.LockoutService) *UserController {
+	return &UserController{service: service, tokens: tokens, twoFactor: twoFactor, lockout: lockout}
+}
+
+// ============================================================
+// AUTH — không cần đăng nhập
+// ============================================================
+
+// Register — POST /v1/auth/register
+func (h *UserController) Register(ctx *gin.Context) {
+	var in us.RegisterInput
+	if !c.BindJSON(ctx, &in) {
+		return
+	}
+	user, err := h.service.Register(ctx.Request.Context(), in)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+	response.Created(ctx, serializer.User.Serialize(asSelf(ctx, user), user, nil))
+}
+
+// Login — POST /v1/auth/login
+// User đã bật 2FA → chưa cấp token, trả pending token để gọi /auth/two-factor/verify
+// Sai nhiều lần (theo email + IP) → 429 kèm Retry-After
+func (h *UserController) Login(ctx *gin.Context) {
+	var in us.LoginInput
+	if !c.BindJSON(ctx, &in) {
+		return
+	}
+	if err := h.lockout.Check(ctx.Request.Context(), in.Email, ctx.ClientIP()); err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+	user, err := h.service.Login(ctx.Request.Context(), in)
+	if errors.Is(err, us.ErrInvalidCredentials) {
+		if ferr := h.lockout.Failed(ctx.Request.Context(), in.Email, ctx.ClientIP()); ferr != nil {
+			_ = ctx.Error(ferr)
+		}
+	}
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+	if err := h.lockout.Succeeded(ctx.Request.Context(), in.Email); err != nil {
+		_ = ctx.Error(err)
+	}
+
+	challenge, err := h.twoFactor.Challenge(ctx.Request.Context(), user)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+	if challenge != nil {
+		response.OK(ctx, gin.H{"two_factor_required": true, "challenge": challenge})
+		return
+	}
+
+	h.issue(ctx, user, sessionMeta(ctx, in.DeviceName))
+}
+
+// VerifyTwoFactor — POST /v1/auth/two-factor/verify, bước 2 của đăng nhập
+func (h *UserController) VerifyTwoFactor(ctx *gin.Context) {
+	var in us.VerifyTwoFactorInput
+	if !c.BindJSON(ctx, &in) {
+		return
+	}
+	// Chưa biết email ở bước này → chỉ đếm theo IP (số lần thử / pending token đã giới hạn riêng)
+	// account rỗng không phải bucket chung: không delay, không khóa tài khoản nào
+	// → user khác sau cùng NAT chỉ bị chặn khi IP vượt IPMaxAttempts (xem throttle.TestIPOnlyFailures)
+	if err := h.lockout.Check(ctx.Request.Context(), "", ctx.ClientIP()); err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+	user, err := h.twoFactor.Verify(ctx.Request.Context(), in)
+	if errors.Is(err, us.ErrInvalidTwoFactorCode) {
+		if ferr := h.lockout.Failed(ctx.Request.Context(), "", ctx.ClientIP()); ferr != nil {
+			_ = ctx.Error(ferr)
+		}
+	}
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+
+	meta := sessionMeta(ctx, in.DeviceName)
+	meta.TwoFactor = true
+	h.issue(ctx, user, meta)
+}
+
+// issue — cấp token cho lần đăng nhập
+// two_factor_setup_required: nhóm bắt buộc 2FA mà user chưa bật → chỉ gọi được API bật 2FA
+func (h *UserController) issue(ctx *gin.Context, user *model.User, meta us.SessionMeta) {
+	pair, err := h.tokens.Issue(ctx.Request.Context(), user, meta)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+	setupRequired := false
+	if !meta.TwoFactor {
+		if setupRequired, err = h.twoFactor.Required(ctx.Request.Context(), user.ID); err != nil {
+			h.respondError(ctx, err)
+			return
+		}
+	}
+	response.OK(ctx, gin.H{
+		"user":                      serializer.User.Serialize(asSelf(ctx, user), user, nil),
+		"tokens":                    pair,
+		"two_factor_setup_required": setupRequired,
+	})
+}
+
+// Refresh — POST /v1/auth/refresh, đổi refresh token lấy cặp token mới
+func (h *UserController) Refresh(ctx *gin.Context) {
+	var in us.RefreshInput
+	if !c.BindJSON(ctx, &in) {
+		return
+	}
+	pair, err := h.tokens.Refresh(ctx.Request.Context(), in.RefreshToken, sessionMeta(ctx, ""))
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+	response.OK(ctx, pair)
+}
+
+// ForgotPassword — POST /v1/auth/password/forgot
+// Luôn trả 200 dù email có tồn tại hay không
+func (h *UserController) ForgotPassword(ctx *gin.Context) {
+	var in us.ForgotPasswordInput
+	if !c.BindJSON(ctx, &in) {
+		return
+	}
+	if err := h.service.RequestPasswordReset(ctx.Request.Context(), in); err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+	response.OK(ctx, nil)
+}
+
+// ResetPassword — POST /v1/auth/password/reset
+func (h *UserController) ResetPassword(ctx *gin.Context) {
+	var in us.ResetPasswordInput
+	if !c.BindJSON(ctx, &in) {
+		return
+	}
+	if err := h.service.ResetPassword(ctx.Request.Context(), in); err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+	response.OK(ctx, nil)
+}
+
+// ============================================================
+// ME — user đang đăng nhập
+// ============================================================
+
+// Profile — GET /v1/me
+func (h *UserController) Profile(ctx *gin.Context) {
+	id, ok := c.RequireUserID[uint](ctx)
+	if !ok {
+		return
+	}
+	user, err := h.service.Profile(ctx.Request.Context(), id)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+	response.OK(ctx, serializer.User.One(user))
+}
+
+// UpdateProfile — PUT /v1/me
+func (h *UserController) UpdateProfile(ctx *gin.Context) {
+	id, ok := c.RequireUserID[uint](ctx)
+	if !ok {
+		return
+	}
+	var in us.UpdateProfileInput
+	if !c.BindJSON(ctx, &in) {
+		return
+	}
+	user, err := h.service.UpdateProfile(ctx.Request.Context(), id, in)
+	if err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+	response.OK(ctx, serializer.User.One(user))
+}
+
+// ChangePassword — PUT /v1/me/password
+func (h *UserController) ChangePassword(ctx *gin.Context) {
+	id, ok := c.RequireUserID[uint](ctx)
+	if !ok {
+		return
+	}
+	var in us.ChangePasswordInput
+	if !c.BindJSON(ctx, &in) {
+		return
+	}
+	if err := h.service.ChangePassword(ctx.Request.Context(), id, in); err != nil {
+		h.respondError(ctx, err)
+		return
+	}
+	response.OK(ctx, nil)
+}
+
+// asSelf — request đăng ký / đăng nhập chưa có principal
+// → gắn chính user đó để response có field chỉ chủ sở hữu được xem (email...)
+func asSelf(ctx *gin.Context, user *model.User) context.Context {
+	return auth.WithPrincipal(ctx.Request.Context(), &auth.Principal{
+		ID:   strconv.FormatUint(uint64(user.ID), 10),
+		Kind: auth.KindUser,
+	})
+}
+
+// sessionMeta — thiết bị của request, device rỗng → lấy User-Agent
+func sessionMeta(ctx *gin.Context, device string) us.SessionMeta {
+	userAgent := ctx.Request.UserAgent()
+	if device == "" {
+		device = userAgent
+	}
+	return us.SessionMeta{Device: device, UserAgent: userAgent, IP: ctx.ClientIP()}
+}
+
+// respondError — bị chặn do đăng nhập sai nhiều lần → header Retry-After, còn lại → base.RespondError
+// Code của module xem errors.go
+func (h *UserController) respondError(ctx *gin.Context, err error) {
+	var retry *throttle.RetryError
+	if errors.As(err, &retry) {
+		seconds := int(math.Ceil(retry.RetryAfter.Seconds()))
+		ctx.Header("Retry-After", strconv.Itoa(seconds))
+		c.RespondError(ctx, apperror.From(err).WithDetails(gin.H{"retry_after": seconds}))
+		return
+	}
+	c.RespondError(ctx, err)
+}
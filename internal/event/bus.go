@@ -0,0 +1,152 @@
+// ============================================================
+// EVENT BUS — In-process domain event bus (typed)
+//
+// BaseService tự publish EntityCreated / EntityUpdated / EntityDeleted
+// → module khác subscribe để phản ứng mà KHÔNG cần sửa hook của module gốc
+// → cache invalidation, search indexing, notification... tách rời nhau
+//
+// Khác outbox (internal/outbox):
+// → bus: in-memory, chạy ngay trong process, mất event nếu crash
+// → outbox: lưu DB, at-least-once, dùng cho event quan trọng / gửi ra ngoài
+// ============================================================
+package event
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// Handler — hàm xử lý event kiểu E
+type Handler[E any] func(ctx context.Context, e E) error
+
+type subscription struct {
+	id    uint64
+	name  string // tên handler, dùng để log
+	async bool
+	fn    func(ctx context.Context, e any) error
+}
+
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[reflect.Type][]subscription
+	nextID   uint64
+	log      *zap.Logger
+	wg       sync.WaitGroup // theo dõi async handler đang chạy
+}
+
+func NewBus(log *zap.Logger) *Bus {
+	return &Bus{
+		handlers: make(map[reflect.Type][]subscription),
+		log:      log,
+	}
+}
+
+// Subscribe — đăng ký handler chạy ĐỒNG BỘ trong lúc Publish
+// Dùng khi: cần xong trước khi request trả về (VD: xóa cache)
+// Trả về hàm unsubscribe
+//
+// VD:
+//
+//	event.Subscribe(bus, "catalogue.cache", func(ctx context.Context, e event.EntityUpdated[model.UserCatalogue, uint]) error {
+//	    return cache.Delete(ctx, e.ID)
+//	})
+func Subscribe[E any](b *Bus, name string, fn Handler[E]) func() {
+	return subscribe(b, name, false, fn)
+}
+
+// SubscribeAsync — đăng ký handler chạy trong goroutine riêng
+// Dùng khi: tác vụ chậm, không cần chờ (VD: gửi notification, index search)
+func SubscribeAsync[E any](b *Bus, name string, fn Handler[E]) func() {
+	return subscribe(b, name, true, fn)
+}
+
+func subscribe[E any](b *Bus, name string, async bool, fn Handler[E]) func() {
+	key := reflect.TypeFor[E]()
+
+	b.mu.Lock()
+	b.nextID++
+	id := b.nextID
+	b.handlers[key] = append(b.handlers[key], subscription{
+		id:    id,
+		name:  name,
+		async: async,
+		fn: func(ctx context.Context, e any) error {
+			return fn(ctx, e.(E))
+		},
+	})
+	b.mu.Unlock()
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		subs := b.handlers[key]
+		for i, sub := range subs {
+			if sub.id == id {
+				b.handlers[key] = append(subs[:i:i], subs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Publish — gửi event tới mọi handler đã đăng ký cho kiểu E
+//
+// Cô lập lỗi theo từng handler:
+// → handler lỗi / panic chỉ bị log lại, KHÔNG ảnh hưởng handler khác
+// → KHÔNG trả lỗi về publisher (thao tác ghi data đã thành công trước đó)
+//
+// Bus nil → bỏ qua, service không bắt buộc phải có bus
+func Publish[E any](ctx context.Context, b *Bus, e E) {
+	if b == nil {
+		return
+	}
+
+	b.mu.RLock()
+	subs := append([]subscription(nil), b.handlers[reflect.TypeFor[E]()]...)
+	b.mu.RUnlock()
+
+	for _, sub := range subs {
+		if !sub.async {
+			b.run(ctx, sub, e)
+			continue
+		}
+
+		// Async: tách khỏi cancel của request, handler vẫn chạy sau khi response trả về
+		asyncCtx := context.WithoutCancel(ctx)
+		b.wg.Add(1)
+		go func(sub subscription) {
+			defer b.wg.Done()
+			b.run(asyncCtx, sub, e)
+		}(sub)
+	}
+}
+
+// run — chạy 1 handler với recover + log lỗi
+func (b *Bus) run(ctx context.Context, sub subscription, e any) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.log.Error("event handler panic recovered",
+				zap.String("handler", sub.name),
+				zap.String("event", fmt.Sprintf("%T", e)),
+				zap.Any("error", r),
+			)
+		}
+	}()
+
+	if err := sub.fn(ctx, e); err != nil {
+		b.log.Error("event handler failed",
+			zap.String("handler", sub.name),
+			zap.String("event", fmt.Sprintf("%T", e)),
+			zap.Error(err),
+		)
+	}
+}
+
+// Wait — chờ toàn bộ async handler chạy xong (dùng khi graceful shutdown)
+func (b *Bus) Wait() {
+	b.wg.Wait()
+}
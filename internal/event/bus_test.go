@@ -0,0 +1,150 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type postPublished struct{ ID uint }
+
+type postArchived struct{ ID uint }
+
+func newTestBus() (*Bus, *observer.ObservedLogs) {
+	core, logs := observer.New(zap.ErrorLevel)
+	return NewBus(zap.New(core)), logs
+}
+
+func TestPublishIsolatesFailingHandlers(t *testing.T) {
+	bus, logs := newTestBus()
+	var calls []string
+	Subscribe(bus, "panics", func(ctx context.Context, e postPublished) error {
+		calls = append(calls, "panics")
+		panic("boom")
+	})
+	Subscribe(bus, "fails", func(ctx context.Context, e postPublished) error {
+		calls = append(calls, "fails")
+		return errors.New("cache down")
+	})
+	Subscribe(bus, "ok", func(ctx context.Context, e postPublished) error {
+		calls = append(calls, "ok")
+		return nil
+	})
+
+	// Không panic ra ngoài publisher, handler sau vẫn chạy theo thứ tự đăng ký
+	Publish(context.Background(), bus, postPublished{ID: 1})
+
+	if !slices.Equal(calls, []string{"panics", "fails", "ok"}) {
+		t.Fatalf("expected every handler to run in order, got %v", calls)
+	}
+	if n := logs.FilterMessage("event handler panic recovered").FilterField(zap.String("handler", "panics")).Len(); n != 1 {
+		t.Fatalf("expected panic to be logged once, got %d", n)
+	}
+	if n := logs.FilterMessage("event handler failed").FilterField(zap.String("handler", "fails")).Len(); n != 1 {
+		t.Fatalf("expected error to be logged once, got %d", n)
+	}
+}
+
+func TestPublishDispatchesByType(t *testing.T) {
+	bus, _ := newTestBus()
+	var got []uint
+	Subscribe(bus, "published", func(ctx context.Context, e postPublished) error {
+		got = append(got, e.ID)
+		return nil
+	})
+
+	Publish(context.Background(), bus, postArchived{ID: 1})
+	Publish(context.Background(), bus, postPublished{ID: 2})
+
+	if !slices.Equal(got, []uint{2}) {
+		t.Fatalf("handler must only receive its own event type, got %v", got)
+	}
+}
+
+func TestPublishAsyncAndWait(t *testing.T) {
+	bus, logs := newTestBus()
+	release := make(chan struct{})
+	var done atomic.Int32
+	var gotCtxErr error
+	var mu sync.Mutex
+
+	SubscribeAsync(bus, "slow", func(ctx context.Context, e postPublished) error {
+		<-release
+		mu.Lock()
+		gotCtxErr = ctx.Err()
+		mu.Unlock()
+		done.Add(1)
+		return nil
+	})
+	SubscribeAsync(bus, "panics", func(ctx context.Context, e postPublished) error {
+		defer done.Add(1)
+		panic("boom")
+	})
+
+	// Request kết thúc (ctx cancel) ngay sau Publish → async handler vẫn chạy với ctx còn sống
+	ctx, cancel := context.WithCancel(context.Background())
+	Publish(ctx, bus, postPublished{ID: 1})
+	cancel()
+
+	waited := make(chan struct{})
+	go func() {
+		bus.Wait()
+		close(waited)
+	}()
+	select {
+	case <-waited:
+		t.Fatal("Wait returned before async handlers finished")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case <-waited:
+	case <-time.After(time.Second):
+		t.Fatal("Wait did not return after async handlers finished")
+	}
+	if done.Load() != 2 {
+		t.Fatalf("expected 2 async handlers done, got %d", done.Load())
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if gotCtxErr != nil {
+		t.Fatalf("async handler must not inherit request cancel, got %v", gotCtxErr)
+	}
+	if logs.FilterMessage("event handler panic recovered").Len() != 1 {
+		t.Fatal("expected async panic to be recovered and logged")
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	bus, _ := newTestBus()
+	var got []string
+	unsubA := Subscribe(bus, "a", func(ctx context.Context, e postPublished) error {
+		got = append(got, "a")
+		return nil
+	})
+	Subscribe(bus, "b", func(ctx context.Context, e postPublished) error {
+		got = append(got, "b")
+		return nil
+	})
+
+	unsubA()
+	unsubA() // gọi lại không gỡ nhầm handler khác
+	Publish(context.Background(), bus, postPublished{ID: 1})
+
+	if !slices.Equal(got, []string{"b"}) {
+		t.Fatalf("expected only b after unsubscribe, got %v", got)
+	}
+}
+
+func TestNilBus(t *testing.T) {
+	var bus *Bus
+	Publish(context.Background(), bus, postPublished{ID: 1})
+}
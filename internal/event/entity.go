@@ -0,0 +1,47 @@
+package event
+
+// ============================================================
+// ENTITY EVENTS — event vòng đời do BaseService tự publish
+// T: model, K: kiểu primary key
+// ============================================================
+
+// EntityCreated — sau khi Create / BulkCreate thành công (bulk: publish từng record)
+type EntityCreated[T any, K comparable] struct {
+	ID     K
+	Entity *T
+}
+
+// EntityUpdated — sau khi Update / BulkUpdate thành công
+//
+// Update: ID + Entity (payload đã update)
+// BulkUpdate: không biết ID từng record → Conditions + Fields + Affected, ID = zero value
+type EntityUpdated[T any, K comparable] struct {
+	ID     K
+	Entity *T
+
+	Conditions map[string]any // WHERE của BulkUpdate
+	Fields     map[string]any // SET của BulkUpdate
+	Affected   int64          // số record bị ảnh hưởng (BulkUpdate)
+}
+
+// IsBulk — event sinh ra từ BulkUpdate (không có ID cụ thể)
+func (e EntityUpdated[T, K]) IsBulk() bool {
+	return e.Conditions != nil || e.Fields != nil
+}
+
+// EntityUpserted — sau khi Upsert thành công (không phân biệt insert hay update)
+// ID: record vừa insert, hoặc record đã có bị update (BaseService tra trước theo conflictColumns)
+type EntityUpserted[T any, K comparable] struct {
+	ID     K
+	Entity *T
+}
+
+// EntityDeleted — sau khi Delete / BulkDelete thành công (bulk: publish từng ID thực sự bị xóa)
+type EntityDeleted[T any, K comparable] struct {
+	ID K
+}
+
+// EntityRestored — sau khi BulkRestore thành công (publish từng ID được yêu cầu)
+type EntityRestored[T any, K comparable] struct {
+	ID K
+}
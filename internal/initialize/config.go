@@ -2,6 +2,7 @@ package initialize
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/viper"
 )
@@ -11,13 +12,17 @@ import (
 // ============================================================
 
 type Config struct {
-	App      AppConfig      `mapstructure:"app"`
-	Logger   LoggerConfig   `mapstructure:"logger"`
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Redis    RedisConfig    `mapstructure:"redis"`
-	JWT      JWTConfig      `mapstructure:"jwt"`
-	AWS      AWSConfig      `mapstructure:"aws"`
+	App       AppConfig       `mapstructure:"app"`
+	Logger    LoggerConfig    `mapstructure:"logger"`
+	Server    ServerConfig    `mapstructure:"server"`
+	Database  DatabaseConfig  `mapstructure:"database"`
+	Redis     RedisConfig     `mapstructure:"redis"`
+	JWT       JWTConfig       `mapstructure:"jwt"`
+	TwoFactor TwoFactorConfig `mapstructure:"two_factor"`
+	AWS       AWSConfig       `mapstructure:"aws"`
+	Outbox    OutboxConfig    `mapstructure:"outbox"`
+
+	LoginThrottle LoginThrottleConfig `mapstructure:"login_throttle"`
 }
 
 type AppConfig struct {
@@ -61,7 +66,33 @@ type RedisConfig struct {
 }
 
 type JWTConfig struct {
-	Secret string `mapstructure:"secret"`
+	Algorithm         string   `mapstructure:"algorithm"` // "HS256" | "RS256" | "EdDSA"
+	Secret            string   `mapstructure:"secret"`    // HS256, tối thiểu 32 byte
+	PrivateKeyFile    string   `mapstructure:"private_key_file"`
+	PublicKeyFile     string   `mapstructure:"public_key_file"`
+	KeyID             string   `mapstructure:"key_id"`
+	Issuer            string   `mapstructure:"issuer"`
+	Audience          []string `mapstructure:"audience"`
+	AccessTTLSeconds  int      `mapstructure:"access_ttl_seconds"`
+	RefreshTTLSeconds int      `mapstructure:"refresh_ttl_seconds"`
+	LeewaySeconds     int      `mapstructure:"leeway_seconds"`
+}
+
+type TwoFactorConfig struct {
+	Issuer        string `mapstructure:"issuer"`         // tên hiện trong app Authenticator
+	EncryptionKey string `mapstructure:"encryption_key"` // mã hóa TOTP secret trong DB, tối thiểu 32 byte
+}
+
+// LoginThrottleConfig — chống dò mật khẩu trên /auth/*, để 0 → giá trị mặc định của throttle
+type LoginThrottleConfig struct {
+	Store           string `mapstructure:"store"` // "memory" | "redis"
+	FreeAttempts    int    `mapstructure:"free_attempts"`
+	MaxAttempts     int    `mapstructure:"max_attempts"`
+	IPMaxAttempts   int    `mapstructure:"ip_max_attempts"`
+	WindowSeconds   int    `mapstructure:"window_seconds"`
+	LockoutSeconds  int    `mapstructure:"lockout_seconds"`
+	BaseDelayMs     int    `mapstructure:"base_delay_ms"`
+	MaxDelaySeconds int    `mapstructure:"max_delay_seconds"`
 }
 
 type AWSConfig struct {
@@ -70,6 +101,16 @@ type AWSConfig struct {
 	Region          string `mapstructure:"region"`
 }
 
+type OutboxConfig struct {
+	Enabled        bool   `mapstructure:"enabled"`
+	Sink           string `mapstructure:"sink"` // "local" | "redis"
+	StreamPrefix   string `mapstructure:"stream_prefix"`
+	StreamMaxLen   int64  `mapstructure:"stream_max_len"`
+	PollIntervalMs int    `mapstructure:"poll_interval_ms"`
+	BatchSize      int    `mapstructure:"batch_size"`
+	MaxAttempts    int    `mapstructure:"max_attempts"`
+}
+
 // LoadConfig — load YAML config + .env override
 // env: "development" | "staging" | "production"
 func LoadConfig(env string) (*Config, error) {
@@ -91,8 +132,10 @@ func LoadConfig(env string) (*Config, error) {
 		return nil, fmt.Errorf("load config %s.yaml failed: %w", env, err)
 	}
 
-	// 3. Cho phép ENV vars override YAML
+	// 3. Cho phép ENV vars override YAML — key "." → "_", viper tự upper-case
 	// VD: DATABASE_PASSWORD=xxx sẽ override database.password
+	//     TWO_FACTOR_ENCRYPTION_KEY=xxx sẽ override two_factor.encryption_key
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	v.AutomaticEnv()
 
 	var cfg Config
@@ -100,5 +143,30 @@ func LoadConfig(env string) (*Config, error) {
 		return nil, fmt.Errorf("unmarshal config failed: %w", err)
 	}
 
+	if err := cfg.checkSecrets(env); err != nil {
+		return nil, err
+	}
+
 	return &cfg, nil
 }
+
+// devSecretPrefix — tiền tố của secret mẫu trong development.yaml
+const devSecretPrefix = "dev-only-"
+
+// checkSecrets — ngoài development không được chạy với secret mẫu
+// (copy development.yaml sang staging / production mà quên set ENV → ai đọc repo cũng ký được JWT)
+func (cfg *Config) checkSecrets(env string) error {
+	if env == "development" {
+		return nil
+	}
+	secrets := []struct{ env, value string }{
+		{"JWT_SECRET", cfg.JWT.Secret},
+		{"TWO_FACTOR_ENCRYPTION_KEY", cfg.TwoFactor.EncryptionKey},
+	}
+	for _, secret := range secrets {
+		if strings.HasPrefix(secret.value, devSecretPrefix) {
+			return fmt.Errorf("config %s: %s is a development-only secret, set it via ENV", env, secret.env)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package initialize
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckSecrets(t *testing.T) {
+	dev := Config{JWT: JWTConfig{Secret: devSecretPrefix + "jwt"}, TwoFactor: TwoFactorConfig{EncryptionKey: devSecretPrefix + "2fa"}}
+	if err := dev.checkSecrets("development"); err != nil {
+		t.Fatalf("development must accept sample secrets: %v", err)
+	}
+	if err := dev.checkSecrets("production"); err == nil || !strings.Contains(err.Error(), "JWT_SECRET") {
+		t.Fatalf("production must refuse sample jwt secret, got %v", err)
+	}
+
+	dev.JWT.Secret = "real-secret-from-env-0123456789abcdef"
+	if err := dev.checkSecrets("staging"); err == nil || !strings.Contains(err.Error(), "TWO_FACTOR_ENCRYPTION_KEY") {
+		t.Fatalf("staging must refuse sample 2fa key, got %v", err)
+	}
+
+	dev.TwoFactor.EncryptionKey = "real-2fa-key-from-env-0123456789abcdef"
+	if err := dev.checkSecrets("production"); err != nil {
+		t.Fatalf("real secrets must be accepted: %v", err)
+	}
+}
+
+func TestLoadConfigEnvOverride(t *testing.T) {
+	t.Setenv("JWT_SECRET", "jwt-secret-from-env-0123456789abcdef")
+	t.Setenv("TWO_FACTOR_ENCRYPTION_KEY", "2fa-key-from-env-0123456789abcdef")
+
+	cfg, err := LoadConfig("development")
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.JWT.Secret != "jwt-secret-from-env-0123456789abcdef" {
+		t.Fatalf("JWT_SECRET not applied, got %q", cfg.JWT.Secret)
+	}
+	if cfg.TwoFactor.EncryptionKey != "2fa-key-from-env-0123456789abcdef" {
+		t.Fatalf("TWO_FACTOR_ENCRYPTION_KEY not applied, got %q", cfg.TwoFactor.EncryptionKey)
+	}
+}
@@ -27,7 +27,8 @@ func InitDatabase(cfg DatabaseConfig, loggerCfg LoggerConfig, log *zap.Logger) (
 	}
 
 	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(gormLogLevel),
+		Logger:         logger.Default.LogMode(gormLogLevel),
+		TranslateError: true, // 1062 → gorm.ErrDuplicatedKey (repository.ErrDuplicateKey → 409)
 	})
 	if err != nil {
 		return nil, fmt.Errorf("connect database failed: %w", err)
@@ -0,0 +1,32 @@
+package initialize
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"golang-base/internal/auth/token"
+)
+
+// InitJWT — tạo token.Manager theo config, sai key / secret quá ngắn → lỗi khởi động
+func InitJWT(cfg JWTConfig, log *zap.Logger) (*token.Manager, error) {
+	manager, err := token.NewManager(token.Config{
+		Algorithm:      cfg.Algorithm,
+		Secret:         cfg.Secret,
+		PrivateKeyFile: cfg.PrivateKeyFile,
+		PublicKeyFile:  cfg.PublicKeyFile,
+		KeyID:          cfg.KeyID,
+		Issuer:         cfg.Issuer,
+		Audience:       cfg.Audience,
+		AccessTTL:      time.Duration(cfg.AccessTTLSeconds) * time.Second,
+		RefreshTTL:     time.Duration(cfg.RefreshTTLSeconds) * time.Second,
+		Leeway:         time.Duration(cfg.LeewaySeconds) * time.Second,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("init jwt failed: %w", err)
+	}
+
+	log.Info("jwt initialized", zap.String("algorithm", cfg.Algorithm), zap.String("issuer", cfg.Issuer))
+	return manager, nil
+}
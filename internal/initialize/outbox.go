@@ -0,0 +1,43 @@
+package initialize
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"golang-base/internal/outbox"
+)
+
+// InitOutbox — tạo dispatcher relay event từ bảng outbox sang sink theo config
+// sink "redis" cần Redis đã kết nối, "local" trả kèm LocalSink để module đăng ký handler
+func InitOutbox(cfg OutboxConfig, db *gorm.DB, rdb *redis.Client, log *zap.Logger) (*outbox.Dispatcher, *outbox.LocalSink, error) {
+	var (
+		sink  outbox.Sink
+		local *outbox.LocalSink
+	)
+
+	switch cfg.Sink {
+	case "redis":
+		if rdb == nil {
+			return nil, nil, fmt.Errorf("outbox sink redis requires a redis connection")
+		}
+		sink = outbox.NewRedisStreamSink(rdb, cfg.StreamPrefix, cfg.StreamMaxLen)
+	case "", "local":
+		local = outbox.NewLocalSink()
+		sink = local
+	default:
+		return nil, nil, fmt.Errorf("unknown outbox sink: %s", cfg.Sink)
+	}
+
+	dispatcher := outbox.NewDispatcher(db, sink, log, outbox.Options{
+		PollInterval: time.Duration(cfg.PollIntervalMs) * time.Millisecond,
+		BatchSize:    cfg.BatchSize,
+		MaxAttempts:  cfg.MaxAttempts,
+	})
+
+	log.Info("outbox initialized", zap.String("sink", cfg.Sink))
+	return dispatcher, local, nil
+}
@@ -9,22 +9,57 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 
+	"golang-base/internal/auth/apikey"
+	"golang-base/internal/auth/rbac"
+	"golang-base/internal/auth/session"
+	"golang-base/internal/auth/throttle"
+	"golang-base/internal/auth/token"
+	"golang-base/internal/auth/totp"
+	"golang-base/internal/event"
 	"golang-base/internal/middlewares"
+	"golang-base/internal/outbox"
 	"golang-base/internal/routers"
+	us "golang-base/internal/service/user"
 )
 
 // Global references — accessible từ bất kỳ đâu trong app
 var (
 	Logger *zap.Logger
 	DB     *gorm.DB
+	Redis  *redis.Client // nil nếu không kết nối được Redis
 	Cfg    *Config
+
+	// OutboxSink — nơi đăng ký handler khi outbox dùng sink "local"
+	OutboxSink *outbox.LocalSink
+
+	// EventBus — domain event bus in-process, service publish / module subscribe
+	EventBus *event.Bus
+
+	// Tokens — ký / verify access token JWT
+	Tokens *token.Manager
+
+	// TOTP — sinh / kiểm tra mã 2FA
+	TOTP *totp.Manager
+
+	// RBAC — tra role + permission của user (cache, tự invalidate qua EventBus)
+	RBAC *rbac.Resolver
+
+	// Revocations — session bị thu hồi, AuthMiddleware chặn access token của session đó
+	Revocations *session.RevocationList
+
+	// APIKeys — xác thực header X-API-Key cho service gọi service
+	APIKeys *apikey.Authenticator
+
+	// Throttle — đếm đăng nhập sai theo tài khoản + IP, khóa tạm thời
+	Throttle *throttle.Throttler
 )
 
 // Run — bootstrap toàn bộ ứng dụng
-// Flow: LoadConfig → InitLogger → InitDB → InitRouter → GracefulShutdown
+// Flow: LoadConfig → InitLogger → InitDB → InitRedis → InitOutbox → InitEventBus → InitJWT → InitTwoFactor → InitThrottle → InitRouter → GracefulShutdown
 func Run() {
 	// 1. Xác định environment
 	env := os.Getenv("APP_ENV")
@@ -58,7 +93,64 @@ func Run() {
 	DB = db
 	log.Info("database initialized")
 
-	// 5. Init router
+	// 5. Init redis — không bắt buộc, module cần Redis tự kiểm tra nil
+	rdb, err := InitRedis(cfg.Redis, log)
+	if err != nil {
+		log.Warn("redis unavailable", zap.Error(err))
+	}
+	Redis = rdb
+
+	// 6. Outbox dispatcher — relay event chạy nền
+	outboxCtx, stopOutbox := context.WithCancel(context.Background())
+	defer stopOutbox()
+
+	var dispatcher *outbox.Dispatcher
+	if cfg.Outbox.Enabled {
+		dispatcher, OutboxSink, err = InitOutbox(cfg.Outbox, db, rdb, log)
+		if err != nil {
+			log.Fatal("outbox init failed", zap.Error(err))
+		}
+		go dispatcher.Run(outboxCtx)
+	}
+
+	// 7. Domain event bus
+	bus := event.NewBus(log)
+	EventBus = bus
+
+	// 8. JWT + 2FA — sai key / secret → không khởi động (tránh chạy với auth hỏng)
+	tokens, err := InitJWT(cfg.JWT, log)
+	if err != nil {
+		log.Fatal("jwt init failed", zap.Error(err))
+	}
+	Tokens = tokens
+
+	twoFactor, err := InitTwoFactor(cfg.TwoFactor, log)
+	if err != nil {
+		log.Fatal("two-factor init failed", zap.Error(err))
+	}
+	TOTP = twoFactor
+
+	resolver := rbac.NewResolver(db, rbac.DefaultTTL)
+	resolver.Subscribe(bus)
+	RBAC = resolver
+
+	revocations := session.NewRevocationList(db, session.DefaultAliveTTL, tokens.AccessTTL()+time.Duration(cfg.JWT.LeewaySeconds)*time.Second)
+	revocations.Subscribe(bus)
+	Revocations = revocations
+
+	keys := apikey.NewAuthenticator(db, apikey.DefaultCacheTTL)
+	keys.Subscribe(bus)
+	APIKeys = keys
+
+	throttler, err := InitThrottle(cfg.LoginThrottle, rdb, bus, log)
+	if err != nil {
+		log.Fatal("login throttle init failed", zap.Error(err))
+	}
+	Throttle = throttler
+	throttle.AuditTrail(bus, db, log)
+	us.NewLockoutService(db, throttler, bus).Subscribe(bus)
+
+	// 9. Init router
 	r := routers.NewRouter(log)
 
 	// Global middleware chain
@@ -67,13 +159,25 @@ func Run() {
 		middlewares.CORSMiddleware(),     // CORS headers
 		middlewares.RequestTimer(),       // Track execution time
 		middlewares.RequestLogger(log),   // Structured request logging
+		middlewares.ErrorHandler(),       // c.Error(err) → envelope + error_code
 		middlewares.RateLimiter(100, 10), // 100 req/s burst, 10 req/s sustained per IP
 	)
 
 	// Register routes
-	routers.RegisterRoutes(r, log, db)
-
-	// 6. Start server with graceful shutdown
+	routers.RegisterRoutes(r, routers.Deps{
+		Log:         log,
+		DB:          db,
+		Redis:       rdb,
+		Bus:         bus,
+		Tokens:      tokens,
+		Revocations: revocations,
+		RBAC:        resolver,
+		APIKeys:     keys,
+		TOTP:        twoFactor,
+		Throttle:    throttler,
+	})
+
+	// 10. Start server with graceful shutdown
 	addr := fmt.Sprintf(":%d", cfg.Server.Port)
 	srv := &http.Server{
 		Addr:         addr,
@@ -106,6 +210,19 @@ func Run() {
 		log.Error("server forced to shutdown", zap.Error(err))
 	}
 
+	// Chờ async event handler chạy nốt
+	bus.Wait()
+
+	// Dừng outbox dispatcher, chờ batch đang chạy kết thúc
+	stopOutbox()
+	if dispatcher != nil {
+		dispatcher.Wait()
+	}
+
+	if rdb != nil {
+		rdb.Close()
+	}
+
 	// Close DB connection
 	sqlDB, _ := db.DB()
 	if sqlDB != nil {
@@ -0,0 +1,43 @@
+package initialize
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"golang-base/internal/auth/throttle"
+	"golang-base/internal/event"
+)
+
+// InitThrottle — tạo throttle.Throttler cho /auth/*
+// store "redis" cần Redis đã kết nối (nhiều instance phải thấy chung bộ đếm)
+func InitThrottle(cfg LoginThrottleConfig, rdb *redis.Client, bus *event.Bus, log *zap.Logger) (*throttle.Throttler, error) {
+	var store throttle.Store
+
+	switch cfg.Store {
+	case "redis":
+		if rdb == nil {
+			return nil, fmt.Errorf("login throttle store redis requires a redis connection")
+		}
+		store = throttle.NewRedisStore(rdb, "")
+	case "", "memory":
+		store = throttle.NewMemoryStore()
+	default:
+		return nil, fmt.Errorf("unknown login throttle store: %s", cfg.Store)
+	}
+
+	throttler := throttle.New(store, throttle.Config{
+		FreeAttempts:    cfg.FreeAttempts,
+		MaxAttempts:     cfg.MaxAttempts,
+		IPMaxAttempts:   cfg.IPMaxAttempts,
+		Window:          time.Duration(cfg.WindowSeconds) * time.Second,
+		LockoutDuration: time.Duration(cfg.LockoutSeconds) * time.Second,
+		BaseDelay:       time.Duration(cfg.BaseDelayMs) * time.Millisecond,
+		MaxDelay:        time.Duration(cfg.MaxDelaySeconds) * time.Second,
+	}, bus)
+
+	log.Info("login throttle initialized", zap.String("store", cfg.Store))
+	return throttler, nil
+}
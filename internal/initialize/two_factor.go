@@ -0,0 +1,23 @@
+package initialize
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"golang-base/internal/auth/totp"
+)
+
+// InitTwoFactor — tạo totp.Manager, thiếu issuer / key mã hóa quá ngắn → lỗi khởi động
+func InitTwoFactor(cfg TwoFactorConfig, log *zap.Logger) (*totp.Manager, error) {
+	manager, err := totp.NewManager(totp.Config{
+		Issuer:        cfg.Issuer,
+		EncryptionKey: cfg.EncryptionKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("init two-factor failed: %w", err)
+	}
+
+	log.Info("two-factor initialized", zap.String("issuer", cfg.Issuer))
+	return manager, nil
+}
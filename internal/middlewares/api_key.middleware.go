@@ -0,0 +1,38 @@
+package middlewares
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"golang-base/internal/auth"
+	"golang-base/internal/auth/apikey"
+	"golang-base/pkg/apperror"
+	response "golang-base/pkg/response"
+)
+
+// APIKeyMiddleware — xác thực header "X-API-Key", thay thế cho bearer token
+// Đặt TRƯỚC AuthMiddleware:
+//
+//	group.Use(middlewares.APIKeyMiddleware(keys), middlewares.AuthMiddleware(tokens, revocations))
+//
+// Không có header (hoặc keys nil) → để AuthMiddleware xử lý bearer token
+// Key sai / hết hạn / bị thu hồi → 401
+func APIKeyMiddleware(keys *apikey.Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw := c.GetHeader(apikey.Header)
+		if raw == "" || keys == nil {
+			c.Next()
+			return
+		}
+
+		p, err := keys.Authenticate(c.Request.Context(), raw)
+		if err != nil {
+			_ = c.Error(err)
+			response.AppError(c, apperror.From(err)) // API_KEY_INVALID, lỗi DB → INTERNAL_ERROR
+			c.Abort()
+			return
+		}
+
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), p))
+		c.Next()
+	}
+}
@@ -1,20 +1,74 @@
 package middlewares
 
 import (
-	"net/http"
-
 	"github.com/gin-gonic/gin"
+
+	"golang-base/internal/auth"
+	"golang-base/internal/auth/token"
+	"golang-base/pkg/apperror"
+	response "golang-base/pkg/response"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+// ClaimsKey — key lưu *token.Claims trong gin context
+const ClaimsKey = "auth.claims"
+
+// AuthMiddleware — xác thực "Authorization: Bearer <access token>"
+//
+// Token hợp lệ → claims vào gin context (ClaimsKey) + principal vào request context
+// → service / policy đọc qua auth.PrincipalFrom(ctx)
+// Thiếu / sai / hết hạn / session đã thu hồi → 401 (TOKEN_MISSING, TOKEN_INVALID, TOKEN_EXPIRED, SESSION_REVOKED)
+// revocations nil → không kiểm tra thu hồi
+// Request đã được APIKeyMiddleware xác thực → bỏ qua
+func AuthMiddleware(tokens *token.Manager, revocations token.RevocationChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		token := c.GetHeader("Authorization")
-		if token == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		if _, ok := auth.PrincipalFrom(c.Request.Context()); ok {
+			c.Next()
+			return
+		}
+
+		raw := token.BearerToken(c.GetHeader("Authorization"))
+		if raw == "" {
+			response.AppError(c, ErrTokenMissing)
 			c.Abort() // dừng request không được gọi nữa trong request đó
 			return
 		}
-		// nếu có token thì gọi đến handler tiếp theo
+
+		claims, err := tokens.Parse(raw)
+		if err != nil {
+			_ = c.Error(err)
+			response.AppError(c, apperror.From(err)) // TOKEN_EXPIRED | TOKEN_INVALID
+			c.Abort()
+			return
+		}
+
+		if revocations != nil && claims.SessionID != "" {
+			revoked, err := revocations.IsRevoked(c.Request.Context(), claims.SessionID)
+			if err != nil {
+				_ = c.Error(err)
+				response.AppError(c, apperror.ErrInternal)
+				c.Abort()
+				return
+			}
+			if revoked {
+				response.AppError(c, ErrSessionRevoked)
+				c.Abort()
+				return
+			}
+		}
+
+		c.Set(ClaimsKey, claims)
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), claims.Principal()))
+		// nếu token hợp lệ thì gọi đến handler tiếp theo
 		c.Next()
 	}
 }
+
+// ClaimsFrom — claims của access token đã xác thực (false nếu route không qua AuthMiddleware)
+func ClaimsFrom(c *gin.Context) (*token.Claims, bool) {
+	v, ok := c.Get(ClaimsKey)
+	if !ok {
+		return nil, false
+	}
+	claims, ok := v.(*token.Claims)
+	return claims, ok
+}
@@ -0,0 +1,60 @@
+package middlewares
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"golang-base/internal/auth/token"
+)
+
+// revokedSet — token.RevocationChecker trong bộ nhớ
+type revokedSet map[string]bool
+
+func (r revokedSet) IsRevoked(_ context.Context, sessionID string) (bool, error) {
+	return r[sessionID], nil
+}
+
+func TestAuthMiddlewareRejectsRevokedSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tokens, err := token.NewManager(token.Config{Secret: "unit-test-secret-0123456789abcdef"})
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	revoked := revokedSet{}
+
+	router := gin.New()
+	router.GET("/me", AuthMiddleware(tokens, revoked), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	call := func(raw string) (int, string) {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authorization", "Bearer "+raw)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var body struct {
+			ErrorCode string `json:"error_code"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &body)
+		return w.Code, body.ErrorCode
+	}
+
+	raw, _, err := tokens.Sign(token.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "15"}, SessionID: "sid-1"})
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if code, _ := call(raw); code != http.StatusOK {
+		t.Fatalf("active session: expected 200, got %d", code)
+	}
+
+	// Token vẫn còn hạn (15 phút) nhưng session đã thu hồi → 401 ngay
+	revoked["sid-1"] = true
+	if code, errorCode := call(raw); code != http.StatusUnauthorized || errorCode != "SESSION_REVOKED" {
+		t.Fatalf("revoked session: expected 401 SESSION_REVOKED, got %d %q", code, errorCode)
+	}
+}
@@ -1,10 +1,12 @@
 package middlewares
 
 import (
-	"net/http"
-
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
+
+	"golang-base/pkg/apperror"
+	response "golang-base/pkg/response"
+	"golang-base/pkg/validation"
 )
 
 // Recovery — thay thế gin.Recovery() mặc định
@@ -22,13 +24,32 @@ func Recovery(log *zap.Logger) gin.HandlerFunc {
 					zap.String("ip", c.ClientIP()),
 				)
 
-				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-					"status":  false,
-					"code":    http.StatusInternalServerError,
-					"message": "internal server error",
-				})
+				response.AppError(c, apperror.ErrInternal)
+				c.Abort()
 			}
 		}()
 		c.Next()
 	}
 }
+
+// ErrorHandler — terminal middleware: handler chỉ cần c.Error(err) rồi return
+//
+//	if err := h.service.Publish(ctx, in); err != nil {
+//		_ = c.Error(err)
+//		return
+//	}
+//
+// Sau c.Next(): chưa có response mà c.Errors có lỗi → lỗi cuối qua apperror.From → envelope
+// Handler đã tự trả response (VD: base.RespondError) → giữ nguyên
+// Đặt SAU RequestLogger để log thấy status cuối cùng
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if c.Writer.Written() || len(c.Errors) == 0 {
+			return
+		}
+		e := apperror.From(c.Errors.Last().Err)
+		response.AppError(c, e.Localize(validation.Language(c.GetHeader("Accept-Language"))))
+	}
+}
@@ -0,0 +1,20 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"golang-base/internal/auth/apikey"
+	"golang-base/internal/auth/token"
+	"golang-base/pkg/apperror"
+)
+
+// Mã lỗi của tầng xác thực / phân quyền (xem apperror.Catalog)
+var (
+	ErrTokenMissing      = apperror.Define(http.StatusUnauthorized, "TOKEN_MISSING", "auth.token_missing", "missing bearer token")
+	ErrTokenInvalid      = apperror.Define(http.StatusUnauthorized, "TOKEN_INVALID", "auth.token_invalid", "invalid token").Match(token.ErrInvalidToken)
+	ErrTokenExpired      = apperror.Define(http.StatusUnauthorized, "TOKEN_EXPIRED", "auth.token_expired", "token expired").Match(token.ErrExpiredToken)
+	ErrSessionRevoked    = apperror.Define(http.StatusUnauthorized, "SESSION_REVOKED", "auth.session_revoked", "session revoked")
+	ErrAPIKeyInvalid     = apperror.Define(http.StatusUnauthorized, "API_KEY_INVALID", "auth.api_key_invalid", "invalid api key").Match(apikey.ErrInvalidKey)
+	ErrPermissionDenied  = apperror.Define(http.StatusForbidden, "PERMISSION_DENIED", "auth.permission_denied", "missing permission")
+	ErrTwoFactorRequired = apperror.Define(http.StatusForbidden, "TWO_FACTOR_REQUIRED", "auth.two_factor_required", "two-factor authentication required")
+)
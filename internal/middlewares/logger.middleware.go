@@ -33,7 +33,7 @@ func RequestLogger(log *zap.Logger) gin.HandlerFunc {
 			logFn = log.Warn
 		}
 
-		logFn("request",
+		fields := []zap.Field{
 			zap.String("id", requestID),
 			zap.String("method", c.Request.Method),
 			zap.String("path", c.Request.URL.Path),
@@ -43,6 +43,11 @@ func RequestLogger(log *zap.Logger) gin.HandlerFunc {
 			zap.Duration("latency", time.Since(start)),
 			zap.String("ip", c.ClientIP()),
 			zap.String("user_agent", c.Request.UserAgent()),
-		)
+		}
+		// Lỗi gốc (c.Error) — response chỉ có error_code, chi tiết nằm ở log
+		if len(c.Errors) > 0 {
+			fields = append(fields, zap.Strings("errors", c.Errors.Errors()))
+		}
+		logFn("request", fields...)
 	}
 }
@@ -0,0 +1,79 @@
+package middlewares
+
+import (
+	"maps"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"golang-base/internal/auth"
+	"golang-base/internal/auth/rbac"
+	"golang-base/pkg/apperror"
+	response "golang-base/pkg/response"
+)
+
+// LoadPermissions — gắn role + permission của user vào principal (đặt SAU AuthMiddleware)
+// Principal không phải user (API key, service) → giữ nguyên quyền sẵn có
+func LoadPermissions(resolver *rbac.Resolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p, ok := auth.PrincipalFrom(c.Request.Context())
+		if !ok || p.Kind != auth.KindUser {
+			c.Next()
+			return
+		}
+
+		userID, err := strconv.ParseUint(p.ID, 10, strconv.IntSize)
+		if err != nil {
+			response.AppError(c, ErrTokenInvalid)
+			c.Abort()
+			return
+		}
+		grants, err := resolver.Resolve(c.Request.Context(), uint(userID))
+		if err != nil {
+			_ = c.Error(err)
+			response.AppError(c, apperror.ErrInternal)
+			c.Abort()
+			return
+		}
+
+		// Copy principal → không sửa object dùng chung
+		enriched := *p
+		enriched.Permissions = grants.Permissions
+		enriched.Roles = []string{}
+		if grants.Role != "" {
+			enriched.Roles = []string{grants.Role}
+		}
+		enriched.Attributes = maps.Clone(p.Attributes)
+		if enriched.Attributes == nil {
+			enriched.Attributes = make(map[string]any)
+		}
+		enriched.Attributes["user_catalogue_id"] = grants.CatalogueID
+		enriched.Attributes["require_two_factor"] = grants.RequireTwoFactor
+
+		c.Request = c.Request.WithContext(auth.WithPrincipal(c.Request.Context(), &enriched))
+		c.Next()
+	}
+}
+
+// RequirePermission — chặn request nếu principal thiếu 1 trong các permission
+// Chưa xác thực → 401, thiếu quyền → 403 PERMISSION_DENIED (errors.permission = quyền còn thiếu)
+//
+//	catalogues.PUT("/:id", middlewares.RequirePermission("catalogue.update"), h.Update)
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p, ok := auth.PrincipalFrom(c.Request.Context())
+		if !ok {
+			response.AppError(c, apperror.ErrUnauthenticated)
+			c.Abort()
+			return
+		}
+		for _, permission := range permissions {
+			if !p.Can(permission) {
+				response.AppError(c, ErrPermissionDenied.WithDetails(map[string]string{"permission": permission}))
+				c.Abort()
+				return
+			}
+		}
+		c.Next()
+	}
+}
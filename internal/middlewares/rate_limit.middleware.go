@@ -1,11 +1,13 @@
 package middlewares
 
 import (
-	"net/http"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"golang-base/pkg/apperror"
+	response "golang-base/pkg/response"
 )
 
 // ============================================================
@@ -103,11 +105,8 @@ func RateLimiter(burst, rate float64) gin.HandlerFunc {
 		ip := c.ClientIP()
 
 		if !store.allow(ip) {
-			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
-				"status":  false,
-				"code":    http.StatusTooManyRequests,
-				"message": "too many requests, please try again later",
-			})
+			response.AppError(c, apperror.ErrTooManyRequests)
+			c.Abort()
 			return
 		}
 
@@ -0,0 +1,34 @@
+package middlewares
+
+import (
+	"slices"
+
+	"github.com/gin-gonic/gin"
+
+	"golang-base/internal/auth"
+	"golang-base/internal/auth/token"
+	response "golang-base/pkg/response"
+)
+
+// RequireTwoFactor — nhóm của user bắt buộc 2FA mà access token chưa qua bước 2FA → 403
+// Đặt SAU LoadPermissions (cần Attributes["require_two_factor"])
+// Route bật 2FA / đăng xuất KHÔNG được gắn middleware này (user chưa bật thì không còn đường bật)
+// Principal không phải user (API key, service) → bỏ qua
+func RequireTwoFactor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p, ok := auth.PrincipalFrom(c.Request.Context())
+		if !ok || p.Kind != auth.KindUser {
+			c.Next()
+			return
+		}
+
+		required, _ := p.Attributes["require_two_factor"].(bool)
+		amr, _ := p.Attributes["amr"].([]string)
+		if required && !slices.Contains(amr, token.AMROTP) {
+			response.AppError(c, ErrTwoFactorRequired)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// APIKey — khóa cho worker nội bộ / đối tác gọi API, không cần đăng nhập user
+//
+// Key gốc: gbk_<prefix>_<secret>, chỉ trả về 1 lần lúc tạo / rotate
+// DB lưu prefix (tra cứu) + SHA-256 của cả key, Scopes = permission được phép
+type APIKey struct {
+	ID         uint64     `json:"id"            gorm:"primaryKey;autoIncrement"`
+	Name       string     `json:"name"          gorm:"type:varchar(255);not null"`
+	Prefix     string     `json:"prefix"        gorm:"type:varchar(16);not null;uniqueIndex"`
+	KeyHash    string     `json:"-"             gorm:"type:char(64);not null"` // SHA-256 hex
+	Scopes     []string   `json:"scopes"        gorm:"type:json;serializer:json"`
+	ExpiresAt  *time.Time `json:"expires_at"` // nil = không hết hạn
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedBy  *uint      `json:"created_by"` // user tạo key
+	CreatedAt  time.Time  `json:"created_at"    gorm:"autoCreateTime"`
+	UpdatedAt  time.Time  `json:"updated_at"    gorm:"autoUpdateTime"`
+}
+
+// khai báo tên bảng trong DB
+func (A *APIKey) TableName() string {
+	return "api_keys"
+}
+
+// Active — chưa thu hồi, chưa hết hạn
+func (A *APIKey) Active(now time.Time) bool {
+	return A.RevokedAt == nil && (A.ExpiresAt == nil || A.ExpiresAt.After(now))
+}
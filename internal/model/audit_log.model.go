@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// action ghi trong audit_logs
+const (
+	AuditActionCreated      = "created"
+	AuditActionUpdated      = "updated"
+	AuditActionDeleted      = "deleted"
+	AuditActionBulkUpdated  = "bulk_updated"
+	AuditActionBulkDeleted  = "bulk_deleted"
+	AuditActionBulkRestored = "bulk_restored"
+
+	// sự kiện bảo mật đăng nhập (auditable_type "login", auditable_id = email / IP)
+	AuditActionLoginFailed = "login_failed"
+	AuditActionLocked      = "locked"
+	AuditActionBlocked     = "blocked"
+	AuditActionUnlocked    = "unlocked"
+)
+
+// AuditLog — lịch sử thay đổi data, ghi bởi hooks.AuditHook (+ throttle.AuditTrail cho sự kiện đăng nhập)
+type AuditLog struct {
+	ID            uint64    `json:"id"               gorm:"primaryKey;autoIncrement"`
+	AuditableType string    `json:"auditable_type"   gorm:"not null"` // VD: "user_catalogue"
+	AuditableID   string    `json:"auditable_id"`                     // rỗng với bulk action
+	Action        string    `json:"action"           gorm:"not null"`
+	Changes       string    `json:"changes"          gorm:"type:json"` // payload / điều kiện bulk (JSON)
+	CreatedAt     time.Time `json:"created_at"       gorm:"autoCreateTime"`
+}
+
+// khai báo tên bảng trong DB
+func (A *AuditLog) TableName() string {
+	return "audit_logs"
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// trạng thái của 1 event trong outbox
+const (
+	OutboxStatusPending = "pending" // chờ gửi (hoặc chờ retry)
+	OutboxStatusSent    = "sent"    // đã gửi thành công
+	OutboxStatusDead    = "dead"    // vượt quá số lần retry → dead-letter, cần xử lý tay
+)
+
+// OutboxEvent — event chờ gửi, được insert CÙNG transaction với thao tác ghi data
+type OutboxEvent struct {
+	ID            uint64     `json:"id"               gorm:"primaryKey;autoIncrement"`
+	AggregateType string     `json:"aggregate_type"   gorm:"not null"`           // VD: "user_catalogue"
+	AggregateID   string     `json:"aggregate_id"     gorm:"not null"`           // ID của record gốc
+	EventType     string     `json:"event_type"       gorm:"not null"`           // VD: "user_catalogue.created"
+	Payload       string     `json:"payload"          gorm:"type:json;not null"` // JSON
+	Status        string     `json:"status"           gorm:"not null;default:pending"`
+	Attempts      int        `json:"attempts"         gorm:"not null;default:0"`
+	LastError     string     `json:"last_error"       gorm:"type:text"`
+	AvailableAt   time.Time  `json:"available_at"     gorm:"not null"` // thời điểm sớm nhất được gửi (backoff)
+	ProcessedAt   *time.Time `json:"processed_at"`
+	CreatedAt     time.Time  `json:"created_at"       gorm:"autoCreateTime"`
+	UpdatedAt     time.Time  `json:"updated_at"       gorm:"autoUpdateTime"`
+}
+
+// khai báo tên bảng trong DB
+func (O *OutboxEvent) TableName() string {
+	return "outbox"
+}
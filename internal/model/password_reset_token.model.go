@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// PasswordResetToken — token đặt lại mật khẩu
+// Chỉ lưu hash, token gốc chỉ xuất hiện 1 lần trong mail gửi user
+type PasswordResetToken struct {
+	ID        uint64     `json:"id"           gorm:"primaryKey;autoIncrement"`
+	UserID    uint       `json:"user_id"      gorm:"not null;index"`
+	TokenHash string     `json:"-"            gorm:"type:char(64);not null;uniqueIndex"` // SHA-256 hex
+	ExpiresAt time.Time  `json:"expires_at"   gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"` // khác nil = đã dùng
+	CreatedAt time.Time  `json:"created_at"   gorm:"autoCreateTime"`
+}
+
+// khai báo tên bảng trong DB
+func (P *PasswordResetToken) TableName() string {
+	return "password_reset_tokens"
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// Permission — quyền thao tác, gán cho UserCatalogue qua bảng user_catalogue_permissions
+// Name dạng "<resource>.<action>": user.create, catalogue.update, catalogue.delete...
+type Permission struct {
+	ID          uint      `json:"id"           gorm:"primaryKey;autoIncrement"`
+	Name        string    `json:"name"         gorm:"type:varchar(100);not null;uniqueIndex" validate:"required,max=100,unique"`
+	Description string    `json:"description"  gorm:"null"`
+	CreatedAt   time.Time `json:"created_at"   gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `json:"updated_at"   gorm:"autoUpdateTime"`
+}
+
+// khai báo tên bảng trong DB
+func (P *Permission) TableName() string {
+	return "permissions"
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// RefreshToken — refresh token, mỗi lần dùng sinh token mới cùng family (rotation)
+//
+// Family = chuỗi token sinh ra từ 1 lần đăng nhập
+// Token đã dùng bị gửi lại → bị đánh cắp → thu hồi cả family (reuse detection)
+type RefreshToken struct {
+	ID        uint64     `json:"id"           gorm:"primaryKey;autoIncrement"`
+	UserID    uint       `json:"user_id"      gorm:"not null;index"`
+	FamilyID  string     `json:"family_id"    gorm:"type:char(36);not null;index"`
+	TokenHash string     `json:"-"            gorm:"type:char(64);not null;uniqueIndex"` // SHA-256 hex
+	ExpiresAt time.Time  `json:"expires_at"   gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`    // khác nil = đã đổi lấy token mới
+	RevokedAt *time.Time `json:"revoked_at"` // khác nil = bị thu hồi
+	CreatedAt time.Time  `json:"created_at"   gorm:"autoCreateTime"`
+}
+
+// khai báo tên bảng trong DB
+func (R *RefreshToken) TableName() string {
+	return "refresh_tokens"
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// Session — 1 lần đăng nhập trên 1 thiết bị
+// ID trùng với family_id của refresh token, access token mang ID này trong claim "sid"
+// → thu hồi session = refresh token hết dùng được + access token bị AuthMiddleware chặn ngay
+type Session struct {
+	ID          string     `json:"id"            gorm:"type:char(36);primaryKey"`
+	UserID      uint       `json:"user_id"       gorm:"not null;index"`
+	Device      string     `json:"device"        gorm:"type:varchar(255)"`
+	UserAgent   string     `json:"user_agent"    gorm:"type:varchar(512)"`
+	IP          string     `json:"ip"            gorm:"type:varchar(45)"`
+	LastSeenAt  time.Time  `json:"last_seen_at"  gorm:"not null"`
+	ExpiresAt   time.Time  `json:"expires_at"    gorm:"not null"`
+	RevokedAt   *time.Time `json:"revoked_at"`    // khác nil = đã đăng xuất / bị thu hồi
+	TwoFactorAt *time.Time `json:"two_factor_at"` // session đã qua bước 2FA lúc nào, nil = chỉ có mật khẩu
+	CreatedAt   time.Time  `json:"created_at"    gorm:"autoCreateTime"`
+}
+
+// khai báo tên bảng trong DB
+func (S *Session) TableName() string {
+	return "sessions"
+}
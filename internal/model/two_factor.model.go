@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+// TwoFactor — TOTP của user, ConfirmedAt nil = đang enrol (chưa nhập mã xác nhận)
+type TwoFactor struct {
+	UserID       uint       `json:"user_id"       gorm:"primaryKey;autoIncrement:false"`
+	Secret       string     `json:"-"             gorm:"type:varchar(255);not null"` // đã mã hóa (secure.Cipher)
+	LastUsedStep int64      `json:"-"             gorm:"not null;default:0"`         // bước TOTP dùng gần nhất, chống dùng lại mã
+	ConfirmedAt  *time.Time `json:"confirmed_at"`
+	CreatedAt    time.Time  `json:"created_at"    gorm:"autoCreateTime"`
+	UpdatedAt    time.Time  `json:"updated_at"    gorm:"autoUpdateTime"`
+}
+
+// khai báo tên bảng trong DB
+func (T *TwoFactor) TableName() string {
+	return "user_two_factors"
+}
+
+// RecoveryCode — mã dự phòng dùng 1 lần khi mất điện thoại, chỉ lưu hash
+type RecoveryCode struct {
+	ID        uint64     `json:"id"           gorm:"primaryKey;autoIncrement"`
+	UserID    uint       `json:"user_id"      gorm:"not null;index"`
+	CodeHash  string     `json:"-"            gorm:"type:char(64);not null;uniqueIndex"` // SHA-256 hex
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"   gorm:"autoCreateTime"`
+}
+
+// khai báo tên bảng trong DB
+func (R *RecoveryCode) TableName() string {
+	return "two_factor_recovery_codes"
+}
+
+// TwoFactorChallenge — đăng nhập đúng mật khẩu, đang chờ mã 2FA
+// Client giữ token gốc (pending token), DB chỉ lưu hash
+type TwoFactorChallenge struct {
+	ID        uint64     `json:"id"           gorm:"primaryKey;autoIncrement"`
+	UserID    uint       `json:"user_id"      gorm:"not null;index"`
+	TokenHash string     `json:"-"            gorm:"type:char(64);not null;uniqueIndex"`
+	Attempts  int        `json:"attempts"     gorm:"not null;default:0"` // số lần nhập sai
+	ExpiresAt time.Time  `json:"expires_at"   gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"   gorm:"autoCreateTime"`
+}
+
+// khai báo tên bảng trong DB
+func (T *TwoFactorChallenge) TableName() string {
+	return "two_factor_challenges"
+}
@@ -3,12 +3,15 @@ package model
 import "time"
 
 type User struct {
-	ID        uint      `json:"id"           gorm:"primarykey,autoIncrement"`
-	Name      string    `json:"name"         gorm:"not null"`
-	Email     string    `json:"email"        gorm:"not null"`
-	Password  string    `json:"password"     gorm:"not null"`
-	CreatedAt time.Time `json:"created_at"   gorm:"autoCreateTime"`
-	UpdatedAt time.Time `json:"updated_at"   gorm:"autoUpdateTime"`
+	ID              uint      `json:"id"           gorm:"primarykey,autoIncrement"`
+	Name            string    `json:"name"         gorm:"not null"                   validate:"required,min=2,max=255"`
+	Email           string    `json:"email"        gorm:"not null;uniqueIndex"       validate:"required,email,max=255,unique"`
+	Password        string    `json:"-"            gorm:"not null"                   validate:"required"` // hash bcrypt, KHÔNG bao giờ trả về client
+	UserCatalogueID *uint     `json:"user_catalogue_id" gorm:"index"`                                     // nhóm quyền, nil = chưa phân nhóm
+	CreatedAt       time.Time `json:"created_at"   gorm:"autoCreateTime"`
+	UpdatedAt       time.Time `json:"updated_at"   gorm:"autoUpdateTime"`
+
+	Catalogue *UserCatalogue `json:"catalogue,omitempty" gorm:"foreignKey:UserCatalogueID"`
 }
 
 // khai báo tên bảng trong DB
@@ -2,17 +2,31 @@ package model
 
 import "time" // dùng cho các field created_at, updated_at
 
+// Trạng thái publish của catalogue
+const (
+	PublishPrivate   uint = 0
+	PublishDraft     uint = 1
+	PublishPublished uint = 2
+)
+
+// DefaultCatalogueRole — role khi tạo catalogue không gửi role (cột role NOT NULL, không có default)
+const DefaultCatalogueRole = "user"
+
 // định nghĩa các field trong bảng user_catalogues
 type UserCatalogue struct {
 	// go sử dụng   type   struct tag sẽ trả về theo field trên DB thay vì go. gorm mapping struct -> table
-	ID          uint      `json:"id"               gorm:"primarykey,autoIncrement"`
-	Name        string    `json:"name"             gorm:"not null"`
-	Slug        string    `json:"slug"             gorm:"not null,unique"`
-	Description string    `json:"description"      gorm:"null"`
-	Role        string    `json:"role"             gorm:"not null,default:user"`
-	Publish     uint      `json:"publish"          gorm:"not null,default:2"`
-	CreatedAt   time.Time `json:"created_at"       gorm:"autoCreateTime"`
-	UpdatedAt   time.Time `json:"updated_at"       gorm:"autoUpdateTime"`
+	ID               uint      `json:"id"               gorm:"primarykey,autoIncrement"`
+	Name             string    `json:"name"             gorm:"not null"                validate:"required,min=2,max=255"`
+	Slug             string    `json:"slug"             gorm:"not null,unique"         validate:"omitempty,max=255,slug,unique"`
+	Description      string    `json:"description"      gorm:"null"`
+	Role             string    `json:"role"             gorm:"not null,default:user"   validate:"omitempty,max=255"`
+	Publish          uint      `json:"publish"          gorm:"not null,default:2"      validate:"omitempty,oneof=0 1 2"` // 0: private, 1: draft, 2: publish
+	RequireTwoFactor bool      `json:"require_two_factor" gorm:"not null;default:false"`                                 // bắt buộc user trong nhóm bật 2FA (VD: admin)
+	CreatedAt        time.Time `json:"created_at"       gorm:"autoCreateTime"`
+	UpdatedAt        time.Time `json:"updated_at"       gorm:"autoUpdateTime"`
+	Version          uint      `json:"-"                gorm:"not null;default:1"` // tăng mỗi lần ghi (repository tự tăng) → ETag đổi kể cả 2 lần ghi cùng giây
+
+	Permissions []Permission `json:"permissions,omitempty" gorm:"many2many:user_catalogue_permissions"`
 }
 
 // khai báo tên bảng trong DB
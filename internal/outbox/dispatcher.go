@@ -0,0 +1,311 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang-base/internal/model"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ============================================================
+// DISPATCHER — relay event từ bảng outbox sang Sink
+//
+// Mỗi vòng 3 bước, KHÔNG giữ transaction / row lock trong lúc gọi Sink (network I/O):
+//
+//  1. Claim — transaction ngắn
+//     BEGIN
+//     SELECT * FROM outbox
+//     WHERE status = 'pending' AND available_at <= NOW()
+//     AND NOT EXISTS (event pending cũ hơn của CÙNG aggregate)
+//     ORDER BY id LIMIT 100
+//     FOR UPDATE SKIP LOCKED
+//     UPDATE outbox SET attempts = attempts + 1, available_at = NOW() + ClaimTimeout WHERE id IN (...)
+//     COMMIT
+//  2. Publish — gọi Sink cho từng event, ngoài transaction
+//  3. Record — transaction ngắn: sent / retry (backoff) / dead
+//     UPDATE ... WHERE id = ? AND status = 'pending' AND attempts = <attempt đã claim>
+//
+// → FOR UPDATE SKIP LOCKED + lease (available_at đẩy lên ClaimTimeout): nhiều instance không gửi trùng 1 event
+// → dispatcher chết giữa bước 2 → hết lease thì instance khác claim lại (at-least-once)
+// → điều kiện attempts ở bước 3: lease đã hết + bị claim lại → bỏ kết quả cũ, không ghi đè
+// → NOT EXISTS: chỉ lấy event ĐẦU của mỗi aggregate, event đã claim vẫn là pending
+// → event sau không vượt mặt event trước đang gửi/đang lỗi/đang retry (giữ thứ tự)
+// ============================================================
+
+// Options — cấu hình dispatcher
+type Options struct {
+	PollInterval time.Duration // nghỉ giữa 2 vòng khi outbox trống
+	BatchSize    int           // số event tối đa mỗi vòng
+	MaxAttempts  int           // vượt quá → dead-letter
+	BaseBackoff  time.Duration // backoff lần retry đầu, nhân đôi mỗi lần
+	MaxBackoff   time.Duration // backoff tối đa
+	ClaimTimeout time.Duration // lease của event đã claim, phải lớn hơn thời gian publish cả batch
+}
+
+// DefaultOptions — giá trị mặc định an toàn
+func DefaultOptions() Options {
+	return Options{
+		PollInterval: time.Second,
+		BatchSize:    100,
+		MaxAttempts:  10,
+		BaseBackoff:  time.Second,
+		MaxBackoff:   5 * time.Minute,
+		ClaimTimeout: time.Minute,
+	}
+}
+
+type Dispatcher struct {
+	db   *gorm.DB
+	sink Sink
+	log  *zap.Logger
+	opts Options
+	done chan struct{}
+	now  func() time.Time // đồng hồ, test thay bằng đồng hồ giả
+}
+
+func NewDispatcher(db *gorm.DB, sink Sink, log *zap.Logger, opts Options) *Dispatcher {
+	defaults := DefaultOptions()
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = defaults.PollInterval
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaults.BatchSize
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaults.MaxAttempts
+	}
+	if opts.BaseBackoff <= 0 {
+		opts.BaseBackoff = defaults.BaseBackoff
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = defaults.MaxBackoff
+	}
+	if opts.ClaimTimeout <= 0 {
+		opts.ClaimTimeout = defaults.ClaimTimeout
+	}
+
+	return &Dispatcher{
+		db:   db,
+		sink: sink,
+		log:  log,
+		opts: opts,
+		done: make(chan struct{}),
+		now:  time.Now,
+	}
+}
+
+// Run — vòng lặp dispatch, dừng khi ctx bị cancel
+// Gọi trong goroutine: go dispatcher.Run(ctx)
+func (d *Dispatcher) Run(ctx context.Context) {
+	defer close(d.done)
+
+	d.log.Info("outbox dispatcher started",
+		zap.Duration("poll_interval", d.opts.PollInterval),
+		zap.Int("batch_size", d.opts.BatchSize),
+	)
+
+	for {
+		processed, err := d.DispatchBatch(ctx)
+		if err != nil && ctx.Err() == nil {
+			d.log.Error("outbox dispatch failed", zap.Error(err))
+		}
+
+		// Còn event → chạy tiếp ngay, outbox trống → nghỉ PollInterval
+		wait := d.opts.PollInterval
+		if processed > 0 && err == nil {
+			wait = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			d.log.Info("outbox dispatcher stopped")
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// Wait — chờ Run kết thúc hẳn (dùng khi graceful shutdown)
+func (d *Dispatcher) Wait() {
+	<-d.done
+}
+
+// DispatchBatch — xử lý 1 batch, trả về số event đã xử lý (kể cả lỗi)
+func (d *Dispatcher) DispatchBatch(ctx context.Context) (int, error) {
+	events, err := d.claim(ctx)
+	if err != nil || len(events) == 0 {
+		return 0, err
+	}
+
+	// ctx bị cancel giữa batch (shutdown) → dừng gửi, event chưa gửi được trả lại (nil)
+	results := make([]map[string]any, len(events))
+	for i := range events {
+		if ctx.Err() != nil {
+			break
+		}
+		results[i] = d.publish(ctx, &events[i])
+	}
+
+	// Ghi kết quả kể cả khi ctx đã cancel: event đã gửi phải được đánh dấu sent
+	if err := d.record(context.WithoutCancel(ctx), events, results); err != nil {
+		return len(events), err
+	}
+	return len(events), nil
+}
+
+// claim — lock batch kế tiếp, tăng attempts + đẩy available_at lên 1 lease rồi commit ngay
+// events trả về đã mang Attempts của lần gửi này
+func (d *Dispatcher) claim(ctx context.Context) ([]model.OutboxEvent, error) {
+	var events []model.OutboxEvent
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := d.now()
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND available_at <= ?", model.OutboxStatusPending, now).
+			Where(`NOT EXISTS (
+				SELECT 1 FROM outbox AS prev
+				WHERE prev.aggregate_type = outbox.aggregate_type
+				  AND prev.aggregate_id = outbox.aggregate_id
+				  AND prev.status = ?
+				  AND prev.id < outbox.id
+			)`, model.OutboxStatusPending).
+			Order("id asc").
+			Limit(d.opts.BatchSize).
+			Find(&events).Error
+		if err != nil {
+			return fmt.Errorf("fetch outbox events failed: %w", err)
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		ids := make([]uint64, len(events))
+		for i := range events {
+			ids[i] = events[i].ID
+			events[i].Attempts++
+		}
+		err = tx.Model(&model.OutboxEvent{}).
+			Where("id IN ?", ids).
+			Updates(map[string]any{
+				"attempts":     gorm.Expr("attempts + 1"),
+				"available_at": now.Add(d.opts.ClaimTimeout),
+			}).Error
+		if err != nil {
+			return fmt.Errorf("claim outbox events failed: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// publish — gửi 1 event đã claim, trả về các field cần cập nhật (sent / retry / dead)
+// Lỗi của Sink KHÔNG return ra ngoài, chỉ ghi nhận để retry
+func (d *Dispatcher) publish(ctx context.Context, event *model.OutboxEvent) map[string]any {
+	attempt := event.Attempts
+	publishErr := d.sink.Publish(ctx, Message{
+		ID:            event.ID,
+		AggregateType: event.AggregateType,
+		AggregateID:   event.AggregateID,
+		EventType:     event.EventType,
+		Payload:       []byte(event.Payload),
+		Attempt:       attempt,
+		CreatedAt:     event.CreatedAt,
+	})
+
+	now := d.now()
+	fields := map[string]any{}
+
+	switch {
+	case publishErr == nil:
+		fields["status"] = model.OutboxStatusSent
+		fields["processed_at"] = now
+		fields["last_error"] = ""
+	case attempt >= d.opts.MaxAttempts:
+		fields["status"] = model.OutboxStatusDead
+		fields["processed_at"] = now
+		fields["last_error"] = publishErr.Error()
+		d.log.Error("outbox event moved to dead-letter",
+			zap.Uint64("id", event.ID),
+			zap.String("event_type", event.EventType),
+			zap.String("aggregate_id", event.AggregateID),
+			zap.Int("attempts", attempt),
+			zap.Error(publishErr),
+		)
+	default:
+		fields["available_at"] = now.Add(d.backoff(attempt))
+		fields["last_error"] = publishErr.Error()
+		d.log.Warn("outbox event publish failed, will retry",
+			zap.Uint64("id", event.ID),
+			zap.String("event_type", event.EventType),
+			zap.Int("attempt", attempt),
+			zap.Error(publishErr),
+		)
+	}
+	return fields
+}
+
+// record — ghi kết quả cả batch trong 1 transaction ngắn
+// results[i] nil = chưa gửi (shutdown) → trả lại event: hoàn attempts, gửi lại ngay ở vòng sau
+// Chỉ lỗi DB mới trả ra ngoài; event chưa ghi được kết quả sẽ được claim lại khi hết lease
+func (d *Dispatcher) record(ctx context.Context, events []model.OutboxEvent, results []map[string]any) error {
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range events {
+			event, fields := &events[i], results[i]
+			if fields == nil {
+				fields = map[string]any{"attempts": event.Attempts - 1, "available_at": d.now()}
+			}
+
+			result := tx.Model(&model.OutboxEvent{}).
+				Where("id = ? AND status = ? AND attempts = ?", event.ID, model.OutboxStatusPending, event.Attempts).
+				Updates(fields)
+			if result.Error != nil {
+				return fmt.Errorf("update outbox event %d failed: %w", event.ID, result.Error)
+			}
+			if result.RowsAffected == 0 {
+				d.log.Warn("outbox event lease expired before its result was recorded",
+					zap.Uint64("id", event.ID),
+					zap.Int("attempt", event.Attempts),
+				)
+			}
+		}
+		return nil
+	})
+}
+
+// backoff — exponential: base, 2*base, 4*base... tối đa MaxBackoff
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	delay := d.opts.BaseBackoff
+	for i := 1; i < attempt && delay < d.opts.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > d.opts.MaxBackoff {
+		delay = d.opts.MaxBackoff
+	}
+	return delay
+}
+
+// Retry — đưa event dead-letter về pending để gửi lại (thao tác tay của admin)
+func Retry(db *gorm.DB, ids ...uint64) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	result := db.Model(&model.OutboxEvent{}).
+		Where("id IN ? AND status = ?", ids, model.OutboxStatusDead).
+		Updates(map[string]any{
+			"status":       model.OutboxStatusPending,
+			"attempts":     0,
+			"available_at": time.Now(),
+			"processed_at": nil,
+		})
+	if result.Error != nil {
+		return 0, fmt.Errorf("retry outbox events failed: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
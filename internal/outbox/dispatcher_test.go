@@ -0,0 +1,281 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang-base/internal/model"
+
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeSink — ghi lại message đã nhận, fail(msg) != nil → trả lỗi cho lần gửi đó
+type fakeSink struct {
+	mu       sync.Mutex
+	received []Message
+	fail     func(msg Message) error
+	during   func(ctx context.Context) // chạy trong lúc Publish (VD: kiểm tra không còn transaction mở)
+}
+
+func (s *fakeSink) Publish(ctx context.Context, msg Message) error {
+	if s.during != nil {
+		s.during(ctx)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.received = append(s.received, msg)
+	if s.fail != nil {
+		return s.fail(msg)
+	}
+	return nil
+}
+
+// delivered — "event_type#attempt" theo thứ tự nhận
+func (s *fakeSink) delivered() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]string, len(s.received))
+	for i, m := range s.received {
+		out[i] = fmt.Sprintf("%s#%d", m.EventType, m.Attempt)
+	}
+	return out
+}
+
+// newTestDispatcher — sqlite in-memory 1 connection: transaction còn mở trong lúc Publish → query khác bị chặn
+func newTestDispatcher(t *testing.T, sink Sink, opts Options) (*Dispatcher, *gorm.DB, *time.Time) {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", strings.ReplaceAll(t.Name(), "/", "_"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	if err := db.AutoMigrate(&model.OutboxEvent{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	// Đồng hồ giả đi trước 1s: event vừa Enqueue (available_at = time.Now()) đã tới hạn
+	now := time.Now().Add(time.Second)
+	d := NewDispatcher(db, sink, zap.NewNop(), opts)
+	d.now = func() time.Time { return now }
+	return d, db, &now
+}
+
+func enqueue(t *testing.T, db *gorm.DB, events ...Event) {
+	t.Helper()
+	if err := Enqueue(db, events...); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+}
+
+func event(aggregate, eventType string) Event {
+	return Event{AggregateType: "post", AggregateID: aggregate, EventType: eventType, Payload: map[string]any{"id": aggregate}}
+}
+
+func dispatch(t *testing.T, d *Dispatcher) int {
+	t.Helper()
+	n, err := d.DispatchBatch(context.Background())
+	if err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+	return n
+}
+
+func status(t *testing.T, db *gorm.DB, eventType string) model.OutboxEvent {
+	t.Helper()
+	var e model.OutboxEvent
+	if err := db.Where("event_type = ?", eventType).First(&e).Error; err != nil {
+		t.Fatalf("load %s: %v", eventType, err)
+	}
+	return e
+}
+
+func TestDispatchKeepsOrderPerAggregate(t *testing.T) {
+	failOnce := map[string]bool{"1.created": true}
+	sink := &fakeSink{fail: func(m Message) error {
+		if failOnce[m.EventType] {
+			delete(failOnce, m.EventType)
+			return errors.New("sink down")
+		}
+		return nil
+	}}
+	d, db, now := newTestDispatcher(t, sink, Options{BaseBackoff: time.Second})
+	enqueue(t, db, event("1", "1.created"), event("1", "1.updated"), event("2", "2.created"), event("1", "1.deleted"))
+
+	// Vòng 1: chỉ event ĐẦU của mỗi aggregate; 1.created lỗi → 1.updated / 1.deleted chưa được đi
+	if n := dispatch(t, d); n != 2 {
+		t.Fatalf("batch 1: expected 2 events, got %d", n)
+	}
+	// Còn trong backoff → aggregate 1 bị chặn hoàn toàn, không vượt mặt
+	if n := dispatch(t, d); n != 0 {
+		t.Fatalf("during backoff: expected 0 events, got %d", n)
+	}
+
+	*now = now.Add(time.Second)
+	for dispatch(t, d) > 0 {
+	}
+	want := []string{"1.created#1", "2.created#1", "1.created#2", "1.updated#1", "1.deleted#1"}
+	if got := sink.delivered(); !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if e := status(t, db, "1.created"); e.Status != model.OutboxStatusSent || e.Attempts != 2 || e.LastError != "" || e.ProcessedAt == nil {
+		t.Fatalf("retried event not marked sent: %+v", e)
+	}
+}
+
+func TestDispatchBackoff(t *testing.T) {
+	sink := &fakeSink{fail: func(Message) error { return errors.New("sink down") }}
+	d, db, now := newTestDispatcher(t, sink, Options{MaxAttempts: 10, BaseBackoff: time.Second, MaxBackoff: 4 * time.Second})
+	enqueue(t, db, event("1", "1.created"))
+
+	// Lỗi lần 1, 2, 3, 4 → chờ 1s, 2s, 4s, 4s (MaxBackoff)
+	for attempt, wait := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second} {
+		if n := dispatch(t, d); n != 1 {
+			t.Fatalf("attempt %d: expected 1 event, got %d", attempt+1, n)
+		}
+		e := status(t, db, "1.created")
+		if e.Attempts != attempt+1 || e.LastError != "sink down" || !e.AvailableAt.Equal(now.Add(wait)) {
+			t.Fatalf("attempt %d: expected retry at +%v, got %+v", attempt+1, wait, e)
+		}
+
+		*now = now.Add(wait - time.Millisecond)
+		if n := dispatch(t, d); n != 0 {
+			t.Fatalf("attempt %d: must wait %v before retrying", attempt+1, wait)
+		}
+		*now = now.Add(time.Millisecond)
+	}
+}
+
+func TestDispatchDeadLetterAndRetry(t *testing.T) {
+	down := true
+	sink := &fakeSink{fail: func(m Message) error {
+		if down && m.EventType == "1.created" {
+			return errors.New("poison")
+		}
+		return nil
+	}}
+	d, db, now := newTestDispatcher(t, sink, Options{MaxAttempts: 3, BaseBackoff: time.Second, MaxBackoff: time.Second})
+	enqueue(t, db, event("1", "1.created"), event("1", "1.updated"))
+
+	for i := 0; i < 3; i++ {
+		dispatch(t, d)
+		*now = now.Add(time.Second)
+	}
+	dead := status(t, db, "1.created")
+	if dead.Status != model.OutboxStatusDead || dead.Attempts != 3 || dead.LastError != "poison" || dead.ProcessedAt == nil {
+		t.Fatalf("expected dead-letter after 3 attempts, got %+v", dead)
+	}
+
+	// Dead không còn pending → event sau của aggregate được đi tiếp, event dead không bị gửi lại
+	for dispatch(t, d) > 0 {
+	}
+	if e := status(t, db, "1.updated"); e.Status != model.OutboxStatusSent {
+		t.Fatalf("next event must be sent after dead-letter, got %+v", e)
+	}
+	if got := sink.delivered(); len(got) != 4 {
+		t.Fatalf("dead event must not be published again, got %v", got)
+	}
+
+	// Admin sửa xong nguyên nhân → Retry: về pending, đếm lại từ attempt 1
+	down = false
+	if n, err := Retry(db, dead.ID, 999); err != nil || n != 1 {
+		t.Fatalf("retry: got %d, %v", n, err)
+	}
+	if n, _ := Retry(db, status(t, db, "1.updated").ID); n != 0 {
+		t.Fatal("retry must only touch dead events")
+	}
+	*now = now.Add(time.Second)
+	dispatch(t, d)
+	if e := status(t, db, "1.created"); e.Status != model.OutboxStatusSent || e.Attempts != 1 {
+		t.Fatalf("retried event: expected sent on attempt 1, got %+v", e)
+	}
+}
+
+func TestDispatchPublishesOutsideTransaction(t *testing.T) {
+	var db *gorm.DB
+	var queryErr error
+	sink := &fakeSink{during: func(ctx context.Context) {
+		// 1 connection duy nhất: nếu claim transaction còn mở, query này chờ tới timeout
+		ctx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		var n int64
+		queryErr = db.WithContext(ctx).Model(&model.OutboxEvent{}).Count(&n).Error
+	}}
+	d, testDB, _ := newTestDispatcher(t, sink, Options{})
+	db = testDB
+	enqueue(t, db, event("1", "1.created"))
+
+	dispatch(t, d)
+	if queryErr != nil {
+		t.Fatalf("sink ran while the claim transaction was open: %v", queryErr)
+	}
+	if e := status(t, db, "1.created"); e.Status != model.OutboxStatusSent {
+		t.Fatalf("expected sent, got %+v", e)
+	}
+}
+
+func TestClaimLease(t *testing.T) {
+	sink := &fakeSink{}
+	d, db, now := newTestDispatcher(t, sink, Options{ClaimTimeout: time.Minute})
+	enqueue(t, db, event("1", "1.created"))
+
+	// Dispatcher A claim rồi "chết" trước khi gửi
+	claimed, err := d.claim(context.Background())
+	if err != nil || len(claimed) != 1 || claimed[0].Attempts != 1 {
+		t.Fatalf("claim: got %+v, %v", claimed, err)
+	}
+	// Trong lease → instance khác không lấy được
+	if n := dispatch(t, d); n != 0 {
+		t.Fatalf("claimed event must not be dispatched twice, got %d", n)
+	}
+
+	// Hết lease → instance khác claim lại, attempt 2
+	*now = now.Add(time.Minute)
+	if n := dispatch(t, d); n != 1 {
+		t.Fatalf("expired lease: expected 1 event, got %d", n)
+	}
+	if got := sink.delivered(); !slices.Equal(got, []string{"1.created#2"}) {
+		t.Fatalf("expected re-claimed delivery, got %v", got)
+	}
+
+	// A sống lại, ghi kết quả cũ → bị bỏ qua, không ghi đè trạng thái mới
+	stale := map[string]any{"status": model.OutboxStatusDead, "last_error": "stale"}
+	if err := d.record(context.Background(), claimed, []map[string]any{stale}); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	if e := status(t, db, "1.created"); e.Status != model.OutboxStatusSent || e.LastError != "" {
+		t.Fatalf("stale result overwrote newer one: %+v", e)
+	}
+}
+
+func TestDispatchStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	sink := &fakeSink{during: func(context.Context) { cancel() }}
+	d, db, _ := newTestDispatcher(t, sink, Options{})
+	enqueue(t, db, event("1", "1.created"), event("2", "2.created"))
+
+	if _, err := d.DispatchBatch(ctx); err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+	// Event đã gửi vẫn được ghi sent dù ctx đã cancel; event chưa gửi được trả lại nguyên vẹn
+	if e := status(t, db, "1.created"); e.Status != model.OutboxStatusSent {
+		t.Fatalf("published event must be recorded, got %+v", e)
+	}
+	if e := status(t, db, "2.created"); e.Status != model.OutboxStatusPending || e.Attempts != 0 {
+		t.Fatalf("unpublished event must be released, got %+v", e)
+	}
+	if n := dispatch(t, d); n != 1 {
+		t.Fatalf("released event must be dispatched on the next batch, got %d", n)
+	}
+}
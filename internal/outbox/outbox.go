@@ -0,0 +1,91 @@
+// ============================================================
+// OUTBOX — Transactional outbox pattern
+//
+// Vấn đề:
+//
+//	tx.Create(&order) → COMMIT → publish("order.created") → 💥 crash
+//	→ data đã lưu nhưng event mất vĩnh viễn
+//
+// Giải pháp:
+// 1. Ghi event vào bảng outbox TRONG CÙNG transaction với data
+// → commit cả 2 hoặc rollback cả 2
+// 2. Dispatcher chạy nền đọc outbox → gửi sang Sink (in-process, Redis stream...)
+// → gửi thành công mới đánh dấu sent, lỗi thì retry với backoff
+//
+// Đảm bảo at-least-once: 1 event có thể được gửi > 1 lần (crash sau khi gửi, trước khi đánh dấu)
+// → consumer nên dedupe theo Message.ID
+// ============================================================
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"golang-base/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// Event — event cần ghi vào outbox
+type Event struct {
+	AggregateType string // VD: "user_catalogue"
+	AggregateID   string // ID của record, dùng để giữ thứ tự theo aggregate
+	EventType     string // VD: "user_catalogue.created"
+	Payload       any    // sẽ được json.Marshal
+}
+
+// Message — event đọc từ outbox, gửi sang Sink
+type Message struct {
+	ID            uint64 // ID trong bảng outbox → consumer dùng để dedupe
+	AggregateType string
+	AggregateID   string
+	EventType     string
+	Payload       []byte // JSON
+	Attempt       int    // lần gửi thứ mấy (bắt đầu từ 1)
+	CreatedAt     time.Time
+}
+
+// Enqueue — ghi events vào outbox bằng tx đang mở
+// BẮT BUỘC truyền tx của transaction đang ghi data, không phải DB gốc
+//
+// VD:
+//
+//	repo.Transaction(func(tx *gorm.DB) error {
+//	    if err := tx.Create(&order).Error; err != nil {
+//	        return err
+//	    }
+//	    return outbox.Enqueue(tx, outbox.Event{
+//	        AggregateType: "order",
+//	        AggregateID:   fmt.Sprint(order.ID),
+//	        EventType:     "order.created",
+//	        Payload:       order,
+//	    })
+//	})
+func Enqueue(tx *gorm.DB, events ...Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	records := make([]model.OutboxEvent, 0, len(events))
+	for _, e := range events {
+		payload, err := json.Marshal(e.Payload)
+		if err != nil {
+			return fmt.Errorf("marshal outbox payload failed: %w", err)
+		}
+		records = append(records, model.OutboxEvent{
+			AggregateType: e.AggregateType,
+			AggregateID:   e.AggregateID,
+			EventType:     e.EventType,
+			Payload:       string(payload),
+			Status:        model.OutboxStatusPending,
+			AvailableAt:   now,
+		})
+	}
+
+	if err := tx.Create(&records).Error; err != nil {
+		return fmt.Errorf("enqueue outbox failed: %w", err)
+	}
+	return nil
+}
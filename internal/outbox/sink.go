@@ -0,0 +1,113 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Sink — đích nhận event từ dispatcher
+// Trả error → dispatcher retry event đó (giữ nguyên thứ tự trong aggregate)
+type Sink interface {
+	Publish(ctx context.Context, msg Message) error
+}
+
+// ============================================================
+// LOCAL SINK — in-process, handler chạy ngay trong dispatcher
+// Phù hợp: monolith, side effect nội bộ (gửi mail, sync cache...)
+// ============================================================
+
+// Handler — xử lý 1 message từ outbox
+type Handler func(ctx context.Context, msg Message) error
+
+type LocalSink struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler // event type → handlers, "*" = mọi event
+}
+
+func NewLocalSink() *LocalSink {
+	return &LocalSink{handlers: make(map[string][]Handler)}
+}
+
+// Subscribe — đăng ký handler cho event type ("*" = nhận tất cả)
+// VD: sink.Subscribe("user_catalogue.updated", invalidateCache)
+func (s *LocalSink) Subscribe(eventType string, handler Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[eventType] = append(s.handlers[eventType], handler)
+}
+
+// Publish — gọi lần lượt tất cả handler, gom lỗi lại
+// Có lỗi → cả message được retry → handler nên idempotent
+func (s *LocalSink) Publish(ctx context.Context, msg Message) error {
+	s.mu.RLock()
+	handlers := make([]Handler, 0, len(s.handlers[msg.EventType])+len(s.handlers["*"]))
+	handlers = append(handlers, s.handlers[msg.EventType]...)
+	handlers = append(handlers, s.handlers["*"]...)
+	s.mu.RUnlock()
+
+	var errs []error
+	for _, handler := range handlers {
+		if err := safeHandle(ctx, handler, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// safeHandle — chạy handler, panic → error (không làm chết dispatcher)
+func safeHandle(ctx context.Context, handler Handler, msg Message) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("outbox handler panic: %v", r)
+		}
+	}()
+	return handler(ctx, msg)
+}
+
+// ============================================================
+// REDIS STREAM SINK — gửi event sang Redis Stream (XADD)
+//
+// Mỗi aggregate type 1 stream: "{prefix}:{aggregate_type}"
+// VD: outbox:user_catalogue
+// → consumer dùng XREADGROUP để đọc, hỗ trợ nhiều instance cùng consume
+// ============================================================
+type RedisStreamSink struct {
+	client *redis.Client
+	prefix string
+	maxLen int64 // giới hạn độ dài stream (xấp xỉ), 0 = không giới hạn
+}
+
+func NewRedisStreamSink(client *redis.Client, prefix string, maxLen int64) *RedisStreamSink {
+	if prefix == "" {
+		prefix = "outbox"
+	}
+	return &RedisStreamSink{client: client, prefix: prefix, maxLen: maxLen}
+}
+
+func (s *RedisStreamSink) Publish(ctx context.Context, msg Message) error {
+	args := &redis.XAddArgs{
+		Stream: s.prefix + ":" + msg.AggregateType,
+		Values: map[string]any{
+			"id":             strconv.FormatUint(msg.ID, 10),
+			"aggregate_type": msg.AggregateType,
+			"aggregate_id":   msg.AggregateID,
+			"event_type":     msg.EventType,
+			"payload":        string(msg.Payload),
+			"created_at":     msg.CreatedAt.UnixMilli(),
+		},
+	}
+	if s.maxLen > 0 {
+		args.MaxLen = s.maxLen
+		args.Approx = true
+	}
+
+	if err := s.client.XAdd(ctx, args).Err(); err != nil {
+		return fmt.Errorf("redis xadd failed: %w", err)
+	}
+	return nil
+}
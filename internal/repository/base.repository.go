@@ -1,8 +1,11 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"reflect"
 	"strings"
 
 	"golang-base/global/common"
@@ -11,6 +14,17 @@ import (
 	"gorm.io/gorm/clause"
 )
 
+// Sentinel — caller kiểm tra bằng errors.Is, tầng HTTP map sang 404 / 409 (xem base.RespondError)
+var (
+	// ErrNotFound — Update / Replace / UpdateFields / Delete / RestoreById không có record nào bị ảnh hưởng
+	// Lưu ý: FindById KHÔNG trả lỗi này (không tìm thấy → nil, nil)
+	ErrNotFound = errors.New("record not found")
+
+	// ErrDuplicateKey — vi phạm unique index (cần gorm.Config.TranslateError = true)
+	// Rule `unique` của validation chỉ so với data đã có → 2 request đồng thời vẫn có thể lọt xuống DB
+	ErrDuplicateKey = gorm.ErrDuplicatedKey
+)
+
 // ============================================================
 // BaseRepository — Generic repository pattern dùng Go Generics
 //
@@ -21,19 +35,30 @@ import (
 // → Viết 1 lần, dùng cho TẤT CẢ model
 // → Không cần copy-paste code cho mỗi model
 // → Type-safe: compiler bắt lỗi nếu truyền sai type
+//
+// K comparable: kiểu primary key của model
+// → uint / int64 cho auto increment
+// → string cho ULID, uuid.UUID cho UUIDv7
 // ============================================================
-type BaseRepository[T any] struct {
+type BaseRepository[T any, K comparable] struct {
 	DB *gorm.DB
+
+	// allowedRelations — whitelist relation được preload qua Specs, xem AllowRelations
+	allowedRelations map[string]struct{}
+
+	// scopes — điều kiện luôn áp vào query Paginate, xem WithScopes
+	scopes []func(*gorm.DB) *gorm.DB
 }
 
-func NewBaseRepository[T any](db *gorm.DB) *BaseRepository[T] {
-	return &BaseRepository[T]{DB: db}
+func NewBaseRepository[T any, K comparable](db *gorm.DB) *BaseRepository[T, K] {
+	return &BaseRepository[T, K]{DB: db}
 }
 
 // ============================================================
 // PAGINATE — tự chọn strategy (offset vs keyset) dựa vào specs
 // ============================================================
-func (r *BaseRepository[T]) Paginate(specs common.Specs) (*common.PaginateResult[T], error) {
+func (r *BaseRepository[T, K]) Paginate(specs common.Specs) (*common.PaginateResult[T], error) {
+	specs = specs.ApplyPage()
 	if specs.UseKeyset {
 		return r.keysetPaginate(specs)
 	}
@@ -50,7 +75,7 @@ func (r *BaseRepository[T]) Paginate(specs common.Specs) (*common.PaginateResult
 // Phù hợp: Admin panel, danh sách < 100k data
 // Không phù hợp: Feed, infinite scroll, data > 100k
 // ============================================================
-func (r *BaseRepository[T]) offsetPaginate(specs common.Specs) (*common.PaginateResult[T], error) {
+func (r *BaseRepository[T, K]) offsetPaginate(specs common.Specs) (*common.PaginateResult[T], error) {
 	var (
 		data  []T
 		total int64
@@ -86,6 +111,7 @@ func (r *BaseRepository[T]) offsetPaginate(specs common.Specs) (*common.Paginate
 		Data:    data,
 		Total:   total,
 		HasMore: hasMore,
+		Meta:    common.NewPageMeta(total, specs.Limit, specs.Offset, len(data)),
 	}, nil
 }
 
@@ -108,7 +134,7 @@ func (r *BaseRepository[T]) offsetPaginate(specs common.Specs) (*common.Paginate
 // → Bỏ record thứ 21, chỉ trả 20
 // → KHÔNG cần COUNT(*) → nhanh hơn nhiều
 // ============================================================
-func (r *BaseRepository[T]) keysetPaginate(specs common.Specs) (*common.PaginateResult[T], error) {
+func (r *BaseRepository[T, K]) keysetPaginate(specs common.Specs) (*common.PaginateResult[T], error) {
 	var data []T
 
 	query := r.buildBaseQuery(specs)
@@ -151,11 +177,16 @@ func (r *BaseRepository[T]) keysetPaginate(specs common.Specs) (*common.Paginate
 		data = data[:specs.Limit] // bỏ record thừa
 	}
 
-	// NextCursor = index trong slice, caller tự extract giá trị
-	// VD: nextCursor = data[idx].ID
+	// NextCursor = giá trị cursor field của record cuối trang
+	// → client gửi lại nguyên giá trị này ở request sau (CursorValue)
+	// → hoạt động với mọi kiểu key: số tự tăng, UUIDv7, ULID (đều sắp xếp được theo thời gian)
 	var nextCursor any
 	if hasMore && len(data) > 0 {
-		nextCursor = len(data) - 1
+		value, err := r.fieldValue(&data[len(data)-1], specs.CursorField)
+		if err != nil {
+			return nil, err
+		}
+		nextCursor = value
 	}
 
 	return &common.PaginateResult[T]{
@@ -170,10 +201,18 @@ func (r *BaseRepository[T]) keysetPaginate(specs common.Specs) (*common.Paginate
 // buildBaseQuery — dựng query chung cho mọi pagination strategy
 //
 // Pipeline: SELECT fields → Preload → WHERE filters → Range → IN → Keyword
+//
+// Filters / RangeFilters / InFilters nhận cả relation path ("Category.slug")
+// → xem whereField trong relation.go
 // ============================================================
-func (r *BaseRepository[T]) buildBaseQuery(specs common.Specs) *gorm.DB {
+func (r *BaseRepository[T, K]) buildBaseQuery(specs common.Specs) *gorm.DB {
 	query := r.DB.Model(new(T)) // khởi tạo query từ model
 
+	// Scope bắt buộc (VD: policy chỉ cho xem record của mình) → áp trước mọi filter của client
+	for _, scope := range r.scopes {
+		query = scope(query)
+	}
+
 	// Select fields — tránh SELECT *
 	// Khi listing 1000 products, nếu mỗi product có description 5KB
 	// → SELECT * = 5MB data thừa
@@ -186,16 +225,24 @@ func (r *BaseRepository[T]) buildBaseQuery(specs common.Specs) *gorm.DB {
 	// Giải quyết N+1 problem:
 	// → Không preload: 1 query products + N query categories = N+1 queries
 	// → Có preload: 1 query products + 1 query categories = 2 queries
+	//
+	// Relations (chỉ tên) và Preloads (có điều kiện) đều validate theo schema, xem preload.go
+	preloads := make([]common.RelationSpec, 0, len(specs.Relations)+len(specs.Preloads))
 	for _, relation := range specs.Relations {
-		query = query.Preload(relation)
+		preloads = append(preloads, common.RelationSpec{Name: relation})
 	}
+	preloads = append(preloads, specs.Preloads...)
+	query = r.applyPreloads(query, preloads)
 
 	// Exact match filters — WHERE field = value
+	// Field dạng "Relation.column" → lọc theo quan hệ (EXISTS subquery), xem relation.go
 	for field, value := range specs.Filters {
 		if !validFieldName(field) {
 			continue // bỏ qua field không hợp lệ → chống SQL injection
 		}
-		query = query.Where(field+" = ?", value)
+		query = r.whereField(query, field, func(col string) string {
+			return col + " = ?"
+		}, value)
 	}
 
 	// Range filters — WHERE field >= min AND field <= max
@@ -207,12 +254,24 @@ func (r *BaseRepository[T]) buildBaseQuery(specs common.Specs) *gorm.DB {
 		if !validFieldName(field) {
 			continue
 		}
+		// Min/Max gộp vào 1 điều kiện → với relation has-many,
+		// cả 2 cận phải thỏa trên CÙNG 1 row con
+		conditions := make([]string, 0, 2)
+		args := make([]any, 0, 2)
 		if rf.Min != nil {
-			query = query.Where(field+" >= ?", rf.Min)
+			conditions = append(conditions, "%[1]s >= ?")
+			args = append(args, rf.Min)
 		}
 		if rf.Max != nil {
-			query = query.Where(field+" <= ?", rf.Max)
+			conditions = append(conditions, "%[1]s <= ?")
+			args = append(args, rf.Max)
+		}
+		if len(conditions) == 0 {
+			continue
 		}
+		query = r.whereField(query, field, func(col string) string {
+			return "(" + fmt.Sprintf(strings.Join(conditions, " AND "), col) + ")"
+		}, args...)
 	}
 
 	// IN filters — WHERE field IN (v1, v2, v3)
@@ -223,7 +282,9 @@ func (r *BaseRepository[T]) buildBaseQuery(specs common.Specs) *gorm.DB {
 			continue
 		}
 		if len(values) > 0 {
-			query = query.Where(field+" IN ?", values)
+			query = r.whereField(query, field, func(col string) string {
+				return col + " IN ?"
+			}, values)
 		}
 	}
 
@@ -259,7 +320,7 @@ func (r *BaseRepository[T]) buildBaseQuery(specs common.Specs) *gorm.DB {
 // ============================================================
 
 // Create — tạo 1 record mới
-func (r *BaseRepository[T]) Create(payload *T) error {
+func (r *BaseRepository[T, K]) Create(payload *T) error {
 	if err := r.DB.Create(payload).Error; err != nil {
 		return fmt.Errorf("create failed: %w", err)
 	}
@@ -269,7 +330,7 @@ func (r *BaseRepository[T]) Create(payload *T) error {
 // Insert — batch insert nhiều data cùng lúc
 // GORM tự động chia thành chunks nếu quá nhiều
 // VD: import 10000 sản phẩm từ CSV
-func (r *BaseRepository[T]) Insert(payloads []T) error {
+func (r *BaseRepository[T, K]) Insert(payloads []T) error {
 	if len(payloads) == 0 {
 		return nil // không có gì để insert → skip
 	}
@@ -286,7 +347,7 @@ func (r *BaseRepository[T]) Insert(payloads []T) error {
 // → Dễ retry nếu 1 batch fail
 //
 // VD: InsertInBatches(products, 500) → insert 500 data/lần
-func (r *BaseRepository[T]) InsertInBatches(payloads []T, batchSize int) error {
+func (r *BaseRepository[T, K]) InsertInBatches(payloads []T, batchSize int) error {
 	if len(payloads) == 0 {
 		return nil
 	}
@@ -303,21 +364,40 @@ func (r *BaseRepository[T]) InsertInBatches(payloads []T, batchSize int) error {
 //
 // VD: payload.IsActive = false (bool zero value) → BỊ BỎ QUA
 // → Dùng UpdateFields() với map nếu cần update zero values
-func (r *BaseRepository[T]) Update(id uint, payload *T) error {
-	result := r.DB.Model(new(T)).Where("id = ?", id).Updates(payload)
+func (r *BaseRepository[T, K]) Update(id K, payload *T) error {
+	result := r.DB.Model(new(T)).Where(r.primaryKey()+" = ?", id).Omit(r.versionColumns()...).Updates(payload)
 	if result.Error != nil {
 		return fmt.Errorf("update failed: %w", result.Error)
 	}
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("record not found, ID: %d", id)
+	if err := r.checkAffected(result, id); err != nil {
+		return err
 	}
-	return nil
+	return r.bumpVersion(result, id)
+}
+
+// Replace — full update KỂ CẢ zero values theo ID
+// Dùng cho HTTP PUT — ghi đè mọi column trừ primary key, created_at và quan hệ
+// Khác Save: payload không cần mang ID, record không tồn tại → lỗi (Save sẽ INSERT)
+func (r *BaseRepository[T, K]) Replace(id K, payload *T) error {
+	result := r.DB.Model(new(T)).
+		Where(r.primaryKey()+" = ?", id).
+		Select("*").
+		Omit(append(r.versionColumns(), r.primaryKey(), "created_at", clause.Associations)...).
+		Updates(payload)
+	if result.Error != nil {
+		return fmt.Errorf("replace failed: %w", result.Error)
+	}
+	if err := r.checkAffected(result, id); err != nil {
+		return err
+	}
+	return r.bumpVersion(result, id)
 }
 
 // Save — full update KỂ CẢ zero values
 // Dùng cho HTTP PUT — ghi đè toàn bộ
 // VD: set IsActive=false (bool zero value) vẫn được lưu
-func (r *BaseRepository[T]) Save(payload *T) error {
+func (r *BaseRepository[T, K]) Save(payload *T) error {
+	r.incrementVersion(payload)
 	if err := r.DB.Save(payload).Error; err != nil {
 		return fmt.Errorf("save failed: %w", err)
 	}
@@ -328,33 +408,113 @@ func (r *BaseRepository[T]) Save(payload *T) error {
 // Giải quyết vấn đề zero value của Updates(struct)
 // VD: UpdateFields(1, map[string]any{"is_active": false, "stock": 0})
 // → false và 0 đều được lưu đúng, không bị skip
-func (r *BaseRepository[T]) UpdateFields(id uint, fields map[string]any) error {
-	result := r.DB.Model(new(T)).Where("id = ?", id).Updates(fields)
+func (r *BaseRepository[T, K]) UpdateFields(id K, fields map[string]any) error {
+	result := r.DB.Model(new(T)).Where(r.primaryKey()+" = ?", id).Updates(r.withVersion(fields))
 	if result.Error != nil {
 		return fmt.Errorf("update fields failed: %w", result.Error)
 	}
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("record not found, ID: %d", id)
+	return r.checkAffected(result, id)
+}
+
+// checkAffected — UPDATE theo ID không đổi row nào → record không tồn tại?
+// MySQL chỉ đếm row THỰC SỰ đổi giá trị: ghi lại đúng giá trị cũ (PATCH lặp lại, 2 lần ghi cùng giây
+// với updated_at TIMESTAMP...) cũng ra RowsAffected = 0 → kiểm tra tồn tại riêng, không trả 404 sai
+func (r *BaseRepository[T, K]) checkAffected(result *gorm.DB, id K) error {
+	if result.RowsAffected > 0 {
+		return nil
+	}
+	exists, err := r.ExistsById(id)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w, ID: %v", ErrNotFound, id)
 	}
 	return nil
 }
 
+// ============================================================
+// VERSION — model có field Version (VD: UserCatalogue) → mỗi lần ghi tăng version lên 1
+// ETag = updated_at + version (xem base/etag.go): updated_at TIMESTAMP chỉ chính xác tới giây
+// → 2 lần ghi trong cùng 1 giây vẫn ra ETag khác, If-Match cầm ETag cũ → 412
+//
+// Version luôn do repository tăng, không bao giờ lấy từ payload:
+// → UpdateFields / BulkUpdateFields / Upsert: thêm version = version + 1 vào cùng câu lệnh
+// → Update / Replace (payload struct, GORM không trộn được biểu thức vào SET): câu UPDATE thứ 2 khi row thực sự đổi
+// → Save: tăng field Version của payload (payload đọc từ DB)
+// ============================================================
+
+// versionColumns — Omit(...) cho Update / Replace: []string{"version"}, rỗng nếu model không có Version
+func (r *BaseRepository[T, K]) versionColumns() []string {
+	if f := r.versionField(); f != nil {
+		return []string{f.DBName}
+	}
+	return nil
+}
+
+func versionExpr(column string) clause.Expr {
+	return gorm.Expr("? + 1", clause.Column{Name: column})
+}
+
+// withVersion — bản copy của fields kèm version = version + 1 (không sửa map của caller)
+func (r *BaseRepository[T, K]) withVersion(fields map[string]any) map[string]any {
+	f := r.versionField()
+	if f == nil {
+		return fields
+	}
+	versioned := make(map[string]any, len(fields)+1)
+	maps.Copy(versioned, fields)
+	versioned[f.DBName] = versionExpr(f.DBName)
+	return versioned
+}
+
+// bumpVersion — sau Update / Replace: row thực sự đổi → version + 1
+// Chạy trên r.DB → cùng transaction với câu UPDATE khi gọi qua WithTx
+func (r *BaseRepository[T, K]) bumpVersion(result *gorm.DB, id K) error {
+	f := r.versionField()
+	if f == nil || result.RowsAffected == 0 {
+		return nil
+	}
+	err := r.DB.Model(new(T)).Where(r.primaryKey()+" = ?", id).UpdateColumn(f.DBName, versionExpr(f.DBName)).Error
+	if err != nil {
+		return fmt.Errorf("bump version failed: %w", err)
+	}
+	return nil
+}
+
+// incrementVersion — Save ghi nguyên payload → tăng Version trên payload trước khi ghi
+func (r *BaseRepository[T, K]) incrementVersion(payload *T) {
+	f := r.versionField()
+	if f == nil {
+		return
+	}
+	v := f.ReflectValueOf(context.Background(), reflect.ValueOf(payload).Elem())
+	switch {
+	case v.CanUint():
+		v.SetUint(v.Uint() + 1)
+	case v.CanInt():
+		v.SetInt(v.Int() + 1)
+	}
+}
+
 // BulkUpdateFields — update nhiều data theo điều kiện
 // VD: đổi trạng thái tất cả orders quá hạn → "cancelled"
 //
 //	BulkUpdateFields({"status":"pending"}, {"status":"cancelled"})
 //
-// conditions: WHERE clause (field → value)
+// conditions: WHERE clause (field → value, value là slice → IN)
 // fields: SET clause (field → new value)
-func (r *BaseRepository[T]) BulkUpdateFields(conditions map[string]any, fields map[string]any) (int64, error) {
+//
+//	BulkUpdateFields({"id": []uint{1, 2, 3}}, {"publish": 2})
+func (r *BaseRepository[T, K]) BulkUpdateFields(conditions map[string]any, fields map[string]any) (int64, error) {
 	query := r.DB.Model(new(T))
 	for field, value := range conditions {
 		if !validFieldName(field) {
 			continue
 		}
-		query = query.Where(field+" = ?", value)
+		query = whereEquals(query, field, value)
 	}
-	result := query.Updates(fields)
+	result := query.Updates(r.withVersion(fields))
 	if result.Error != nil {
 		return 0, fmt.Errorf("bulk update failed: %w", result.Error)
 	}
@@ -379,13 +539,16 @@ func (r *BaseRepository[T]) BulkUpdateFields(conditions map[string]any, fields m
 // updateColumns: field(s) cần update nếu conflict
 //
 //	VD: []string{"quantity", "updated_at"} → chỉ update quantity, giữ nguyên các field khác
-func (r *BaseRepository[T]) Upsert(payload *T, conflictColumns []string, updateColumns []string) error {
+func (r *BaseRepository[T, K]) Upsert(payload *T, conflictColumns []string, updateColumns []string) error {
 	columns := make([]clause.Column, len(conflictColumns))
 	for i, col := range conflictColumns {
 		columns[i] = clause.Column{Name: col}
 	}
 
 	doUpdates := clause.AssignmentColumns(updateColumns)
+	if f := r.versionField(); f != nil {
+		doUpdates = append(doUpdates, clause.Assignment{Column: clause.Column{Name: f.DBName}, Value: versionExpr(f.DBName)})
+	}
 
 	if err := r.DB.Clauses(clause.OnConflict{
 		Columns:   columns,
@@ -399,24 +562,27 @@ func (r *BaseRepository[T]) Upsert(payload *T, conflictColumns []string, updateC
 // Delete — xóa mềm hoặc cứng tùy model
 // Nếu model có field gorm.DeletedAt → soft delete (đánh dấu deleted_at)
 // Nếu không có → hard delete (xóa hẳn khỏi DB)
-func (r *BaseRepository[T]) Delete(id uint) error {
-	result := r.DB.Delete(new(T), id)
+//
+// Luôn dùng Where(pk = ?) thay vì Delete(model, id):
+// GORM coi id kiểu string là câu SQL thô → UUID/ULID sẽ sai (và nguy hiểm)
+func (r *BaseRepository[T, K]) Delete(id K) error {
+	result := r.DB.Where(r.primaryKey()+" = ?", id).Delete(new(T))
 	if result.Error != nil {
 		return fmt.Errorf("delete failed: %w", result.Error)
 	}
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("record not found, ID: %d", id)
+		return fmt.Errorf("%w, ID: %v", ErrNotFound, id)
 	}
 	return nil
 }
 
 // BulkDelete — xóa nhiều data cùng lúc theo mảng IDs
 // VD: admin chọn 50 sản phẩm → xóa hàng loạt
-func (r *BaseRepository[T]) BulkDelete(ids []uint) error {
+func (r *BaseRepository[T, K]) BulkDelete(ids []K) error {
 	if len(ids) == 0 {
 		return nil
 	}
-	result := r.DB.Where("id IN ?", ids).Delete(new(T))
+	result := r.DB.Where(r.primaryKey()+" IN ?", ids).Delete(new(T))
 	if result.Error != nil {
 		return fmt.Errorf("bulk delete failed: %w", result.Error)
 	}
@@ -427,7 +593,7 @@ func (r *BaseRepository[T]) BulkDelete(ids []uint) error {
 // VD: xóa tất cả cart items của user khi checkout xong
 //
 //	DeleteByField("user_id", 5)
-func (r *BaseRepository[T]) DeleteByField(field string, value any) (int64, error) {
+func (r *BaseRepository[T, K]) DeleteByField(field string, value any) (int64, error) {
 	if !validFieldName(field) {
 		return 0, fmt.Errorf("invalid field name: %s", field)
 	}
@@ -443,19 +609,34 @@ func (r *BaseRepository[T]) DeleteByField(field string, value any) (int64, error
 //
 // Unscoped() = bỏ qua default WHERE deleted_at IS NULL
 // → Có thể thấy và update cả record đã bị soft delete
-func (r *BaseRepository[T]) RestoreById(id uint) error {
+func (r *BaseRepository[T, K]) RestoreById(id K) error {
 	result := r.DB.Unscoped().Model(new(T)).
-		Where("id = ?", id).
+		Where(r.primaryKey()+" = ?", id).
 		Update("deleted_at", nil)
 	if result.Error != nil {
 		return fmt.Errorf("restore failed: %w", result.Error)
 	}
 	if result.RowsAffected == 0 {
-		return fmt.Errorf("record not found or not deleted, ID: %d", id)
+		return fmt.Errorf("%w or not deleted, ID: %v", ErrNotFound, id)
 	}
 	return nil
 }
 
+// BulkRestore — khôi phục nhiều record đã soft delete theo mảng IDs
+// Trả về số record thực sự được khôi phục (record chưa bị xóa không tính)
+func (r *BaseRepository[T, K]) BulkRestore(ids []K) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	result := r.DB.Unscoped().Model(new(T)).
+		Where(r.primaryKey()+" IN ? AND deleted_at IS NOT NULL", ids).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return 0, fmt.Errorf("bulk restore failed: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
 // ============================================================
 // FIND — Các method tìm kiếm
 // ============================================================
@@ -463,13 +644,13 @@ func (r *BaseRepository[T]) RestoreById(id uint) error {
 // FindById — tìm 1 record theo ID
 // Phân biệt rõ: không tìm thấy (ErrRecordNotFound) vs lỗi DB thật
 // → Caller có thể xử lý khác nhau: 404 vs 500
-func (r *BaseRepository[T]) FindById(id uint, relations []string) (*T, error) {
+func (r *BaseRepository[T, K]) FindById(id K, relations []string) (*T, error) {
 	var record T
-	query := r.DB
+	query := r.DB.Where(r.primaryKey()+" = ?", id)
 	for _, rel := range relations {
 		query = query.Preload(rel)
 	}
-	err := query.First(&record, id).Error
+	err := query.First(&record).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil // nil, nil = không tìm thấy (KHÔNG phải lỗi)
@@ -482,7 +663,7 @@ func (r *BaseRepository[T]) FindById(id uint, relations []string) (*T, error) {
 // FindByField — tìm 1 record theo 1 field bất kỳ
 // VD: FindByField("email", "user@example.com", []string{"Profile"})
 // VD: FindByField("slug", "iphone-15-pro", []string{"Category", "Brand"})
-func (r *BaseRepository[T]) FindByField(field string, value any, relations []string) (*T, error) {
+func (r *BaseRepository[T, K]) FindByField(field string, value any, relations []string) (*T, error) {
 	if !validFieldName(field) {
 		return nil, fmt.Errorf("invalid field name: %s", field)
 	}
@@ -504,7 +685,7 @@ func (r *BaseRepository[T]) FindByField(field string, value any, relations []str
 // FindManyByField — tìm NHIỀU data theo 1 field
 // VD: tất cả orders của user_id = 5
 // VD: tất cả products có category_id = 3
-func (r *BaseRepository[T]) FindManyByField(field string, value any, relations []string, sort string) ([]T, error) {
+func (r *BaseRepository[T, K]) FindManyByField(field string, value any, relations []string, sort string) ([]T, error) {
 	if !validFieldName(field) {
 		return nil, fmt.Errorf("invalid field name: %s", field)
 	}
@@ -525,7 +706,7 @@ func (r *BaseRepository[T]) FindManyByField(field string, value any, relations [
 // FindByFields — tìm 1 record theo NHIỀU điều kiện AND
 // VD: FindByFields(map{"email":"a@b.com", "is_active": true}, []string{})
 // → WHERE email = 'a@b.com' AND is_active = true
-func (r *BaseRepository[T]) FindByFields(conditions map[string]any, relations []string) (*T, error) {
+func (r *BaseRepository[T, K]) FindByFields(conditions map[string]any, relations []string) (*T, error) {
 	var record T
 	query := r.DB
 	for field, value := range conditions {
@@ -552,7 +733,7 @@ func (r *BaseRepository[T]) FindByFields(conditions map[string]any, relations []
 // → WHERE id IN (1, 2, 3) ORDER BY created_at desc
 //
 // Ứng dụng: lấy danh sách sản phẩm trong giỏ hàng từ mảng product IDs
-func (r *BaseRepository[T]) FindWhereIn(field string, values []any, relations []string, sort string) ([]T, error) {
+func (r *BaseRepository[T, K]) FindWhereIn(field string, values []any, relations []string, sort string) ([]T, error) {
 	if !validFieldName(field) {
 		return nil, fmt.Errorf("invalid field name: %s", field)
 	}
@@ -570,9 +751,53 @@ func (r *BaseRepository[T]) FindWhereIn(field string, values []any, relations []
 	return data, nil
 }
 
+// FindByIds — lấy nhiều record theo mảng IDs (record đã soft delete bị bỏ qua)
+func (r *BaseRepository[T, K]) FindByIds(ids []K) ([]T, error) {
+	var data []T
+	if len(ids) == 0 {
+		return data, nil
+	}
+	if err := r.DB.Where(r.primaryKey()+" IN ?", ids).Find(&data).Error; err != nil {
+		return nil, fmt.Errorf("find by ids failed: %w", err)
+	}
+	return data, nil
+}
+
+// FindTrashedByIds — lấy các record ĐÃ soft delete theo mảng IDs (dùng trước khi restore)
+func (r *BaseRepository[T, K]) FindTrashedByIds(ids []K) ([]T, error) {
+	var data []T
+	if len(ids) == 0 {
+		return data, nil
+	}
+	err := r.DB.Unscoped().
+		Where(r.primaryKey()+" IN ? AND deleted_at IS NOT NULL", ids).
+		Find(&data).Error
+	if err != nil {
+		return nil, fmt.Errorf("find trashed by ids failed: %w", err)
+	}
+	return data, nil
+}
+
+// FindAllByFields — lấy TẤT CẢ record khớp nhiều điều kiện (AND)
+// Cùng cách dựng WHERE với BulkUpdateFields → dùng để xem trước các record sắp bị bulk update
+func (r *BaseRepository[T, K]) FindAllByFields(conditions map[string]any) ([]T, error) {
+	var data []T
+	query := r.DB.Model(new(T))
+	for field, value := range conditions {
+		if !validFieldName(field) {
+			continue
+		}
+		query = whereEquals(query, field, value)
+	}
+	if err := query.Find(&data).Error; err != nil {
+		return nil, fmt.Errorf("find all by fields failed: %w", err)
+	}
+	return data, nil
+}
+
 // FindLimit — lấy N data đầu tiên
 // VD: "Top 10 sản phẩm bán chạy", "5 đơn hàng gần nhất"
-func (r *BaseRepository[T]) FindLimit(limit int, sort string, relations []string) ([]T, error) {
+func (r *BaseRepository[T, K]) FindLimit(limit int, sort string, relations []string) ([]T, error) {
 	var data []T
 	query := r.DB.Limit(limit)
 	for _, rel := range relations {
@@ -594,9 +819,9 @@ func (r *BaseRepository[T]) FindLimit(limit int, sort string, relations []string
 // ExistsById — check record có tồn tại không
 // Dùng SELECT 1 LIMIT 1 thay vì COUNT(*) → nhanh hơn
 // COUNT phải đếm TẤT CẢ rows match, EXISTS chỉ cần tìm 1 row
-func (r *BaseRepository[T]) ExistsById(id uint) (bool, error) {
+func (r *BaseRepository[T, K]) ExistsById(id K) (bool, error) {
 	var count int64
-	err := r.DB.Model(new(T)).Where("id = ?", id).Limit(1).Count(&count).Error
+	err := r.DB.Model(new(T)).Where(r.primaryKey()+" = ?", id).Limit(1).Count(&count).Error
 	if err != nil {
 		return false, fmt.Errorf("exists check failed: %w", err)
 	}
@@ -606,7 +831,7 @@ func (r *BaseRepository[T]) ExistsById(id uint) (bool, error) {
 // ExistsByField — check tồn tại theo field bất kỳ
 // VD: ExistsByField("email", "user@example.com") → true/false
 // Dùng khi: validate unique email, check slug trùng
-func (r *BaseRepository[T]) ExistsByField(field string, value any) (bool, error) {
+func (r *BaseRepository[T, K]) ExistsByField(field string, value any) (bool, error) {
 	if !validFieldName(field) {
 		return false, fmt.Errorf("invalid field name: %s", field)
 	}
@@ -618,8 +843,25 @@ func (r *BaseRepository[T]) ExistsByField(field string, value any) (bool, error)
 	return count > 0, nil
 }
 
+// ExistsByFieldExcept — như ExistsByField nhưng bỏ qua 1 record theo ID
+// Dùng khi update: đổi email/slug thành giá trị trùng chính nó thì không tính là trùng
+func (r *BaseRepository[T, K]) ExistsByFieldExcept(field string, value any, id K) (bool, error) {
+	if !validFieldName(field) {
+		return false, fmt.Errorf("invalid field name: %s", field)
+	}
+	var count int64
+	err := r.DB.Model(new(T)).
+		Where(field+" = ?", value).
+		Where(r.primaryKey()+" <> ?", id).
+		Limit(1).Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("exists check failed: %w", err)
+	}
+	return count > 0, nil
+}
+
 // Count — đếm data theo filter
-func (r *BaseRepository[T]) Count(filters map[string]any) (int64, error) {
+func (r *BaseRepository[T, K]) Count(filters map[string]any) (int64, error) {
 	var count int64
 	query := r.DB.Model(new(T))
 	for field, value := range filters {
@@ -658,7 +900,7 @@ func (r *BaseRepository[T]) Count(filters map[string]any) (int64, error) {
 // VD: Aggregate("AVG", "price", map{"category_id": 5})
 //
 //	→ Giá trung bình sản phẩm trong category 5
-func (r *BaseRepository[T]) Aggregate(fn string, field string, filters map[string]any) (float64, error) {
+func (r *BaseRepository[T, K]) Aggregate(fn string, field string, filters map[string]any) (float64, error) {
 	// Validate inputs
 	allowedFns := map[string]bool{"SUM": true, "AVG": true, "COUNT": true, "MIN": true, "MAX": true}
 	if !allowedFns[strings.ToUpper(fn)] {
@@ -717,10 +959,37 @@ func (r *BaseRepository[T]) Aggregate(fn string, field string, filters map[strin
 //	    }
 //	    return nil // → commit tất cả
 //	})
-func (r *BaseRepository[T]) Transaction(fn func(tx *gorm.DB) error) error {
+func (r *BaseRepository[T, K]) Transaction(fn func(tx *gorm.DB) error) error {
 	return r.DB.Transaction(fn) // mở transaction
 }
 
+// WithTx — bản sao repository chạy trên tx, dùng lại được mọi method CRUD trong transaction
+//
+// VD:
+//
+//	repo.Transaction(func(tx *gorm.DB) error {
+//	    txRepo := repo.WithTx(tx)
+//	    if err := txRepo.Create(&order); err != nil {
+//	        return err
+//	    }
+//	    return outbox.Enqueue(tx, event)
+//	})
+func (r *BaseRepository[T, K]) WithTx(tx *gorm.DB) *BaseRepository[T, K] {
+	clone := *r
+	clone.DB = tx
+	return &clone
+}
+
+// WithScopes — bản sao repository luôn áp thêm scopes vào query Paginate
+// VD: policy chỉ cho xem record cùng tenant
+//
+//	repo.WithScopes(func(db *gorm.DB) *gorm.DB { return db.Where("tenant_id = ?", tenantID) })
+func (r *BaseRepository[T, K]) WithScopes(scopes ...func(*gorm.DB) *gorm.DB) *BaseRepository[T, K] {
+	clone := *r
+	clone.scopes = append(append([]func(*gorm.DB) *gorm.DB{}, r.scopes...), scopes...)
+	return &clone
+}
+
 // ============================================================
 // LOCKING — Pessimistic locking cho concurrent operations
 // Khi 2+ users cùng mua sản phẩm cuối cùng trong kho:
@@ -746,10 +1015,12 @@ func (r *BaseRepository[T]) Transaction(fn func(tx *gorm.DB) error) error {
 //	    product.Stock -= quantity
 //	    return tx.Save(product).Error
 //	})
-func (r *BaseRepository[T]) FindByIdForUpdate(tx *gorm.DB, id uint) (*T, error) {
+func (r *BaseRepository[T, K]) FindByIdForUpdate(tx *gorm.DB, id K) (*T, error) {
 	var record T
 	// Clauses(clause.Locking{Strength: "UPDATE"}) → thêm FOR UPDATE vào query
-	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&record, id).Error
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where(r.primaryKey()+" = ?", id).
+		First(&record).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil // return nil for not found
@@ -761,7 +1032,7 @@ func (r *BaseRepository[T]) FindByIdForUpdate(tx *gorm.DB, id uint) (*T, error)
 
 // FindByFieldForUpdate — tìm và lock theo field bất kỳ
 // VD: lock inventory record theo product_id
-func (r *BaseRepository[T]) FindByFieldForUpdate(tx *gorm.DB, field string, value any) (*T, error) {
+func (r *BaseRepository[T, K]) FindByFieldForUpdate(tx *gorm.DB, field string, value any) (*T, error) {
 	if !validFieldName(field) {
 		return nil, fmt.Errorf("invalid field name: %s", field)
 	}
@@ -777,3 +1048,32 @@ func (r *BaseRepository[T]) FindByFieldForUpdate(tx *gorm.DB, field string, valu
 	}
 	return &record, nil
 }
+
+// FindConflictForUpdate — tìm và lock record trùng payload trên conflictColumns (dùng trước Upsert)
+// VD: FindConflictForUpdate(tx, &catalogue, []string{"slug"}) → WHERE slug = catalogue.Slug FOR UPDATE
+// nil, nil = chưa có → Upsert sẽ insert
+func (r *BaseRepository[T, K]) FindConflictForUpdate(tx *gorm.DB, payload *T, conflictColumns []string) (*T, error) {
+	if len(conflictColumns) == 0 {
+		return nil, fmt.Errorf("conflict columns required")
+	}
+	query := tx.Clauses(clause.Locking{Strength: "UPDATE"})
+	for _, column := range conflictColumns {
+		if !validFieldName(column) {
+			return nil, fmt.Errorf("invalid field name: %s", column)
+		}
+		value, err := r.fieldValue(payload, column)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where(column+" = ?", value)
+	}
+
+	var record T
+	if err := query.First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find conflict failed: %w", err)
+	}
+	return &record, nil
+}
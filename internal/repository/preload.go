@@ -0,0 +1,302 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+
+	"golang-base/global/common"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ============================================================
+// PRELOAD — Eager loading có điều kiện, chọn column, sort, limit
+//
+// Specs.Preloads nhận []common.RelationSpec thay vì chỉ tên relation:
+//
+//	{Name: "Posts", Filters: {"publish": 2}, Select: ["id", "title"], Sort: "created_at desc", Limit: 5}
+//	→ mỗi user chỉ load 5 bài viết đã publish mới nhất, chỉ lấy id + title
+//
+//	{Name: "Catalogue.Users.Profile"}
+//	→ preload lồng nhiều cấp
+//
+// Mọi thứ client gửi lên đều được đối chiếu với GORM schema:
+// → relation phải tồn tại, column phải thuộc model con
+// → repo có thể giới hạn thêm bằng AllowRelations(...)
+// → client KHÔNG thể kéo theo quan hệ tùy ý (VD: User.Sessions, User.ApiKeys)
+// ============================================================
+
+// AllowRelations — whitelist các relation path được phép preload
+// Path cha của 1 entry tự động được phép: "Catalogue.Users" → cho phép cả "Catalogue"
+// Không gọi = cho phép mọi relation có trong schema
+//
+// VD: repo.AllowRelations("Catalogue", "Catalogue.Permissions")
+func (r *BaseRepository[T, K]) AllowRelations(paths ...string) *BaseRepository[T, K] {
+	if r.allowedRelations == nil {
+		r.allowedRelations = make(map[string]struct{}, len(paths))
+	}
+	for _, path := range paths {
+		r.allowedRelations[path] = struct{}{}
+	}
+	return r
+}
+
+// relationAllowed — check path có nằm trong whitelist (hoặc là path cha của 1 entry)
+func (r *BaseRepository[T, K]) relationAllowed(path string) bool {
+	if len(r.allowedRelations) == 0 {
+		return true
+	}
+	for allowed := range r.allowedRelations {
+		if allowed == path || strings.HasPrefix(allowed, path+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// applyPreloads — validate + map từng RelationSpec sang GORM Preload
+// Lỗi validate → AddError vào query → Count/Find trả lỗi, KHÔNG âm thầm bỏ qua
+func (r *BaseRepository[T, K]) applyPreloads(query *gorm.DB, preloads []common.RelationSpec) *gorm.DB {
+	if len(preloads) == 0 {
+		return query
+	}
+
+	s, err := r.modelSchema()
+	if err != nil {
+		query.AddError(err)
+		return query
+	}
+
+	for _, spec := range preloads {
+		rel, path, err := resolveRelationPath(s, spec.Name)
+		if err != nil {
+			query.AddError(err)
+			continue
+		}
+		if !r.relationAllowed(path) {
+			query.AddError(fmt.Errorf("relation %q is not allowed to preload", path))
+			continue
+		}
+
+		condition, err := preloadCondition(rel, spec)
+		if err != nil {
+			query.AddError(err)
+			continue
+		}
+		if condition == nil {
+			query = query.Preload(path)
+			continue
+		}
+		query = query.Preload(path, condition)
+	}
+	return query
+}
+
+// resolveRelationPath — đi theo chuỗi "A.B.C" trên schema
+// Trả về relation cuối cùng + path đã chuẩn hóa theo tên Go ("catalogue" → "Catalogue")
+func resolveRelationPath(s *schema.Schema, name string) (*schema.Relationship, string, error) {
+	if name == "" {
+		return nil, "", fmt.Errorf("relation name is required")
+	}
+
+	var (
+		rel     *schema.Relationship
+		current = s
+		names   = make([]string, 0, 3)
+	)
+	for _, segment := range strings.Split(name, ".") {
+		rel = lookupRelation(current, segment)
+		if rel == nil {
+			return nil, "", fmt.Errorf("unknown relation %q on %s", segment, current.Name)
+		}
+		names = append(names, rel.Name)
+		current = rel.FieldSchema
+	}
+	return rel, strings.Join(names, "."), nil
+}
+
+// preloadCondition — dựng hàm điều kiện cho GORM Preload từ RelationSpec
+// nil = không có điều kiện gì → Preload thường
+func preloadCondition(rel *schema.Relationship, spec common.RelationSpec) (func(*gorm.DB) *gorm.DB, error) {
+	if len(spec.Filters) == 0 && len(spec.Select) == 0 && spec.Sort == "" && spec.Limit <= 0 {
+		return nil, nil
+	}
+
+	child := rel.FieldSchema
+
+	// Filters — WHERE trên bảng con, column phải thuộc model con
+	conditions := make([]string, 0, len(spec.Filters)+1)
+	args := make([]any, 0, len(spec.Filters))
+	for field, value := range spec.Filters {
+		f := child.LookUpField(field)
+		if f == nil || f.DBName == "" {
+			return nil, fmt.Errorf("unknown field %q on relation %s", field, rel.Name)
+		}
+		conditions = append(conditions, child.Table+"."+f.DBName+" = ?")
+		args = append(args, value)
+	}
+
+	// Select — luôn kèm primary key + khóa liên kết
+	// Thiếu khóa → GORM không map được con vào cha (hoặc vào cháu khi preload lồng)
+	var columns []string
+	if len(spec.Select) > 0 {
+		seen := make(map[string]bool, len(spec.Select))
+		add := func(f *schema.Field) {
+			if f != nil && f.DBName != "" && !seen[f.DBName] {
+				seen[f.DBName] = true
+				columns = append(columns, child.Table+"."+f.DBName)
+			}
+		}
+		for _, field := range spec.Select {
+			f := child.LookUpField(field)
+			if f == nil || f.DBName == "" {
+				return nil, fmt.Errorf("unknown field %q on relation %s", field, rel.Name)
+			}
+			add(f)
+		}
+		for _, f := range child.PrimaryFields {
+			add(f)
+		}
+		for _, f := range childKeyFields(rel) {
+			add(f)
+		}
+		// Khóa ngoại belongs-to của chính model con → cần cho preload cấp sâu hơn
+		for _, childRel := range child.Relationships.BelongsTo {
+			for _, ref := range childRel.References {
+				if !ref.OwnPrimaryKey && ref.PrimaryValue == "" {
+					add(ref.ForeignKey)
+				}
+			}
+		}
+	}
+
+	// Sort — "field asc, field2 desc", field phải thuộc model con
+	order, err := relationOrder(child, spec.Sort)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sort on relation %s: %w", rel.Name, err)
+	}
+
+	// Limit mỗi cha — chỉ có nghĩa với has-many
+	var limitClause string
+	if spec.Limit > 0 {
+		clause, err := perParentLimit(rel, conditions, order)
+		if err != nil {
+			return nil, err
+		}
+		limitClause = clause
+	}
+
+	return func(db *gorm.DB) *gorm.DB {
+		if len(columns) > 0 {
+			db = db.Select(columns)
+		}
+		for i, condition := range conditions {
+			db = db.Where(condition, args[i])
+		}
+		if limitClause != "" {
+			db = db.Where(limitClause, append(append([]any{}, args...), spec.Limit)...)
+		}
+		if order != "" {
+			db = db.Order(order)
+		}
+		return db
+	}, nil
+}
+
+// childKeyFields — các field ở phía model con dùng để nối với model cha
+func childKeyFields(rel *schema.Relationship) []*schema.Field {
+	fields := make([]*schema.Field, 0, len(rel.References))
+	for _, ref := range rel.References {
+		switch {
+		case rel.JoinTable != nil:
+			if !ref.OwnPrimaryKey {
+				fields = append(fields, ref.PrimaryKey)
+			}
+		case ref.OwnPrimaryKey || ref.PrimaryValue != "":
+			fields = append(fields, ref.ForeignKey)
+		default:
+			fields = append(fields, ref.PrimaryKey)
+		}
+	}
+	return fields
+}
+
+// relationOrder — validate + chuẩn hóa chuỗi sort cho bảng con
+func relationOrder(child *schema.Schema, sort string) (string, error) {
+	if strings.TrimSpace(sort) == "" {
+		return "", nil
+	}
+	parts := strings.Split(sort, ",")
+	orders := make([]string, 0, len(parts))
+	for _, part := range parts {
+		tokens := strings.Fields(part)
+		if len(tokens) == 0 || len(tokens) > 2 {
+			return "", fmt.Errorf("invalid sort expression %q", part)
+		}
+		f := child.LookUpField(tokens[0])
+		if f == nil || f.DBName == "" {
+			return "", fmt.Errorf("unknown field %q", tokens[0])
+		}
+		direction := "asc"
+		if len(tokens) == 2 {
+			direction = strings.ToLower(tokens[1])
+			if direction != "asc" && direction != "desc" {
+				return "", fmt.Errorf("invalid sort direction %q", tokens[1])
+			}
+		}
+		orders = append(orders, child.Table+"."+f.DBName+" "+direction)
+	}
+	return strings.Join(orders, ", "), nil
+}
+
+// perParentLimit — giới hạn số con cho MỖI record cha
+//
+// GORM Preload + Limit chỉ giới hạn TỔNG số con của cả trang, không phải mỗi cha
+// → Dùng window function đánh số con trong từng nhóm khóa ngoại:
+//
+//	posts.id IN (
+//	    SELECT id FROM (
+//	        SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY created_at desc) AS rn
+//	        FROM posts WHERE posts.publish = ?
+//	    ) AS ranked WHERE ranked.rn <= ?
+//	)
+//
+// Yêu cầu MySQL 8+ / PostgreSQL (hỗ trợ window function)
+// Filter được lặp lại bên trong để đánh số trên đúng tập con đã lọc
+func perParentLimit(rel *schema.Relationship, conditions []string, order string) (string, error) {
+	if rel.Type != schema.HasMany || rel.JoinTable != nil || rel.Polymorphic != nil {
+		return "", fmt.Errorf("limit per parent is only supported on has-many relation, got %s", rel.Name)
+	}
+
+	child := rel.FieldSchema
+	if child.PrioritizedPrimaryField == nil {
+		return "", fmt.Errorf("relation %s has no primary key", rel.Name)
+	}
+	pk := child.PrioritizedPrimaryField.DBName
+
+	partitions := make([]string, 0, len(rel.References))
+	for _, f := range childKeyFields(rel) {
+		partitions = append(partitions, child.Table+"."+f.DBName)
+	}
+	if order == "" {
+		order = child.Table + "." + pk + " asc"
+	}
+
+	inner := append([]string{}, conditions...)
+	for _, f := range child.Fields {
+		if f.FieldType == deletedAtType && f.DBName != "" {
+			inner = append(inner, child.Table+"."+f.DBName+" IS NULL")
+			break
+		}
+	}
+	where := ""
+	if len(inner) > 0 {
+		where = " WHERE " + strings.Join(inner, " AND ")
+	}
+
+	return fmt.Sprintf(
+		"%[1]s.%[2]s IN (SELECT ranked.%[2]s FROM (SELECT %[1]s.%[2]s, ROW_NUMBER() OVER (PARTITION BY %[3]s ORDER BY %[4]s) AS rn FROM %[1]s%[5]s) AS ranked WHERE ranked.rn <= ?)",
+		child.Table, pk, strings.Join(partitions, ", "), order, where,
+	), nil
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"golang-base/global/common"
+)
+
+// loadPosts — buildBaseQuery chỉ với preloads, trả về post theo thứ tự id
+func loadPosts(t *testing.T, repo *BaseRepository[testPost, uint], preloads ...common.RelationSpec) []testPost {
+	t.Helper()
+	var posts []testPost
+	if err := repo.buildBaseQuery(common.Specs{Preloads: preloads}).Order("id").Find(&posts).Error; err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	return posts
+}
+
+func bodies(comments []testComment) []string {
+	out := make([]string, len(comments))
+	for i, c := range comments {
+		out[i] = c.Body
+	}
+	return out
+}
+
+func TestPreloadConditions(t *testing.T) {
+	db := seedPosts(t)
+	// Post "SQL" (id 3) thêm 2 comment → limit phải tính theo TỪNG post, không phải cả trang
+	db.Create(&[]testComment{{PostID: 3, Body: "tốt", Votes: 3}, {PostID: 3, Body: "ổn", Votes: 2}})
+	repo := NewBaseRepository[testPost, uint](db)
+
+	t.Run("filter", func(t *testing.T) {
+		posts := loadPosts(t, repo, common.RelationSpec{Name: "Comments", Filters: map[string]any{"votes": 1}})
+		if got := bodies(posts[0].Comments); !slices.Equal(got, []string{"dở"}) || len(posts[2].Comments) != 0 {
+			t.Fatalf("unexpected filtered comments: %v / %v", got, bodies(posts[2].Comments))
+		}
+	})
+
+	t.Run("sort and limit per parent", func(t *testing.T) {
+		posts := loadPosts(t, repo, common.RelationSpec{Name: "Comments", Sort: "votes desc", Limit: 1})
+		want := [][]string{{"hay"}, {}, {"tốt"}} // comment đã xóa mềm của Rust không được đánh số
+		for i, p := range posts {
+			if got := bodies(p.Comments); !slices.Equal(got, want[i]) {
+				t.Fatalf("post %s: expected %v, got %v", p.Title, want[i], got)
+			}
+		}
+	})
+
+	t.Run("limit with filter", func(t *testing.T) {
+		posts := loadPosts(t, repo, common.RelationSpec{Name: "Comments", Filters: map[string]any{"votes": 2}, Limit: 1})
+		if got := bodies(posts[2].Comments); !slices.Equal(got, []string{"ổn"}) || len(posts[0].Comments) != 0 {
+			t.Fatalf("filter must apply before ranking, got %v / %v", bodies(posts[0].Comments), got)
+		}
+	})
+
+	t.Run("select keeps keys", func(t *testing.T) {
+		posts := loadPosts(t, repo, common.RelationSpec{Name: "Comments", Select: []string{"body"}, Sort: "votes desc"})
+		c := posts[0].Comments[0]
+		if c.Body != "hay" || c.ID == 0 || c.PostID != posts[0].ID || c.Votes != 0 {
+			t.Fatalf("expected only body + keys, got %+v", c)
+		}
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		posts := loadPosts(t, repo, common.RelationSpec{Name: "author.company"})
+		if posts[1].Author == nil || posts[1].Author.Company == nil || posts[1].Author.Company.Name != "Globex" {
+			t.Fatalf("nested preload missing: %+v", posts[1].Author)
+		}
+	})
+
+	t.Run("nested with select on parent", func(t *testing.T) {
+		// Select trên Author phải tự giữ company_id → cấp Company vẫn map được
+		posts := loadPosts(t, repo,
+			common.RelationSpec{Name: "Author", Select: []string{"name"}},
+			common.RelationSpec{Name: "Author.Company"},
+		)
+		if posts[0].Author == nil || posts[0].Author.Company == nil || posts[0].Author.Company.Name != "Acme" {
+			t.Fatalf("nested preload lost through select: %+v", posts[0].Author)
+		}
+	})
+}
+
+func TestPreloadValidation(t *testing.T) {
+	repo := NewBaseRepository[testPost, uint](seedPosts(t))
+
+	cases := []struct {
+		spec common.RelationSpec
+		want string
+	}{
+		{common.RelationSpec{Name: "Sessions"}, "unknown relation"},
+		{common.RelationSpec{Name: "Author.Sessions"}, "unknown relation"},
+		{common.RelationSpec{Name: "Comments", Select: []string{"password"}}, "unknown field"},
+		{common.RelationSpec{Name: "Comments", Filters: map[string]any{"1=1; --": 1}}, "unknown field"},
+		{common.RelationSpec{Name: "Comments", Sort: "votes sideways"}, "invalid sort direction"},
+		{common.RelationSpec{Name: "Comments", Sort: "secret desc"}, "unknown field"},
+		{common.RelationSpec{Name: "Author", Limit: 1}, "only supported on has-many"},
+		{common.RelationSpec{Name: "Tags", Limit: 1}, "only supported on has-many"},
+		{common.RelationSpec{Name: "Images", Limit: 1}, "only supported on has-many"},
+	}
+	for _, tc := range cases {
+		var posts []testPost
+		err := repo.buildBaseQuery(common.Specs{Preloads: []common.RelationSpec{tc.spec}}).Find(&posts).Error
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Fatalf("%+v: expected %q, got %v", tc.spec, tc.want, err)
+		}
+	}
+}
+
+func TestAllowRelations(t *testing.T) {
+	repo := NewBaseRepository[testPost, uint](seedPosts(t)).AllowRelations("Author.Company")
+
+	for _, name := range []string{"Author", "author.company"} {
+		var posts []testPost
+		if err := repo.buildBaseQuery(common.Specs{Relations: []string{name}}).Find(&posts).Error; err != nil {
+			t.Fatalf("%s must be allowed (parent of whitelisted path): %v", name, err)
+		}
+	}
+	var posts []testPost
+	err := repo.buildBaseQuery(common.Specs{Relations: []string{"Comments"}}).Find(&posts).Error
+	if err == nil || !strings.Contains(err.Error(), "not allowed") {
+		t.Fatalf("Comments: expected not allowed, got %v", err)
+	}
+}
+
+func TestPerParentLimitSQL(t *testing.T) {
+	db := newTestDB(t)
+	s, err := NewBaseRepository[testPost, uint](db).modelSchema()
+	if err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	rel := s.Relationships.Relations["Comments"]
+
+	got, err := perParentLimit(rel, []string{"test_comments.votes = ?"}, "test_comments.votes desc")
+	if err != nil {
+		t.Fatalf("perParentLimit: %v", err)
+	}
+	want := "test_comments.id IN (SELECT ranked.id FROM (SELECT test_comments.id, ROW_NUMBER() OVER " +
+		"(PARTITION BY test_comments.post_id ORDER BY test_comments.votes desc) AS rn FROM test_comments " +
+		"WHERE test_comments.votes = ? AND test_comments.deleted_at IS NULL) AS ranked WHERE ranked.rn <= ?)"
+	if got != want {
+		t.Fatalf("expected\n%s\ngot\n%s", want, got)
+	}
+}
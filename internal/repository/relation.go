@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ============================================================
+// RELATION FILTER (whereHas) — lọc record cha theo field của quan hệ
+//
+// Specs.Filters / InFilters / RangeFilters chấp nhận key dạng
+// "Relation.column" hoặc lồng nhiều cấp "Relation.Child.column":
+//
+//	{"Catalogue.role": "admin"}
+//	→ users có catalogue.role = 'admin'
+//
+//	{"Category.slug": "phones"}
+//	→ products có category.slug = 'phones'
+//
+// Tại sao dùng EXISTS thay vì JOIN?
+// → JOIN với quan hệ has-many nhân bản row cha → COUNT(*) sai, data trùng
+// → EXISTS chỉ kiểm tra "có ít nhất 1 row con thỏa điều kiện" → không trùng
+//
+// SQL tạo ra (belongs-to):
+//
+//	SELECT * FROM users WHERE EXISTS (
+//	    SELECT 1 FROM user_catalogues AS wh1
+//	    WHERE wh1.id = users.user_catalogue_id AND wh1.role = 'admin'
+//	)
+//
+// Tên bảng, khóa ngoại lấy từ GORM schema → client chỉ điều khiển được
+// tên relation + tên column, cả 2 đều phải tồn tại trong schema.
+// ============================================================
+
+// deletedAtType — dùng để nhận biết model con có soft delete hay không
+var deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+
+// lookupRelation — tìm relation theo tên Go (Catalogue), chấp nhận cả chữ thường (catalogue)
+func lookupRelation(s *schema.Schema, name string) *schema.Relationship {
+	if rel, ok := s.Relationships.Relations[name]; ok {
+		return rel
+	}
+	for relName, rel := range s.Relationships.Relations {
+		if strings.EqualFold(relName, name) {
+			return rel
+		}
+	}
+	return nil
+}
+
+// whereField — apply điều kiện cho 1 field, tự nhận biết field thường hay relation path
+//
+// expr: hàm dựng điều kiện từ tên column đã resolve
+// VD: func(col string) string { return col + " IN ?" }
+//
+// Field có dấu chấm nhưng segment đầu KHÔNG phải relation (VD: "users.name")
+// → giữ nguyên hành vi cũ, coi như column có prefix tên bảng
+func (r *BaseRepository[T, K]) whereField(query *gorm.DB, field string, expr func(column string) string, args ...any) *gorm.DB {
+	segments := strings.Split(field, ".")
+	if len(segments) < 2 {
+		return query.Where(expr(field), args...)
+	}
+
+	s, err := r.modelSchema()
+	if err != nil {
+		query.AddError(err)
+		return query
+	}
+	if lookupRelation(s, segments[0]) == nil {
+		return query.Where(expr(field), args...)
+	}
+
+	sql, vars, err := existsClause(s, s.Table, segments[:len(segments)-1], segments[len(segments)-1], expr, 1)
+	if err != nil {
+		query.AddError(err)
+		return query
+	}
+	return query.Where(sql, append(vars, args...)...)
+}
+
+// existsClause — dựng EXISTS subquery đệ quy theo chuỗi relation
+//
+// parentAlias: alias của bảng cha ở cấp ngoài (cấp gốc = tên bảng của T)
+// depth: dùng đặt alias wh1, wh2... → tránh trùng khi quan hệ tự tham chiếu (self-join)
+//
+// vars trả về là các giá trị cố định lấy từ schema (VD: polymorphic type),
+// nằm TRƯỚC placeholder của expr trong câu SQL
+func existsClause(parent *schema.Schema, parentAlias string, relations []string, column string, expr func(string) string, depth int) (string, []any, error) {
+	rel := lookupRelation(parent, relations[0])
+	if rel == nil {
+		return "", nil, fmt.Errorf("unknown relation %q on %s", relations[0], parent.Name)
+	}
+
+	child := rel.FieldSchema
+	alias := fmt.Sprintf("wh%d", depth)
+
+	from := child.Table + " AS " + alias
+	conditions := make([]string, 0, 4)
+	vars := make([]any, 0, 1)
+
+	if rel.JoinTable != nil {
+		// Many2Many: đi qua bảng trung gian
+		// FROM child AS wh1 INNER JOIN pivot AS wh1_j ON wh1_j.child_id = wh1.id
+		// WHERE wh1_j.parent_id = parent.id
+		joinAlias := alias + "_j"
+		on := make([]string, 0, 1)
+		for _, ref := range rel.References {
+			if ref.OwnPrimaryKey {
+				conditions = append(conditions, joinAlias+"."+ref.ForeignKey.DBName+" = "+parentAlias+"."+ref.PrimaryKey.DBName)
+			} else {
+				on = append(on, joinAlias+"."+ref.ForeignKey.DBName+" = "+alias+"."+ref.PrimaryKey.DBName)
+			}
+		}
+		from += " INNER JOIN " + rel.JoinTable.Table + " AS " + joinAlias + " ON " + strings.Join(on, " AND ")
+	} else {
+		for _, ref := range rel.References {
+			switch {
+			case ref.PrimaryValue != "":
+				// Polymorphic: wh1.owner_type = 'users'
+				conditions = append(conditions, alias+"."+ref.ForeignKey.DBName+" = ?")
+				vars = append(vars, ref.PrimaryValue)
+			case ref.OwnPrimaryKey:
+				// HasOne / HasMany: khóa ngoại nằm ở bảng con
+				conditions = append(conditions, alias+"."+ref.ForeignKey.DBName+" = "+parentAlias+"."+ref.PrimaryKey.DBName)
+			default:
+				// BelongsTo: khóa ngoại nằm ở bảng cha
+				conditions = append(conditions, alias+"."+ref.PrimaryKey.DBName+" = "+parentAlias+"."+ref.ForeignKey.DBName)
+			}
+		}
+	}
+
+	// Model con có soft delete → bỏ qua row đã xóa, giống hành vi mặc định của GORM
+	for _, f := range child.Fields {
+		if f.FieldType == deletedAtType && f.DBName != "" {
+			conditions = append(conditions, alias+"."+f.DBName+" IS NULL")
+			break
+		}
+	}
+
+	if len(relations) > 1 {
+		inner, innerVars, err := existsClause(child, alias, relations[1:], column, expr, depth+1)
+		if err != nil {
+			return "", nil, err
+		}
+		conditions = append(conditions, inner)
+		vars = append(vars, innerVars...)
+	} else {
+		f := child.LookUpField(column)
+		if f == nil || f.DBName == "" {
+			return "", nil, fmt.Errorf("unknown field %q on relation %s", column, rel.Name)
+		}
+		conditions = append(conditions, expr(alias+"."+f.DBName))
+	}
+
+	return fmt.Sprintf("EXISTS (SELECT 1 FROM %s WHERE %s)", from, strings.Join(conditions, " AND ")), vars, nil
+}
@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang-base/global/common"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+// ============================================================
+// Model mẫu cho test của repository — đủ các loại quan hệ:
+//
+//	testPost ──belongs-to──► testAuthor ──belongs-to──► testCompany
+//	testPost ──has-many────► testComment (soft delete)
+//	testPost ──many2many───► testTag (test_post_tags)
+//	testPost / testAuthor ──polymorphic──► testImage (owner_type, owner_id)
+// ============================================================
+
+type testCompany struct {
+	ID   uint
+	Name string
+}
+
+type testAuthor struct {
+	ID        uint
+	Name      string
+	CompanyID uint
+	Company   *testCompany
+	Images    []testImage `gorm:"polymorphic:Owner"`
+}
+
+type testPost struct {
+	ID        uint
+	AuthorID  uint
+	Title     string
+	Publish   int
+	CreatedAt time.Time
+	Author    *testAuthor
+	Comments  []testComment `gorm:"foreignKey:PostID"`
+	Tags      []testTag     `gorm:"many2many:test_post_tags"`
+	Images    []testImage   `gorm:"polymorphic:Owner"`
+}
+
+type testComment struct {
+	ID        uint
+	PostID    uint
+	Body      string
+	Votes     int
+	DeletedAt gorm.DeletedAt
+}
+
+type testTag struct {
+	ID   uint
+	Name string
+}
+
+type testImage struct {
+	ID        uint
+	OwnerID   uint
+	OwnerType string
+	URL       string
+}
+
+// newTestDB — sqlite in-memory riêng cho từng test, đã migrate model mẫu
+func newTestDB(t *testing.T, models ...any) *gorm.DB {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", strings.ReplaceAll(t.Name(), "/", "_"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Discard, TranslateError: true})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+// seedPosts — An (Acme): "Go", "SQL"; Bình (Globex): "Rust"
+func seedPosts(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := newTestDB(t, &testCompany{}, &testAuthor{}, &testPost{}, &testComment{}, &testTag{}, &testImage{})
+
+	an := testAuthor{Name: "An", Company: &testCompany{Name: "Acme"}}
+	binh := testAuthor{Name: "Bình", Company: &testCompany{Name: "Globex"}}
+	goTag, rustTag, dbTag := testTag{Name: "go"}, testTag{Name: "rust"}, testTag{Name: "db"}
+	posts := []testPost{
+		{Title: "Go", Publish: 2, Author: &an, Tags: []testTag{goTag}, Comments: []testComment{{Body: "hay", Votes: 5}, {Body: "dở", Votes: 1}}},
+		{Title: "Rust", Publish: 1, Author: &binh, Tags: []testTag{rustTag}, Comments: []testComment{{Body: "đã xóa", Votes: 9}}, Images: []testImage{{URL: "cover.png"}}},
+		{Title: "SQL", Publish: 2, Author: &an, Tags: []testTag{dbTag}},
+	}
+	for i := range posts {
+		if i == 2 {
+			posts[i].Tags = append(posts[i].Tags, posts[0].Tags[0])
+		}
+		if err := db.Create(&posts[i]).Error; err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+	}
+	// Comment đã xóa mềm, ảnh cùng owner_id=1 nhưng của author (không phải post 1)
+	if err := db.Delete(&testComment{}, "body = ?", "đã xóa").Error; err != nil {
+		t.Fatalf("soft delete: %v", err)
+	}
+	if err := db.Create(&testImage{OwnerID: 1, OwnerType: "test_authors", URL: "avatar.png"}).Error; err != nil {
+		t.Fatalf("seed image: %v", err)
+	}
+	return db
+}
+
+// titles — chạy buildBaseQuery với specs, trả về title theo thứ tự id
+func titles(t *testing.T, repo *BaseRepository[testPost, uint], specs common.Specs) []string {
+	t.Helper()
+	var posts []testPost
+	if err := repo.buildBaseQuery(specs).Order("id").Find(&posts).Error; err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	out := make([]string, len(posts))
+	for i, p := range posts {
+		out[i] = p.Title
+	}
+	return out
+}
+
+func TestRelationFilters(t *testing.T) {
+	repo := NewBaseRepository[testPost, uint](seedPosts(t))
+
+	cases := []struct {
+		name  string
+		specs common.Specs
+		want  []string
+	}{
+		{"belongs-to", common.Specs{Filters: map[string]any{"Author.name": "An"}}, []string{"Go", "SQL"}},
+		{"lower-case relation", common.Specs{Filters: map[string]any{"author.name": "Bình"}}, []string{"Rust"}},
+		{"nested", common.Specs{Filters: map[string]any{"Author.Company.name": "Globex"}}, []string{"Rust"}},
+		{"has-many", common.Specs{Filters: map[string]any{"Comments.body": "hay"}}, []string{"Go"}},
+		{"has-many skips soft-deleted child", common.Specs{Filters: map[string]any{"Comments.body": "đã xóa"}}, []string{}},
+		{"many2many in", common.Specs{InFilters: map[string][]any{"Tags.name": {"db", "rust"}}}, []string{"Rust", "SQL"}},
+		{"many2many shared tag", common.Specs{Filters: map[string]any{"Tags.name": "go"}}, []string{"Go", "SQL"}},
+		// Ảnh avatar.png có owner_id = 1 nhưng owner_type = test_authors → post 1 không khớp
+		{"polymorphic", common.Specs{Filters: map[string]any{"Images.url": "avatar.png"}}, []string{}},
+		{"polymorphic match", common.Specs{Filters: map[string]any{"Images.url": "cover.png"}}, []string{"Rust"}},
+		// Min + Max phải thỏa trên CÙNG 1 comment: Go có 5 và 1, không có comment nào trong [2, 4]
+		{"range on one child row", common.Specs{RangeFilters: map[string]common.RangeFilter{"Comments.votes": {Min: 2, Max: 4}}}, []string{}},
+		{"range match", common.Specs{RangeFilters: map[string]common.RangeFilter{"Comments.votes": {Min: 4, Max: 6}}}, []string{"Go"}},
+		{"combined with own column", common.Specs{Filters: map[string]any{"Author.name": "An", "publish": 2}}, []string{"Go", "SQL"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := titles(t, repo, tc.specs); !slices.Equal(got, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestRelationFilterRejectsUnknownField(t *testing.T) {
+	repo := NewBaseRepository[testPost, uint](seedPosts(t))
+
+	for _, field := range []string{"Author.password", "Author.Company.secret", "Comments.Author.name"} {
+		var posts []testPost
+		err := repo.buildBaseQuery(common.Specs{Filters: map[string]any{field: "x"}}).Find(&posts).Error
+		if err == nil || !strings.Contains(err.Error(), "unknown") {
+			t.Fatalf("%s: expected unknown field/relation error, got %v", field, err)
+		}
+	}
+}
+
+func TestExistsClauseSQL(t *testing.T) {
+	s, err := schema.Parse(&testPost{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("parse schema: %v", err)
+	}
+	eq := func(col string) string { return col + " = ?" }
+
+	cases := []struct {
+		relations []string
+		column    string
+		sql       string
+		vars      []any
+	}{
+		{
+			[]string{"Author"}, "name",
+			"EXISTS (SELECT 1 FROM test_authors AS wh1 WHERE wh1.id = test_posts.author_id AND wh1.name = ?)", nil,
+		},
+		{
+			[]string{"Author", "Company"}, "name",
+			"EXISTS (SELECT 1 FROM test_authors AS wh1 WHERE wh1.id = test_posts.author_id AND " +
+				"EXISTS (SELECT 1 FROM test_companies AS wh2 WHERE wh2.id = wh1.company_id AND wh2.name = ?))", nil,
+		},
+		{
+			[]string{"Comments"}, "body",
+			"EXISTS (SELECT 1 FROM test_comments AS wh1 WHERE wh1.post_id = test_posts.id AND wh1.deleted_at IS NULL AND wh1.body = ?)", nil,
+		},
+		{
+			[]string{"Tags"}, "name",
+			"EXISTS (SELECT 1 FROM test_tags AS wh1 INNER JOIN test_post_tags AS wh1_j ON wh1_j.test_tag_id = wh1.id " +
+				"WHERE wh1_j.test_post_id = test_posts.id AND wh1.name = ?)", nil,
+		},
+	}
+	for _, tc := range cases {
+		sql, vars, err := existsClause(s, s.Table, tc.relations, tc.column, eq, 1)
+		if err != nil {
+			t.Fatalf("%v: %v", tc.relations, err)
+		}
+		if sql != tc.sql || len(vars) != len(tc.vars) {
+			t.Fatalf("%v:\nexpected %s %v\ngot      %s %v", tc.relations, tc.sql, tc.vars, sql, vars)
+		}
+	}
+
+	// Polymorphic: owner_type cố định lấy từ schema, nằm TRƯỚC placeholder của expr
+	sql, vars, err := existsClause(s, s.Table, []string{"Images"}, "url", eq, 1)
+	if err != nil {
+		t.Fatalf("polymorphic: %v", err)
+	}
+	if !strings.Contains(sql, "wh1.owner_type = ?") || !strings.Contains(sql, "wh1.owner_id = test_posts.id") ||
+		strings.Index(sql, "owner_type") > strings.Index(sql, "wh1.url = ?") {
+		t.Fatalf("unexpected polymorphic clause: %s", sql)
+	}
+	if len(vars) != 1 || vars[0] != "test_posts" {
+		t.Fatalf("expected owner_type var test_posts, got %v", vars)
+	}
+}
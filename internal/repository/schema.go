@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ============================================================
+// SCHEMA — helper đọc metadata model từ GORM schema
+// Tránh hard-code tên column ("id") → model dùng key khác vẫn chạy đúng
+// ============================================================
+
+// modelSchema — parse GORM schema của T (GORM tự cache theo type)
+func (r *BaseRepository[T, K]) modelSchema() (*schema.Schema, error) {
+	stmt := &gorm.Statement{DB: r.DB}
+	if err := stmt.Parse(new(T)); err != nil {
+		return nil, fmt.Errorf("parse schema failed: %w", err)
+	}
+	return stmt.Schema, nil
+}
+
+// primaryKey — tên column primary key của T, mặc định "id" nếu không parse được
+func (r *BaseRepository[T, K]) primaryKey() string {
+	s, err := r.modelSchema()
+	if err != nil || s.PrioritizedPrimaryField == nil {
+		return "id"
+	}
+	return s.PrioritizedPrimaryField.DBName
+}
+
+// fieldValue — đọc giá trị 1 field của record theo tên column hoặc tên Go
+// field rỗng → đọc primary key
+// VD: fieldValue(&user, "created_at") → time.Time
+func (r *BaseRepository[T, K]) fieldValue(record *T, field string) (any, error) {
+	s, err := r.modelSchema()
+	if err != nil {
+		return nil, err
+	}
+
+	f := s.PrioritizedPrimaryField
+	if field != "" {
+		f = s.LookUpField(field)
+	}
+	if f == nil {
+		return nil, fmt.Errorf("unknown field %q on %s", field, s.Name)
+	}
+
+	value, _ := f.ValueOf(context.Background(), reflect.ValueOf(record).Elem())
+	return value, nil
+}
+
+// IDOf — lấy primary key của record (VD: sau khi Create để biết ID vừa sinh)
+func (r *BaseRepository[T, K]) IDOf(record *T) (K, error) {
+	var id K
+	value, err := r.fieldValue(record, "")
+	if err != nil {
+		return id, err
+	}
+	id, ok := value.(K)
+	if !ok {
+		return id, fmt.Errorf("primary key type %T does not match %T", value, id)
+	}
+	return id, nil
+}
+
+// versionField — field Version của T (ETag, xem base/etag.go), nil nếu model không có
+func (r *BaseRepository[T, K]) versionField() *schema.Field {
+	s, err := r.modelSchema()
+	if err != nil {
+		return nil
+	}
+	return s.LookUpField("Version")
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"golang-base/global/common"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// testDevice — key UUIDv7 (sắp xếp được theo thời gian tạo), có soft delete
+type testDevice struct {
+	ID        uuid.UUID `gorm:"type:char(36);primaryKey"`
+	Name      string
+	DeletedAt gorm.DeletedAt
+}
+
+// testCoupon — key string, column primary key KHÔNG phải "id"
+type testCoupon struct {
+	Code  string `gorm:"primaryKey"`
+	Label string
+}
+
+func TestUUIDKeyCRUD(t *testing.T) {
+	repo := NewBaseRepository[testDevice, uuid.UUID](newTestDB(t, &testDevice{}))
+
+	devices := make([]testDevice, 3)
+	for i := range devices {
+		devices[i] = testDevice{ID: uuid.Must(uuid.NewV7()), Name: "device"}
+		if err := repo.Create(&devices[i]); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+	first := devices[0].ID
+
+	if id, err := repo.IDOf(&devices[0]); err != nil || id != first {
+		t.Fatalf("IDOf: got %v, %v", id, err)
+	}
+	if err := repo.Update(first, &testDevice{Name: "phone"}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	found, err := repo.FindById(first, nil)
+	if err != nil || found == nil || found.ID != first || found.Name != "phone" {
+		t.Fatalf("find by id: got %+v, %v", found, err)
+	}
+	if err := repo.Update(uuid.Must(uuid.NewV7()), &testDevice{Name: "ghost"}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("update missing: expected ErrNotFound, got %v", err)
+	}
+
+	byIDs, err := repo.FindByIds([]uuid.UUID{devices[1].ID, devices[2].ID})
+	if err != nil || len(byIDs) != 2 {
+		t.Fatalf("find by ids: got %d, %v", len(byIDs), err)
+	}
+
+	if err := repo.Delete(first); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if found, _ := repo.FindById(first, nil); found != nil {
+		t.Fatal("soft-deleted record must not be found")
+	}
+	if err := repo.RestoreById(first); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if found, _ := repo.FindById(first, nil); found == nil {
+		t.Fatal("restored record must be found")
+	}
+}
+
+func TestUUIDKeysetPaginate(t *testing.T) {
+	repo := NewBaseRepository[testDevice, uuid.UUID](newTestDB(t, &testDevice{}))
+	ids := make([]uuid.UUID, 5)
+	for i := range ids {
+		ids[i] = uuid.Must(uuid.NewV7())
+		if err := repo.Create(&testDevice{ID: ids[i], Name: "d"}); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+
+	// Trang đầu: mới nhất trước → ids[4], ids[3]; cursor = id cuối trang, đúng kiểu uuid.UUID
+	specs := common.Specs{UseKeyset: true, CursorField: "id", Limit: 2}
+	page, err := repo.Paginate(specs)
+	if err != nil {
+		t.Fatalf("page 1: %v", err)
+	}
+	cursor, ok := page.NextCursor.(uuid.UUID)
+	if !ok || !page.HasMore || len(page.Data) != 2 || page.Data[0].ID != ids[4] || cursor != ids[3] {
+		t.Fatalf("page 1: unexpected %+v (cursor %T)", page, page.NextCursor)
+	}
+
+	specs.CursorValue = cursor
+	page, err = repo.Paginate(specs)
+	if err != nil || len(page.Data) != 2 || page.Data[0].ID != ids[2] || page.Data[1].ID != ids[1] {
+		t.Fatalf("page 2: unexpected %+v, %v", page, err)
+	}
+}
+
+func TestStringKey(t *testing.T) {
+	repo := NewBaseRepository[testCoupon, string](newTestDB(t, &testCoupon{}))
+	if got := repo.primaryKey(); got != "code" {
+		t.Fatalf("primary key: expected code, got %s", got)
+	}
+
+	if err := repo.Create(&testCoupon{Code: "SALE-50", Label: "Giảm 50%"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := repo.UpdateFields("SALE-50", map[string]any{"label": "Giảm nửa giá"}); err != nil {
+		t.Fatalf("update fields: %v", err)
+	}
+	found, err := repo.FindById("SALE-50", nil)
+	if err != nil || found == nil || found.Label != "Giảm nửa giá" {
+		t.Fatalf("find: got %+v, %v", found, err)
+	}
+
+	// Key string luôn là tham số, không bao giờ bị GORM hiểu thành câu SQL thô
+	if found, err := repo.FindById("' OR '1'='1", nil); err != nil || found != nil {
+		t.Fatalf("injection-like key: expected nil, got %+v, %v", found, err)
+	}
+	if err := repo.Delete("1 = 1"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("delete raw-sql-like key: expected ErrNotFound, got %v", err)
+	}
+	if exists, _ := repo.ExistsById("SALE-50"); !exists {
+		t.Fatal("record must survive the raw-sql-like delete")
+	}
+}
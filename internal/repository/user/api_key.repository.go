@@ -0,0 +1,44 @@
+package user
+
+import (
+	"fmt"
+	"time"
+
+	"golang-base/internal/model"
+	"golang-base/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// APIKeyRepository — API key
+type APIKeyRepository struct {
+	*repository.BaseRepository[model.APIKey, uint64]
+}
+
+func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{
+		BaseRepository: repository.NewBaseRepository[model.APIKey, uint64](db),
+	}
+}
+
+// WithTx — bản sao chạy trên transaction
+func (r *APIKeyRepository) WithTx(tx *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{BaseRepository: r.BaseRepository.WithTx(tx)}
+}
+
+// FindByPrefix — tra key theo prefix, không tìm thấy → nil, nil
+func (r *APIKeyRepository) FindByPrefix(prefix string) (*model.APIKey, error) {
+	return r.FindByField("prefix", prefix, nil)
+}
+
+// TouchLastUsed — cập nhật last_used_at, bỏ qua nếu vừa cập nhật trong khoảng every
+// → key gọi liên tục không ghi DB mỗi request
+func (r *APIKeyRepository) TouchLastUsed(id uint64, now time.Time, every time.Duration) error {
+	err := r.DB.Model(&model.APIKey{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", id, now.Add(-every)).
+		UpdateColumn("last_used_at", now).Error
+	if err != nil {
+		return fmt.Errorf("touch api key failed: %w", err)
+	}
+	return nil
+}
@@ -9,14 +9,14 @@ import (
 
 // CatalogueRepository — struct quản lý thao tác DB với UserCatalogue
 type CatalogueRepository struct {
-	*repository.BaseRepository[model.UserCatalogue]
+	*repository.BaseRepository[model.UserCatalogue, uint]
 }
 
 // NewCatalogueRepository — factory function, map DB connection vào base repo
 // Trả về pointer của struct, bên trong đã tự động có sẵn toàn bộ hàm Base
 func NewCatalogueRepository(db *gorm.DB) *CatalogueRepository {
 	return &CatalogueRepository{
-		BaseRepository: repository.NewBaseRepository[model.UserCatalogue](db),
+		BaseRepository: repository.NewBaseRepository[model.UserCatalogue, uint](db),
 	}
 }
 
@@ -0,0 +1,62 @@
+package user
+
+import (
+	"fmt"
+	"time"
+
+	"golang-base/internal/model"
+	"golang-base/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// PasswordResetRepository — token đặt lại mật khẩu
+type PasswordResetRepository struct {
+	*repository.BaseRepository[model.PasswordResetToken, uint64]
+}
+
+func NewPasswordResetRepository(db *gorm.DB) *PasswordResetRepository {
+	return &PasswordResetRepository{
+		BaseRepository: repository.NewBaseRepository[model.PasswordResetToken, uint64](db),
+	}
+}
+
+// WithTx — bản sao chạy trên transaction
+func (r *PasswordResetRepository) WithTx(tx *gorm.DB) *PasswordResetRepository {
+	return &PasswordResetRepository{BaseRepository: r.BaseRepository.WithTx(tx)}
+}
+
+// Consume — đánh dấu token đã dùng, trả về token nếu hợp lệ
+//
+// UPDATE ... WHERE used_at IS NULL AND expires_at > now → atomic:
+// 2 request dùng cùng 1 token đồng thời → chỉ 1 request thắng (RowsAffected = 1)
+// Trả về nil, nil nếu token sai / hết hạn / đã dùng
+func (r *PasswordResetRepository) Consume(tokenHash string, now time.Time) (*model.PasswordResetToken, error) {
+	result := r.DB.Model(&model.PasswordResetToken{}).
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return nil, fmt.Errorf("consume reset token failed: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	var token model.PasswordResetToken
+	if err := r.DB.Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		return nil, fmt.Errorf("load reset token failed: %w", err)
+	}
+	return &token, nil
+}
+
+// InvalidateUser — vô hiệu mọi token còn hiệu lực của user
+// Gọi khi tạo token mới (chỉ token mới nhất dùng được) và khi đổi mật khẩu thành công
+func (r *PasswordResetRepository) InvalidateUser(userID uint, now time.Time) error {
+	err := r.DB.Model(&model.PasswordResetToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", now).Error
+	if err != nil {
+		return fmt.Errorf("invalidate reset tokens failed: %w", err)
+	}
+	return nil
+}
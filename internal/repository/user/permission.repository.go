@@ -0,0 +1,72 @@
+package user
+
+import (
+	"fmt"
+
+	"golang-base/internal/model"
+	"golang-base/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// PermissionRepository — permission + bảng pivot user_catalogue_permissions
+type PermissionRepository struct {
+	*repository.BaseRepository[model.Permission, uint]
+}
+
+func NewPermissionRepository(db *gorm.DB) *PermissionRepository {
+	return &PermissionRepository{
+		BaseRepository: repository.NewBaseRepository[model.Permission, uint](db),
+	}
+}
+
+// WithTx — bản sao chạy trên transaction
+func (r *PermissionRepository) WithTx(tx *gorm.DB) *PermissionRepository {
+	return &PermissionRepository{BaseRepository: r.BaseRepository.WithTx(tx)}
+}
+
+// NamesByCatalogue — tên các permission gán cho catalogue, sort theo tên
+func (r *PermissionRepository) NamesByCatalogue(catalogueID uint) ([]string, error) {
+	var names []string
+	err := r.DB.Model(&model.Permission{}).
+		Joins("JOIN user_catalogue_permissions ucp ON ucp.permission_id = permissions.id").
+		Where("ucp.user_catalogue_id = ?", catalogueID).
+		Order("permissions.name").
+		Pluck("permissions.name", &names).Error
+	if err != nil {
+		return nil, fmt.Errorf("load catalogue permissions failed: %w", err)
+	}
+	return names, nil
+}
+
+// FindByNames — permission theo danh sách tên (tên không tồn tại bị bỏ qua)
+func (r *PermissionRepository) FindByNames(names []string) ([]model.Permission, error) {
+	var permissions []model.Permission
+	if len(names) == 0 {
+		return permissions, nil
+	}
+	if err := r.DB.Where("name IN ?", names).Order("name").Find(&permissions).Error; err != nil {
+		return nil, fmt.Errorf("find permissions failed: %w", err)
+	}
+	return permissions, nil
+}
+
+// SyncCatalogue — thay toàn bộ permission của catalogue bằng danh sách mới
+// Nên gọi trong transaction (xóa + insert pivot)
+func (r *PermissionRepository) SyncCatalogue(catalogueID uint, permissionIDs []uint) error {
+	if err := r.DB.Exec("DELETE FROM user_catalogue_permissions WHERE user_catalogue_id = ?", catalogueID).Error; err != nil {
+		return fmt.Errorf("clear catalogue permissions failed: %w", err)
+	}
+	if len(permissionIDs) == 0 {
+		return nil
+	}
+
+	rows := make([]map[string]any, len(permissionIDs))
+	for i, id := range permissionIDs {
+		rows[i] = map[string]any{"user_catalogue_id": catalogueID, "permission_id": id}
+	}
+	if err := r.DB.Table("user_catalogue_permissions").Create(rows).Error; err != nil {
+		return fmt.Errorf("attach catalogue permissions failed: %w", err)
+	}
+	return nil
+}
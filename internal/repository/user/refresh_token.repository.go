@@ -0,0 +1,67 @@
+package user
+
+import (
+	"fmt"
+	"time"
+
+	"golang-base/internal/model"
+	"golang-base/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// RefreshTokenRepository — refresh token xoay vòng theo family
+type RefreshTokenRepository struct {
+	*repository.BaseRepository[model.RefreshToken, uint64]
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		BaseRepository: repository.NewBaseRepository[model.RefreshToken, uint64](db),
+	}
+}
+
+// WithTx — bản sao chạy trên transaction
+func (r *RefreshTokenRepository) WithTx(tx *gorm.DB) *RefreshTokenRepository {
+	return &RefreshTokenRepository{BaseRepository: r.BaseRepository.WithTx(tx)}
+}
+
+// FindByHash — tìm token theo hash (kể cả đã dùng / thu hồi, để phát hiện reuse)
+// Không tìm thấy → nil, nil
+func (r *RefreshTokenRepository) FindByHash(tokenHash string) (*model.RefreshToken, error) {
+	return r.FindByField("token_hash", tokenHash, nil)
+}
+
+// MarkUsed — đánh dấu token đã đổi, atomic giống PasswordResetRepository.Consume
+// false → token đã bị dùng / thu hồi bởi request khác
+func (r *RefreshTokenRepository) MarkUsed(id uint64, now time.Time) (bool, error) {
+	result := r.DB.Model(&model.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		Update("used_at", now)
+	if result.Error != nil {
+		return false, fmt.Errorf("mark refresh token used failed: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// RevokeFamily — thu hồi mọi token còn hiệu lực của family
+func (r *RefreshTokenRepository) RevokeFamily(familyID string, now time.Time) error {
+	err := r.DB.Model(&model.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", now).Error
+	if err != nil {
+		return fmt.Errorf("revoke refresh token family failed: %w", err)
+	}
+	return nil
+}
+
+// RevokeUser — thu hồi mọi token của user (đổi / đặt lại mật khẩu)
+func (r *RefreshTokenRepository) RevokeUser(userID uint, now time.Time) error {
+	err := r.DB.Model(&model.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", now).Error
+	if err != nil {
+		return fmt.Errorf("revoke refresh tokens failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package user
+
+import (
+	"fmt"
+	"time"
+
+	"golang-base/internal/model"
+	"golang-base/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// SessionRepository — session đăng nhập
+type SessionRepository struct {
+	*repository.BaseRepository[model.Session, string]
+}
+
+func NewSessionRepository(db *gorm.DB) *SessionRepository {
+	return &SessionRepository{
+		BaseRepository: repository.NewBaseRepository[model.Session, string](db),
+	}
+}
+
+// WithTx — bản sao chạy trên transaction
+func (r *SessionRepository) WithTx(tx *gorm.DB) *SessionRepository {
+	return &SessionRepository{BaseRepository: r.BaseRepository.WithTx(tx)}
+}
+
+// ActiveByUser — session chưa thu hồi, chưa hết hạn, mới dùng gần nhất trước
+func (r *SessionRepository) ActiveByUser(userID uint, now time.Time) ([]model.Session, error) {
+	var sessions []model.Session
+	err := r.DB.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, fmt.Errorf("list sessions failed: %w", err)
+	}
+	return sessions, nil
+}
+
+// Touch — cập nhật lần dùng cuối khi refresh token
+func (r *SessionRepository) Touch(id string, fields map[string]any) error {
+	if err := r.DB.Model(&model.Session{}).Where("id = ?", id).Updates(fields).Error; err != nil {
+		return fmt.Errorf("touch session failed: %w", err)
+	}
+	return nil
+}
+
+// Revoke — thu hồi các session còn hiệu lực theo điều kiện, trả về ID bị thu hồi
+// VD: Revoke(now, "id = ?", sid) / Revoke(now, "user_id = ?", userID)
+func (r *SessionRepository) Revoke(now time.Time, query string, args ...any) ([]string, error) {
+	var ids []string
+	if err := r.DB.Model(&model.Session{}).Where(query, args...).Where("revoked_at IS NULL").Pluck("id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("find sessions failed: %w", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	if err := r.DB.Model(&model.Session{}).Where("id IN ?", ids).Update("revoked_at", now).Error; err != nil {
+		return nil, fmt.Errorf("revoke sessions failed: %w", err)
+	}
+	if err := r.DB.Model(&model.RefreshToken{}).Where("family_id IN ? AND revoked_at IS NULL", ids).Update("revoked_at", now).Error; err != nil {
+		return nil, fmt.Errorf("revoke session refresh tokens failed: %w", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,176 @@
+package user
+
+import (
+	"fmt"
+	"time"
+
+	"golang-base/internal/model"
+	"golang-base/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// ============================================================
+// TOTP SECRET
+// ============================================================
+
+// TwoFactorRepository — TOTP của user (key = user_id)
+type TwoFactorRepository struct {
+	*repository.BaseRepository[model.TwoFactor, uint]
+}
+
+func NewTwoFactorRepository(db *gorm.DB) *TwoFactorRepository {
+	return &TwoFactorRepository{
+		BaseRepository: repository.NewBaseRepository[model.TwoFactor, uint](db),
+	}
+}
+
+// WithTx — bản sao chạy trên transaction
+func (r *TwoFactorRepository) WithTx(tx *gorm.DB) *TwoFactorRepository {
+	return &TwoFactorRepository{BaseRepository: r.BaseRepository.WithTx(tx)}
+}
+
+// Replace — xóa TOTP cũ (nếu có) rồi lưu cái mới
+func (r *TwoFactorRepository) Replace(tf *model.TwoFactor) error {
+	if err := r.DB.Where("user_id = ?", tf.UserID).Delete(&model.TwoFactor{}).Error; err != nil {
+		return fmt.Errorf("delete two-factor failed: %w", err)
+	}
+	if err := r.DB.Create(tf).Error; err != nil {
+		return fmt.Errorf("create two-factor failed: %w", err)
+	}
+	return nil
+}
+
+// UseStep — ghi nhận bước TOTP vừa dùng, atomic giống PasswordResetRepository.Consume
+// false → bước này (hoặc bước sau) đã được request khác dùng
+func (r *TwoFactorRepository) UseStep(userID uint, step int64) (bool, error) {
+	result := r.DB.Model(&model.TwoFactor{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		return false, fmt.Errorf("use two-factor step failed: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ============================================================
+// RECOVERY CODE
+// ============================================================
+
+// RecoveryCodeRepository — mã dự phòng 2FA
+type RecoveryCodeRepository struct {
+	*repository.BaseRepository[model.RecoveryCode, uint64]
+}
+
+func NewRecoveryCodeRepository(db *gorm.DB) *RecoveryCodeRepository {
+	return &RecoveryCodeRepository{
+		BaseRepository: repository.NewBaseRepository[model.RecoveryCode, uint64](db),
+	}
+}
+
+// WithTx — bản sao chạy trên transaction
+func (r *RecoveryCodeRepository) WithTx(tx *gorm.DB) *RecoveryCodeRepository {
+	return &RecoveryCodeRepository{BaseRepository: r.BaseRepository.WithTx(tx)}
+}
+
+// Replace — bộ mã mới thay toàn bộ bộ cũ (mã cũ hết dùng được)
+func (r *RecoveryCodeRepository) Replace(userID uint, hashes []string) error {
+	if err := r.DeleteUser(userID); err != nil {
+		return err
+	}
+	codes := make([]model.RecoveryCode, len(hashes))
+	for i, hash := range hashes {
+		codes[i] = model.RecoveryCode{UserID: userID, CodeHash: hash}
+	}
+	if err := r.DB.Create(&codes).Error; err != nil {
+		return fmt.Errorf("create recovery codes failed: %w", err)
+	}
+	return nil
+}
+
+// Consume — đánh dấu mã đã dùng, false → mã sai / đã dùng
+func (r *RecoveryCodeRepository) Consume(userID uint, codeHash string, now time.Time) (bool, error) {
+	result := r.DB.Model(&model.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", now)
+	if result.Error != nil {
+		return false, fmt.Errorf("consume recovery code failed: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Remaining — số mã chưa dùng
+func (r *RecoveryCodeRepository) Remaining(userID uint) (int64, error) {
+	var n int64
+	if err := r.DB.Model(&model.RecoveryCode{}).Where("user_id = ? AND used_at IS NULL", userID).Count(&n).Error; err != nil {
+		return 0, fmt.Errorf("count recovery codes failed: %w", err)
+	}
+	return n, nil
+}
+
+// DeleteUser — xóa mọi mã của user (tắt 2FA / tạo bộ mới)
+func (r *RecoveryCodeRepository) DeleteUser(userID uint) error {
+	if err := r.DB.Where("user_id = ?", userID).Delete(&model.RecoveryCode{}).Error; err != nil {
+		return fmt.Errorf("delete recovery codes failed: %w", err)
+	}
+	return nil
+}
+
+// ============================================================
+// CHALLENGE — đăng nhập chờ mã 2FA
+// ============================================================
+
+// TwoFactorChallengeRepository — pending token sau bước mật khẩu
+type TwoFactorChallengeRepository struct {
+	*repository.BaseRepository[model.TwoFactorChallenge, uint64]
+}
+
+func NewTwoFactorChallengeRepository(db *gorm.DB) *TwoFactorChallengeRepository {
+	return &TwoFactorChallengeRepository{
+		BaseRepository: repository.NewBaseRepository[model.TwoFactorChallenge, uint64](db),
+	}
+}
+
+// WithTx — bản sao chạy trên transaction
+func (r *TwoFactorChallengeRepository) WithTx(tx *gorm.DB) *TwoFactorChallengeRepository {
+	return &TwoFactorChallengeRepository{BaseRepository: r.BaseRepository.WithTx(tx)}
+}
+
+// FindByHash — tra challenge theo hash của pending token
+// Không tìm thấy → nil, nil
+func (r *TwoFactorChallengeRepository) FindByHash(tokenHash string) (*model.TwoFactorChallenge, error) {
+	return r.FindByField("token_hash", tokenHash, nil)
+}
+
+// Fail — tăng số lần nhập sai
+func (r *TwoFactorChallengeRepository) Fail(id uint64) error {
+	err := r.DB.Model(&model.TwoFactorChallenge{}).
+		Where("id = ?", id).
+		Update("attempts", gorm.Expr("attempts + 1")).Error
+	if err != nil {
+		return fmt.Errorf("record two-factor attempt failed: %w", err)
+	}
+	return nil
+}
+
+// Consume — đánh dấu challenge đã dùng, false → request khác đã dùng trước
+func (r *TwoFactorChallengeRepository) Consume(id uint64, now time.Time) (bool, error) {
+	result := r.DB.Model(&model.TwoFactorChallenge{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", now)
+	if result.Error != nil {
+		return false, fmt.Errorf("consume two-factor challenge failed: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// InvalidateUser — vô hiệu challenge đang chờ của user (đăng nhập lại → chỉ challenge mới nhất dùng được)
+func (r *TwoFactorChallengeRepository) InvalidateUser(userID uint, now time.Time) error {
+	err := r.DB.Model(&model.TwoFactorChallenge{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", now).Error
+	if err != nil {
+		return fmt.Errorf("invalidate two-factor challenges failed: %w", err)
+	}
+	return nil
+}
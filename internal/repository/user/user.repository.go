@@ -0,0 +1,30 @@
+package user
+
+import (
+	"golang-base/internal/model"
+	"golang-base/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// UserRepository — struct quản lý thao tác DB với User
+type UserRepository struct {
+	*repository.BaseRepository[model.User, uint]
+}
+
+func NewUserRepository(db *gorm.DB) *UserRepository {
+	return &UserRepository{
+		BaseRepository: repository.NewBaseRepository[model.User, uint](db),
+	}
+}
+
+// FindByEmail — tìm user theo email (dùng cho login, quên mật khẩu)
+// Không tìm thấy → nil, nil (giống FindByField)
+func (r *UserRepository) FindByEmail(email string) (*model.User, error) {
+	return r.FindByField("email", email, nil)
+}
+
+// WithTx — bản sao dùng tx (hoặc db.WithContext), giữ được method riêng như FindByEmail
+func (r *UserRepository) WithTx(tx *gorm.DB) *UserRepository {
+	return &UserRepository{BaseRepository: r.BaseRepository.WithTx(tx)}
+}
@@ -1,7 +1,10 @@
 package repository
 
 import (
+	"reflect"
 	"regexp" // dùng regex
+
+	"gorm.io/gorm"
 )
 
 // validFieldName — whitelist ký tự hợp lệ trong tên field
@@ -17,3 +20,13 @@ var fieldNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.]*$`)
 func validFieldName(field string) bool {
 	return fieldNameRegex.MatchString(field)
 }
+
+// whereEquals — WHERE field = value, value là slice → WHERE field IN (values)
+// VD: {"id": []uint{1, 2, 3}} → WHERE id IN (1, 2, 3)
+// []byte và array (uuid.UUID) vẫn là 1 giá trị
+func whereEquals(query *gorm.DB, field string, value any) *gorm.DB {
+	if v := reflect.ValueOf(value); v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		return query.Where(field+" IN ?", value)
+	}
+	return query.Where(field+" = ?", value)
+}
@@ -0,0 +1,126 @@
+package routers
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+
+	"golang-base/internal/middlewares"
+)
+
+// ============================================================
+// RESOURCE — đăng ký đủ bộ route CRUD cho 1 controller trong 1 dòng
+//
+//	RegisterResource(secured, "/user-catalogues", catalogues, Permission("catalogue"), Except(ActionRestore))
+//
+// Controller chỉ cần embed *base.BaseController[T, K] là implement Resource
+// Trả về group của resource → module thêm route riêng (VD: /:id/permissions)
+// ============================================================
+
+// Tên action — dùng cho Except / Only
+const (
+	ActionIndex       = "index"
+	ActionShow        = "show"
+	ActionStore       = "store"
+	ActionUpdate      = "update"
+	ActionPatch       = "patch"
+	ActionDestroy     = "destroy"
+	ActionRestore     = "restore"
+	ActionBulkStore   = "bulk_store"
+	ActionBulkUpdate  = "bulk_update"
+	ActionBulkDestroy = "bulk_destroy"
+)
+
+// Resource — handler CRUD chuẩn (xem base.BaseController)
+type Resource interface {
+	Index(c *gin.Context)
+	Show(c *gin.Context)
+	Store(c *gin.Context)
+	Update(c *gin.Context)
+	Patch(c *gin.Context)
+	Destroy(c *gin.Context)
+	Restore(c *gin.Context)
+	BulkStore(c *gin.Context)
+	BulkUpdate(c *gin.Context)
+	BulkDestroy(c *gin.Context)
+}
+
+// resourceRoute — 1 route của resource, ability: view | create | update | delete
+type resourceRoute struct {
+	action  string
+	method  string
+	path    string
+	ability string
+	handler func(r Resource) gin.HandlerFunc
+}
+
+// Route /bulk đăng ký TRƯỚC /:id cho dễ đọc (gin tự ưu tiên segment tĩnh)
+var resourceRoutes = []resourceRoute{
+	{ActionIndex, http.MethodGet, "", "view", func(r Resource) gin.HandlerFunc { return r.Index }},
+	{ActionStore, http.MethodPost, "", "create", func(r Resource) gin.HandlerFunc { return r.Store }},
+	{ActionBulkStore, http.MethodPost, "/bulk", "create", func(r Resource) gin.HandlerFunc { return r.BulkStore }},
+	{ActionBulkUpdate, http.MethodPatch, "/bulk", "update", func(r Resource) gin.HandlerFunc { return r.BulkUpdate }},
+	{ActionBulkDestroy, http.MethodDelete, "/bulk", "delete", func(r Resource) gin.HandlerFunc { return r.BulkDestroy }},
+	{ActionShow, http.MethodGet, "/:id", "view", func(r Resource) gin.HandlerFunc { return r.Show }},
+	{ActionUpdate, http.MethodPut, "/:id", "update", func(r Resource) gin.HandlerFunc { return r.Update }},
+	{ActionPatch, http.MethodPatch, "/:id", "update", func(r Resource) gin.HandlerFunc { return r.Patch }},
+	{ActionDestroy, http.MethodDelete, "/:id", "delete", func(r Resource) gin.HandlerFunc { return r.Destroy }},
+	// ai được xóa thì được khôi phục (giống policy của BaseService.BulkRestore)
+	{ActionRestore, http.MethodPost, "/:id/restore", "delete", func(r Resource) gin.HandlerFunc { return r.Restore }},
+}
+
+type resourceConfig struct {
+	permission string
+	except     []string
+	only       []string
+}
+
+type ResourceOption func(cfg *resourceConfig)
+
+// Permission — gắn RequirePermission theo action: "catalogue" → catalogue.view / create / update / delete
+func Permission(prefix string) ResourceOption {
+	return func(cfg *resourceConfig) {
+		cfg.permission = prefix
+	}
+}
+
+// Except — bỏ các action (VD: model không có soft delete → Except(ActionRestore))
+func Except(actions ...string) ResourceOption {
+	return func(cfg *resourceConfig) {
+		cfg.except = append(cfg.except, actions...)
+	}
+}
+
+// Only — chỉ đăng ký các action này
+func Only(actions ...string) ResourceOption {
+	return func(cfg *resourceConfig) {
+		cfg.only = append(cfg.only, actions...)
+	}
+}
+
+// RegisterResource — đăng ký route CRUD của controller dưới group + path
+func RegisterResource(group *gin.RouterGroup, path string, controller Resource, opts ...ResourceOption) *gin.RouterGroup {
+	var cfg resourceConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	resource := group.Group(path)
+	for _, route := range resourceRoutes {
+		if slices.Contains(cfg.except, route.action) {
+			continue
+		}
+		if len(cfg.only) > 0 && !slices.Contains(cfg.only, route.action) {
+			continue
+		}
+
+		handlers := make([]gin.HandlerFunc, 0, 2)
+		if cfg.permission != "" {
+			handlers = append(handlers, middlewares.RequirePermission(cfg.permission+"."+route.ability))
+		}
+		handlers = append(handlers, route.handler(controller))
+		resource.Handle(route.method, route.path, handlers...)
+	}
+	return resource
+}
@@ -4,9 +4,17 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 
+	"golang-base/internal/auth/apikey"
+	"golang-base/internal/auth/rbac"
+	"golang-base/internal/auth/throttle"
+	"golang-base/internal/auth/token"
+	"golang-base/internal/auth/totp"
+	"golang-base/internal/event"
+	"golang-base/pkg/apperror"
 	response "golang-base/pkg/response"
 )
 
@@ -18,8 +26,22 @@ func NewRouter(log *zap.Logger) *gin.Engine {
 	return r
 }
 
+// Deps — dependency dùng chung khi dựng controller / service của các module
+type Deps struct {
+	Log         *zap.Logger
+	DB          *gorm.DB
+	Redis       *redis.Client // nil nếu không kết nối được Redis
+	Bus         *event.Bus
+	Tokens      *token.Manager
+	Revocations token.RevocationChecker // nil → không kiểm tra session bị thu hồi
+	RBAC        *rbac.Resolver
+	APIKeys     *apikey.Authenticator // nil → tắt xác thực bằng X-API-Key
+	TOTP        *totp.Manager
+	Throttle    *throttle.Throttler // chống dò mật khẩu trên /auth/*
+}
+
 // RegisterRoutes — đăng ký tất cả routes theo module
-func RegisterRoutes(r *gin.Engine, log *zap.Logger, db *gorm.DB) {
+func RegisterRoutes(r *gin.Engine, deps Deps) {
 	// Health check — không cần auth
 	r.GET("/health", func(c *gin.Context) {
 		response.OK(c, gin.H{
@@ -27,19 +49,40 @@ func RegisterRoutes(r *gin.Engine, log *zap.Logger, db *gorm.DB) {
 		})
 	})
 
-	// API v1
-	v1 := r.Group("/v1")
+	// JWKS — public key để service khác verify access token (HS256 → danh sách rỗng)
+	r.GET("/.well-known/jwks.json", func(c *gin.Context) {
+		c.JSON(http.StatusOK, deps.Tokens.JWKS())
+	})
+
+	// Route không tồn tại → envelope chuẩn thay vì 404 text của gin
+	r.NoRoute(func(c *gin.Context) {
+		response.AppError(c, apperror.ErrRouteNotFound)
+	})
+
+	// API v1 — problem+json: "type" trỏ về catalog mã lỗi, VD: /v1/errors#CATALOGUE_SLUG_TAKEN
+	v1 := r.Group("/v1", response.ProblemTypes("/v1/errors#"))
 	{
 		v1.GET("/ping", Pong)
 		v1.GET("/ping/:name", PongWithName)
+
+		// Catalog mã lỗi — client đồng bộ error_code ↔ message_key (không cần auth)
+		v1.GET("/errors", ErrorCatalog)
+
+		registerUserRoutes(v1, deps)
 	}
 
 	// API v2 — placeholder cho tương lai
-	// v2 := r.Group("/v2")
+	// Lỗi dạng problem+json mặc định cho cả group (client vẫn có thể gửi Accept: application/problem+json ở v1):
+	// v2 := r.Group("/v2", response.UseFormat(response.FormatProblem))
 	// {
 	// }
 }
 
+// ErrorCatalog — GET /v1/errors, mọi code đã Define (code chung + code của các module)
+func ErrorCatalog(c *gin.Context) {
+	response.OK(c, apperror.Catalog())
+}
+
 // Pong — demo endpoint
 func Pong(c *gin.Context) {
 	name := c.DefaultQuery("name", "world")
@@ -0,0 +1,100 @@
+package routers
+
+import (
+	"github.com/gin-gonic/gin"
+
+	uc "golang-base/internal/controller/user"
+	"golang-base/internal/middlewares"
+	us "golang-base/internal/service/user"
+)
+
+// registerUserRoutes — đăng ký, đăng nhập / đăng xuất, 2FA, quên mật khẩu, hồ sơ, nhóm user, phân quyền, session, API key
+func registerUserRoutes(v1 *gin.RouterGroup, deps Deps) {
+	twoFactorService := us.NewTwoFactorService(deps.DB, deps.TOTP, deps.Bus)
+	lockoutService := us.NewLockoutService(deps.DB, deps.Throttle, deps.Bus)
+	users := uc.NewUserController(
+		us.NewUserService(deps.DB, deps.Bus),
+		us.NewTokenService(deps.DB, deps.Tokens, deps.Bus),
+		twoFactorService,
+		lockoutService,
+	)
+	lockouts := uc.NewLockoutController(lockoutService)
+
+	auth := v1.Group("/auth")
+	{
+		auth.POST("/register", users.Register)
+		auth.POST("/login", users.Login)
+		auth.POST("/refresh", users.Refresh)
+		auth.POST("/password/forgot", users.ForgotPassword)
+		auth.POST("/password/reset", users.ResetPassword)
+		auth.POST("/two-factor/verify", users.VerifyTwoFactor)
+		auth.POST("/unlock", lockouts.Unlock)
+	}
+
+	permissions := uc.NewPermissionController(us.NewPermissionService(deps.DB, deps.Bus))
+	sessions := uc.NewSessionController(us.NewSessionService(deps.DB, deps.Bus))
+	apiKeys := uc.NewAPIKeyController(us.NewAPIKeyService(deps.DB, deps.Bus))
+	twoFactor := uc.NewTwoFactorController(twoFactorService)
+
+	// Đã xác thực (X-API-Key hoặc bearer token) + đã nạp role / permission (RequirePermission dùng được)
+	authed := v1.Group("",
+		middlewares.APIKeyMiddleware(deps.APIKeys),
+		middlewares.AuthMiddleware(deps.Tokens, deps.Revocations),
+		middlewares.LoadPermissions(deps.RBAC),
+	)
+
+	// Nhóm bắt buộc 2FA mà token chưa qua 2FA → chỉ gọi được các route dưới đây
+	authed.POST("/auth/logout", sessions.Logout)
+	authed.POST("/auth/logout-all", sessions.LogoutAll)
+
+	tf := authed.Group("/me/two-factor")
+	{
+		tf.GET("", twoFactor.Status)
+		tf.POST("", twoFactor.Enroll)
+		tf.POST("/confirm", twoFactor.Confirm)
+		tf.DELETE("", twoFactor.Disable)
+		tf.POST("/recovery-codes", twoFactor.RegenerateRecoveryCodes)
+	}
+
+	// Còn lại → RequireTwoFactor
+	secured := authed.Group("", middlewares.RequireTwoFactor())
+
+	me := secured.Group("/me")
+	{
+		me.GET("", users.Profile)
+		me.PUT("", users.UpdateProfile)
+		me.PUT("/password", users.ChangePassword)
+		me.GET("/permissions", permissions.Mine)
+		me.GET("/sessions", sessions.Mine)
+		me.DELETE("/sessions/:sid", sessions.RevokeMine)
+	}
+
+	secured.GET("/permissions", middlewares.RequirePermission("permission.view"), permissions.List)
+
+	// Nhóm user — CRUD chuẩn (không có soft delete → bỏ restore) + publish hàng loạt + gán permission
+	catalogueController := uc.NewUserCatalogueController(us.NewCatalogueService(deps.DB, deps.Bus))
+	catalogues := RegisterResource(secured, "/user-catalogues", catalogueController, Permission("catalogue"), Except(ActionRestore))
+	{
+		catalogues.POST("/bulk/publish", middlewares.RequirePermission("catalogue.update"), catalogueController.Publish)
+		catalogues.POST("/bulk/unpublish", middlewares.RequirePermission("catalogue.update"), catalogueController.Unpublish)
+		catalogues.GET("/:id/permissions", middlewares.RequirePermission("catalogue.view"), permissions.Catalogue)
+		catalogues.PUT("/:id/permissions", middlewares.RequirePermission("catalogue.update"), permissions.SyncCatalogue)
+	}
+
+	accounts := secured.Group("/users/:id")
+	{
+		accounts.GET("/sessions", middlewares.RequirePermission("session.view"), sessions.List)
+		accounts.DELETE("/sessions", middlewares.RequirePermission("session.revoke"), sessions.RevokeAll)
+		accounts.DELETE("/sessions/:sid", middlewares.RequirePermission("session.revoke"), sessions.Revoke)
+		accounts.GET("/lockout", middlewares.RequirePermission("user.view"), lockouts.Status)
+		accounts.DELETE("/lockout", middlewares.RequirePermission("user.update"), lockouts.Release)
+	}
+
+	keys := secured.Group("/api-keys")
+	{
+		keys.GET("", middlewares.RequirePermission("apikey.view"), apiKeys.List)
+		keys.POST("", middlewares.RequirePermission("apikey.manage"), apiKeys.Create)
+		keys.POST("/:id/rotate", middlewares.RequirePermission("apikey.manage"), apiKeys.Rotate)
+		keys.DELETE("/:id", middlewares.RequirePermission("apikey.manage"), apiKeys.Revoke)
+	}
+}
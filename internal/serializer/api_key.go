@@ -0,0 +1,7 @@
+package serializer
+
+import "golang-base/internal/model"
+
+// APIKey — metadata của key, hash KHÔNG bao giờ có mặt (key gốc chỉ trả 1 lần lúc tạo / rotate)
+var APIKey = New[model.APIKey]().
+	Attrs("id", "name", "prefix", "scopes", "expires_at", "last_used_at", "created_by", "created_at")
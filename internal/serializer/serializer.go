@@ -0,0 +1,325 @@
+// Package serializer — map model sang response, thay vì trả thẳng GORM model
+//
+//	var UserSerializer = serializer.New[model.User]().
+//		Attrs("id", "name", "created_at").
+//		Attr("email", serializer.Roles[model.User]("admin")).
+//		Computed("avatar", func(ctx context.Context, u *model.User) any { return gravatar(u.Email) })
+//
+//	response.OK(c, UserSerializer.One(&user))
+//	response.OK(c, UserSerializer.Page(result))
+//
+// Nguyên tắc:
+// → WHITELIST: chỉ field được khai báo mới ra response → thêm column nhạy cảm vào model không bị lộ
+// → Ẩn/hiện theo principal (role, chủ sở hữu...) → xem Option
+// → ?fields=id,name → client chỉ lấy field cần (sparse fieldset), giao với tập field được phép xem
+package serializer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"golang-base/global/common"
+	"golang-base/internal/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FieldsParam — query param cho sparse fieldset: ?fields=id,name
+const FieldsParam = "fields"
+
+// field — 1 field trong response
+type field[T any] struct {
+	name    string
+	value   func(ctx context.Context, record *T) any
+	visible []func(ctx context.Context, p *auth.Principal, record *T) bool
+	dynamic bool // có điều kiện theo record (When, OwnerOr) → không biết trước khi chưa có record
+}
+
+// Option — điều kiện hiện field, nhiều option = phải thỏa TẤT CẢ
+type Option[T any] func(f *field[T])
+
+// Roles — chỉ hiện khi principal có ít nhất 1 role trong danh sách
+func Roles[T any](roles ...string) Option[T] {
+	return whenPrincipal[T](func(p *auth.Principal) bool {
+		return slices.ContainsFunc(roles, p.HasRole)
+	})
+}
+
+// Permission — chỉ hiện khi principal có permission
+func Permission[T any](permission string) Option[T] {
+	return whenPrincipal[T](func(p *auth.Principal) bool {
+		return p.Can(permission)
+	})
+}
+
+// OwnerOr — hiện cho chủ sở hữu record (principal.ID == ownerID(record)) hoặc principal có 1 trong các role
+// VD: email chỉ hiện cho chính user đó và admin
+//
+//	serializer.OwnerOr(func(u *model.User) string { return strconv.Itoa(int(u.ID)) }, "admin")
+func OwnerOr[T any](ownerID func(record *T) string, roles ...string) Option[T] {
+	return When(func(ctx context.Context, p *auth.Principal, record *T) bool {
+		if p == nil {
+			return false
+		}
+		return p.ID == ownerID(record) || slices.ContainsFunc(roles, p.HasRole)
+	})
+}
+
+// When — điều kiện tùy ý theo principal + record (p có thể nil: request chưa xác thực)
+func When[T any](fn func(ctx context.Context, p *auth.Principal, record *T) bool) Option[T] {
+	return func(f *field[T]) {
+		f.visible = append(f.visible, fn)
+		f.dynamic = true
+	}
+}
+
+// whenPrincipal — điều kiện chỉ theo principal, không cần record → Hidden biết trước được
+func whenPrincipal[T any](fn func(p *auth.Principal) bool) Option[T] {
+	return func(f *field[T]) {
+		f.visible = append(f.visible, func(ctx context.Context, p *auth.Principal, record *T) bool {
+			return fn(p)
+		})
+	}
+}
+
+// Serializer — tập field định nghĩa response cho model T
+// Khai báo 1 lần lúc khởi động (biến package), dùng đồng thời an toàn (chỉ đọc)
+type Serializer[T any] struct {
+	fields  []field[T]
+	columns map[string][]int // json name → index field trong struct (dùng cho Attr)
+}
+
+func New[T any]() *Serializer[T] {
+	return &Serializer[T]{columns: jsonColumns(reflect.TypeOf((*T)(nil)).Elem())}
+}
+
+// Attr — field lấy thẳng từ struct theo tên json tag
+// Tên sai → panic lúc khởi động (lỗi lập trình, không đợi tới lúc có request)
+func (s *Serializer[T]) Attr(name string, opts ...Option[T]) *Serializer[T] {
+	index, ok := s.columns[name]
+	if !ok {
+		var zero T
+		panic(fmt.Sprintf("serializer: %T has no json field %q", zero, name))
+	}
+	return s.add(name, func(ctx context.Context, record *T) any {
+		return reflect.ValueOf(record).Elem().FieldByIndex(index).Interface()
+	}, opts)
+}
+
+// Attrs — nhiều Attr cùng lúc, không kèm option
+func (s *Serializer[T]) Attrs(names ...string) *Serializer[T] {
+	for _, name := range names {
+		s.Attr(name)
+	}
+	return s
+}
+
+// Computed — field tính toán, không có trong DB
+// VD: full_name, avatar_url, is_expired, relation đã serialize...
+func (s *Serializer[T]) Computed(name string, fn func(ctx context.Context, record *T) any, opts ...Option[T]) *Serializer[T] {
+	return s.add(name, fn, opts)
+}
+
+func (s *Serializer[T]) add(name string, value func(ctx context.Context, record *T) any, opts []Option[T]) *Serializer[T] {
+	f := field[T]{name: name, value: value}
+	for _, opt := range opts {
+		opt(&f)
+	}
+	s.fields = append(s.fields, f)
+	return s
+}
+
+// Serialize — dựng response cho 1 record
+// fields: sparse fieldset (nil = mọi field được phép xem)
+// Principal lấy từ ctx (auth.PrincipalFrom)
+func (s *Serializer[T]) Serialize(ctx context.Context, record *T, fields []string) Object {
+	if record == nil {
+		return nil
+	}
+	p, _ := auth.PrincipalFrom(ctx)
+
+	obj := make(Object, 0, len(s.fields))
+	for i := range s.fields {
+		f := &s.fields[i]
+		if len(fields) > 0 && !slices.Contains(fields, f.name) {
+			continue
+		}
+		if !f.allowed(ctx, p, record) {
+			continue
+		}
+		obj = append(obj, Pair{Key: f.name, Value: f.value(ctx, record)})
+	}
+	return obj
+}
+
+// SerializeMany — dựng response cho danh sách record
+func (s *Serializer[T]) SerializeMany(ctx context.Context, records []T, fields []string) []Object {
+	list := make([]Object, len(records))
+	for i := range records {
+		list[i] = s.Serialize(ctx, &records[i], fields)
+	}
+	return list
+}
+
+// Hidden — field bị ẩn với principal trong ctx (ở ít nhất 1 record)
+// Dùng để chặn filter / sort / tìm kiếm trên field client không được xem:
+// filter[role]=admin mà role bị ẩn → đếm kết quả vẫn dò ra được giá trị
+// Field có điều kiện theo record (When, OwnerOr) → luôn coi là ẩn; field không khai báo → false
+func (s *Serializer[T]) Hidden(ctx context.Context, name string) bool {
+	p, _ := auth.PrincipalFrom(ctx)
+	for i := range s.fields {
+		f := &s.fields[i]
+		if f.name != name {
+			continue
+		}
+		return f.dynamic || !f.allowed(ctx, p, nil)
+	}
+	return false
+}
+
+func (f *field[T]) allowed(ctx context.Context, p *auth.Principal, record *T) bool {
+	for _, visible := range f.visible {
+		if !visible(ctx, p, record) {
+			return false
+		}
+	}
+	return true
+}
+
+// ============================================================
+// TRANSFORMER — giá trị trì hoãn, response.Success gọi Transform(c)
+// → principal + ?fields= đọc từ request tại thời điểm trả response
+// ============================================================
+
+// One — response.OK(c, UserSerializer.One(&user))
+func (s *Serializer[T]) One(record *T) Transformer[T] {
+	return Transformer[T]{s: s, one: record}
+}
+
+// Many — response.OK(c, UserSerializer.Many(users))
+func (s *Serializer[T]) Many(records []T) Transformer[T] {
+	return Transformer[T]{s: s, many: records, isMany: true}
+}
+
+// Page — response.OK(c, UserSerializer.Page(result)), giữ nguyên total / next_cursor / has_more / meta / links
+func (s *Serializer[T]) Page(result *common.PaginateResult[T]) Transformer[T] {
+	return Transformer[T]{s: s, page: result}
+}
+
+// Transformer — implement response.Transformer
+type Transformer[T any] struct {
+	s      *Serializer[T]
+	one    *T
+	many   []T
+	isMany bool
+	page   *common.PaginateResult[T]
+}
+
+func (t Transformer[T]) Transform(c *gin.Context) any {
+	ctx := c.Request.Context()
+	fields := ParseFields(c.Query(FieldsParam))
+
+	switch {
+	case t.page != nil:
+		return common.PaginateResult[Object]{
+			Data:       t.s.SerializeMany(ctx, t.page.Data, fields),
+			Total:      t.page.Total,
+			NextCursor: t.page.NextCursor,
+			HasMore:    t.page.HasMore,
+			Meta:       t.page.Meta,
+			Links:      t.page.Links,
+		}
+	case t.isMany:
+		return t.s.SerializeMany(ctx, t.many, fields)
+	default:
+		return t.s.Serialize(ctx, t.one, fields)
+	}
+}
+
+// ParseFields — "id, name,,slug" → ["id", "name", "slug"], rỗng → nil
+func ParseFields(raw string) []string {
+	if strings.TrimSpace(raw) == "" {
+		return nil
+	}
+	var fields []string
+	for _, f := range strings.Split(raw, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// ============================================================
+// OBJECT — JSON object GIỮ THỨ TỰ field theo khai báo
+// (map của Go bị encoding/json sort theo alphabet)
+// ============================================================
+
+type Pair struct {
+	Key   string
+	Value any
+}
+
+type Object []Pair
+
+// Get — lấy giá trị theo key (tiện khi test / xử lý tiếp)
+func (o Object) Get(key string) (any, bool) {
+	for _, p := range o {
+		if p.Key == key {
+			return p.Value, true
+		}
+	}
+	return nil, false
+}
+
+func (o Object) MarshalJSON() ([]byte, error) {
+	if o == nil {
+		return []byte("null"), nil
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, p := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(p.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(p.Value)
+		if err != nil {
+			return nil, fmt.Errorf("serialize field %q failed: %w", p.Key, err)
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// jsonColumns — json name → index field (kể cả field của struct embed)
+func jsonColumns(t reflect.Type) map[string][]int {
+	columns := make(map[string][]int)
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+		if _, exists := columns[name]; !exists {
+			columns[name] = f.Index
+		}
+	}
+	return columns
+}
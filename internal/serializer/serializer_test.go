@@ -0,0 +1,36 @@
+package serializer
+
+import (
+	"context"
+	"testing"
+
+	"golang-base/internal/auth"
+)
+
+func TestHidden(t *testing.T) {
+	admin := auth.WithPrincipal(context.Background(), &auth.Principal{ID: "1", Kind: auth.KindUser, Roles: []string{"admin"}})
+	viewer := auth.WithPrincipal(context.Background(), &auth.Principal{ID: "2", Kind: auth.KindUser, Roles: []string{"viewer"}})
+
+	cases := []struct {
+		name  string
+		ctx   context.Context
+		field string
+		want  bool
+	}{
+		{"role for admin", admin, "role", false},
+		{"role for viewer", viewer, "role", true},
+		{"role unauthenticated", context.Background(), "role", true},
+		{"public field", viewer, "publish", false},
+		{"undeclared field", viewer, "deleted_at", false},
+	}
+	for _, tc := range cases {
+		if got := UserCatalogue.Hidden(tc.ctx, tc.field); got != tc.want {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+
+	// email phụ thuộc từng record (chủ sở hữu) → không biết trước, kể cả với admin
+	if !User.Hidden(admin, "email") {
+		t.Fatal("record-dependent field must be treated as hidden")
+	}
+}
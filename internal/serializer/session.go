@@ -0,0 +1,20 @@
+package serializer
+
+import (
+	"context"
+
+	"golang-base/internal/auth"
+	"golang-base/internal/model"
+)
+
+// Session — thiết bị đăng nhập, "current" = session của chính request này
+var Session = New[model.Session]().
+	Attrs("id", "device", "user_agent", "ip", "last_seen_at", "expires_at", "created_at").
+	Computed("current", func(ctx context.Context, s *model.Session) any {
+		p, ok := auth.PrincipalFrom(ctx)
+		if !ok {
+			return false
+		}
+		current, _ := p.Attributes["session_id"].(string)
+		return current == s.ID
+	})
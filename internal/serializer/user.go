@@ -0,0 +1,19 @@
+package serializer
+
+import (
+	"strconv"
+
+	"golang-base/internal/model"
+)
+
+// User — email chỉ hiện cho chính user đó + admin, password KHÔNG bao giờ có mặt
+var User = New[model.User]().
+	Attrs("id", "name").
+	Attr("email", OwnerOr(func(u *model.User) string { return strconv.FormatUint(uint64(u.ID), 10) }, "admin")).
+	Attrs("created_at", "updated_at")
+
+// UserCatalogue — role nội bộ chỉ admin thấy
+var UserCatalogue = New[model.UserCatalogue]().
+	Attrs("id", "name", "slug", "description").
+	Attr("role", Roles[model.UserCatalogue]("admin")).
+	Attrs("publish", "require_two_factor", "created_at", "updated_at")
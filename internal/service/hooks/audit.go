@@ -0,0 +1,78 @@
+package hooks
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"golang-base/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// AuditHook — ghi lịch sử thay đổi vào bảng audit_logs
+// Ghi bằng tx của pipeline → transactional mode: audit rollback cùng data, không có log "ma"
+// Bulk action ghi 1 dòng cho cả batch (auditable_id rỗng)
+type AuditHook[T any, K comparable] struct {
+	auditableType string
+}
+
+// Audit — auditableType: tên loại record trong log, VD "user_catalogue"
+func Audit[T any, K comparable](auditableType string) *AuditHook[T, K] {
+	return &AuditHook[T, K]{auditableType: auditableType}
+}
+
+func (h *AuditHook[T, K]) AfterCreate(tx *gorm.DB, payload *T) error {
+	s, err := parseSchema(tx, payload)
+	if err != nil {
+		return err
+	}
+	id := ""
+	if s.PrioritizedPrimaryField != nil {
+		if f, err := structField(payload, s.PrioritizedPrimaryField.Name); err == nil {
+			id = fmt.Sprint(f.Interface())
+		}
+	}
+	return h.write(tx, model.AuditActionCreated, id, payload)
+}
+
+func (h *AuditHook[T, K]) AfterUpdate(tx *gorm.DB, id K, payload *T) error {
+	return h.write(tx, model.AuditActionUpdated, fmt.Sprint(id), payload)
+}
+
+func (h *AuditHook[T, K]) AfterDelete(tx *gorm.DB, id K) error {
+	return h.write(tx, model.AuditActionDeleted, fmt.Sprint(id), nil)
+}
+
+func (h *AuditHook[T, K]) AfterBulkUpdate(tx *gorm.DB, conditions map[string]any, fields map[string]any, affected int64) error {
+	return h.write(tx, model.AuditActionBulkUpdated, "", map[string]any{
+		"conditions": conditions,
+		"fields":     fields,
+		"affected":   affected,
+	})
+}
+
+func (h *AuditHook[T, K]) AfterBulkDelete(tx *gorm.DB, ids []K) error {
+	return h.write(tx, model.AuditActionBulkDeleted, "", map[string]any{"ids": ids})
+}
+
+func (h *AuditHook[T, K]) AfterBulkRestore(tx *gorm.DB, ids []K, affected int64) error {
+	return h.write(tx, model.AuditActionBulkRestored, "", map[string]any{"ids": ids, "affected": affected})
+}
+
+func (h *AuditHook[T, K]) write(tx *gorm.DB, action, id string, data any) error {
+	changes, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("marshal audit changes failed: %w", err)
+	}
+
+	log := model.AuditLog{
+		AuditableType: h.auditableType,
+		AuditableID:   id,
+		Action:        action,
+		Changes:       string(changes),
+	}
+	if err := tx.Session(&gorm.Session{NewDB: true}).Create(&log).Error; err != nil {
+		return fmt.Errorf("write audit log failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+// Package hooks — các hook dùng lại được cho mọi module, đăng ký bằng 1 dòng:
+//
+//	service.
+//		AddHook(hooks.Slug[model.Post, uint]("Title", "Slug").EnsureUnique(), 10).
+//		AddHook(hooks.Timestamps[model.Post, uint]("PublishedAt", ""), 20).
+//		AddHook(hooks.Audit[model.Post, uint]("post"), 100)
+//
+// Field truyền vào là tên field Go (Title, Slug...), sai tên → hook trả lỗi
+package hooks
+
+import (
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// structField — lấy field theo tên Go từ con trỏ struct
+func structField(payload any, name string) (reflect.Value, error) {
+	v := reflect.ValueOf(payload)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("payload must be a pointer to struct, got %T", payload)
+	}
+	f := v.Elem().FieldByName(name)
+	if !f.IsValid() {
+		return reflect.Value{}, fmt.Errorf("field %q not found on %T", name, payload)
+	}
+	return f, nil
+}
+
+// parseSchema — GORM schema của model → dùng lấy tên bảng, tên column, primary key
+func parseSchema(tx *gorm.DB, model any) (*schema.Schema, error) {
+	stmt := &gorm.Statement{DB: tx}
+	if err := stmt.Parse(model); err != nil {
+		return nil, fmt.Errorf("parse schema failed: %w", err)
+	}
+	return stmt.Schema, nil
+}
@@ -0,0 +1,129 @@
+package hooks
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+	"gorm.io/gorm"
+)
+
+// maxSlugSuffix — số lần thử thêm hậu tố -2, -3... trước khi bỏ cuộc
+const maxSlugSuffix = 100
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Slugify — "Quản trị viên cấp cao" → "quan-tri-vien-cap-cao"
+// Bỏ dấu tiếng Việt (NFD + loại dấu), đ → d, ký tự lạ → "-"
+func Slugify(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	s, _, _ = transform.String(t, s)
+	s = strings.NewReplacer("đ", "d", "Đ", "d").Replace(s)
+	s = nonSlugChars.ReplaceAllString(strings.ToLower(s), "-")
+	return strings.Trim(s, "-")
+}
+
+// SlugHook — tự sinh slug từ 1 field khác khi tạo/sửa
+// → Slug client gửi lên (khác rỗng) được giữ nguyên, chỉ chuẩn hóa lại
+type SlugHook[T any, K comparable] struct {
+	from   string
+	to     string
+	unique bool
+}
+
+// Slug — VD: hooks.Slug[model.UserCatalogue, uint]("Name", "Slug")
+func Slug[T any, K comparable](from, to string) *SlugHook[T, K] {
+	return &SlugHook[T, K]{from: from, to: to}
+}
+
+// EnsureUnique — slug trùng trong bảng → thêm hậu tố: admin, admin-2, admin-3...
+func (h *SlugHook[T, K]) EnsureUnique() *SlugHook[T, K] {
+	h.unique = true
+	return h
+}
+
+func (h *SlugHook[T, K]) BeforeCreate(tx *gorm.DB, payload *T) error {
+	return h.apply(tx, payload, nil)
+}
+
+// BeforeBulkCreate — sinh slug cho từng phần tử (BulkCreate không chạy BeforeCreate)
+// Lưu ý: EnsureUnique chỉ so với DB, 2 phần tử cùng name trong batch vẫn trùng → unique index chặn
+func (h *SlugHook[T, K]) BeforeBulkCreate(tx *gorm.DB, payloads []T) error {
+	for i := range payloads {
+		if err := h.apply(tx, &payloads[i], nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BeforeUpdate — partial update: không gửi cả from lẫn to → không đụng tới slug
+func (h *SlugHook[T, K]) BeforeUpdate(tx *gorm.DB, id K, payload *T) error {
+	return h.apply(tx, payload, &id)
+}
+
+func (h *SlugHook[T, K]) apply(tx *gorm.DB, payload *T, id *K) error {
+	from, err := structField(payload, h.from)
+	if err != nil {
+		return err
+	}
+	to, err := structField(payload, h.to)
+	if err != nil {
+		return err
+	}
+	if to.Kind() != reflect.String || from.Kind() != reflect.String {
+		return fmt.Errorf("slug hook: %s and %s must be string fields", h.from, h.to)
+	}
+
+	source := to.String()
+	if source == "" {
+		source = from.String()
+	}
+	if source == "" {
+		return nil
+	}
+
+	slug := Slugify(source)
+	if h.unique {
+		if slug, err = h.uniqueSlug(tx, slug, id); err != nil {
+			return err
+		}
+	}
+	to.SetString(slug)
+	return nil
+}
+
+// uniqueSlug — tìm slug chưa tồn tại, update thì bỏ qua chính record đang sửa
+func (h *SlugHook[T, K]) uniqueSlug(tx *gorm.DB, base string, id *K) (string, error) {
+	s, err := parseSchema(tx, new(T))
+	if err != nil {
+		return "", err
+	}
+	column := s.LookUpField(h.to)
+	if column == nil || column.DBName == "" {
+		return "", fmt.Errorf("slug hook: field %q is not a column", h.to)
+	}
+
+	candidate := base
+	for i := 2; i <= maxSlugSuffix+1; i++ {
+		query := tx.Session(&gorm.Session{NewDB: true}).Model(new(T)).Where(column.DBName+" = ?", candidate)
+		if id != nil && s.PrioritizedPrimaryField != nil {
+			query = query.Where(s.PrioritizedPrimaryField.DBName+" <> ?", *id)
+		}
+
+		var count int64
+		if err := query.Count(&count).Error; err != nil {
+			return "", fmt.Errorf("check slug failed: %w", err)
+		}
+		if count == 0 {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-%d", base, i)
+	}
+	return "", fmt.Errorf("slug hook: cannot find unique slug for %q", base)
+}
@@ -0,0 +1,72 @@
+package hooks
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// TimestampHook — gán thời gian cho field mà GORM KHÔNG tự quản lý
+// GORM chỉ tự điền CreatedAt/UpdatedAt (hoặc field có tag autoCreateTime/autoUpdateTime)
+// → Field đặt tên khác (PublishedAt, SyncedAt...) hoặc cần đồng hồ giả khi test → dùng hook này
+//
+// Hỗ trợ field kiểu time.Time và *time.Time
+type TimestampHook[T any, K comparable] struct {
+	created string
+	updated string
+	now     func() time.Time
+}
+
+// Timestamps — created: field gán 1 lần khi tạo (nếu đang rỗng), updated: gán mỗi lần tạo/sửa
+// Truyền "" để bỏ qua field tương ứng
+//
+// VD: hooks.Timestamps[model.Post, uint]("PublishedAt", "")
+func Timestamps[T any, K comparable](created, updated string) *TimestampHook[T, K] {
+	return &TimestampHook[T, K]{created: created, updated: updated, now: time.Now}
+}
+
+// WithClock — thay nguồn thời gian (test, hoặc ép UTC)
+func (h *TimestampHook[T, K]) WithClock(now func() time.Time) *TimestampHook[T, K] {
+	h.now = now
+	return h
+}
+
+func (h *TimestampHook[T, K]) BeforeCreate(tx *gorm.DB, payload *T) error {
+	now := h.now()
+	if err := h.set(payload, h.created, now, false); err != nil {
+		return err
+	}
+	return h.set(payload, h.updated, now, true)
+}
+
+func (h *TimestampHook[T, K]) BeforeUpdate(tx *gorm.DB, id K, payload *T) error {
+	return h.set(payload, h.updated, h.now(), true)
+}
+
+// set — overwrite=false: chỉ gán khi field đang là zero value
+func (h *TimestampHook[T, K]) set(payload *T, name string, now time.Time, overwrite bool) error {
+	if name == "" {
+		return nil
+	}
+	f, err := structField(payload, name)
+	if err != nil {
+		return err
+	}
+	if !overwrite && !f.IsZero() {
+		return nil
+	}
+
+	switch {
+	case f.Type() == timeType:
+		f.Set(reflect.ValueOf(now))
+	case f.Kind() == reflect.Pointer && f.Type().Elem() == timeType:
+		f.Set(reflect.ValueOf(&now))
+	default:
+		return fmt.Errorf("timestamp hook: field %q must be time.Time or *time.Time", name)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package hooks
+
+import "gorm.io/gorm"
+
+// ValidateHook — chạy hàm validate trước khi tạo/sửa, lỗi = dừng pipeline
+// tx cho phép validate có truy vấn DB (VD: check trùng) trong cùng transaction
+type ValidateHook[T any, K comparable] struct {
+	fn func(tx *gorm.DB, payload *T) error
+}
+
+// Validate — VD:
+//
+//	hooks.Validate[model.UserCatalogue, uint](func(tx *gorm.DB, p *model.UserCatalogue) error {
+//		if p.Name == "" {
+//			return errors.New("name is required")
+//		}
+//		return nil
+//	})
+func Validate[T any, K comparable](fn func(tx *gorm.DB, payload *T) error) *ValidateHook[T, K] {
+	return &ValidateHook[T, K]{fn: fn}
+}
+
+func (h *ValidateHook[T, K]) BeforeCreate(tx *gorm.DB, payload *T) error {
+	return h.fn(tx, payload)
+}
+
+func (h *ValidateHook[T, K]) BeforeUpdate(tx *gorm.DB, id K, payload *T) error {
+	return h.fn(tx, payload)
+}
@@ -3,109 +3,800 @@
 // - Gọi repository để lấy/ghi Data.
 // - KHÔNG biết Database là MySQL hay Mongo (đã có repo lo).
 // - KHÔNG biết HTTP Request/Response (đã có Controller lo).
+//
+// Mọi method nhận ctx đầu tiên: mang principal (phân quyền), deadline/cancel của request
+// → Controller truyền c.Request.Context(), cronjob/worker tự dựng ctx
 package impl
 
 import (
+	"context"
+	"fmt"
+	"reflect"
+
 	"golang-base/global/common"
+	"golang-base/internal/auth"
+	"golang-base/internal/event"
+	"golang-base/internal/outbox"
 	r "golang-base/internal/repository"
-	"golang-base/internal/service/interfaces"
 	si "golang-base/internal/service/interfaces"
+	"golang-base/pkg/validation"
+
+	"gorm.io/gorm"
 )
 
 // BaseService — implement interfaces.IBaseService[T]
-type BaseService[T any] struct {
-	br   *r.BaseRepository[T]
-	hook si.IHook[T] // Instance chứa các hàm hook của module con
+type BaseService[T any, K comparable] struct {
+	br     *r.BaseRepository[T, K]
+	entity string // tên model, dùng cho interceptor + thông báo lỗi
+
+	// hooks — đã sort theo priority, xem AddHook
+	hooks []registeredHook
+
+	// interceptors — bọc quanh mọi method, xem Use
+	interceptors []si.Interceptor
+
+	// policy — nil = không phân quyền theo record, xem WithPolicy
+	policy auth.Policy[T]
+
+	// transactional — hook chạy chung transaction với thao tác ghi, xem UseTransaction
+	transactional bool
+
+	// skipValidation — tắt validate tag `validate`, xem DisableValidation
+	skipValidation bool
+
+	// aggregateType — khác rỗng = bật outbox, xem EnableOutbox
+	aggregateType string
+
+	// bus — nil = không publish domain event, xem WithEventBus
+	bus *event.Bus
 }
 
-// NewBaseService — inject cả Repository lẫn Hook
+// NewBaseService — inject Repository + các hook (priority 0, theo thứ tự truyền vào)
 // Hook thường chính là con trỏ của module service con.
-// VD: &UserCatalogueService{}
-func NewBaseService[T any](br *r.BaseRepository[T], hook interfaces.IHook[T]) *BaseService[T] {
-	return &BaseService[T]{
-		br:   br,
-		hook: hook,
+// VD: NewBaseService(repo, &UserCatalogueService{})
+//
+// Cần priority khác → dùng AddHook
+func NewBaseService[T any, K comparable](br *r.BaseRepository[T, K], hooks ...any) *BaseService[T, K] {
+	s := &BaseService[T, K]{
+		br:     br,
+		entity: reflect.TypeOf((*T)(nil)).Elem().Name(),
 	}
+	for _, hook := range hooks {
+		s.AddHook(hook, 0)
+	}
+	return s
 }
 
-// ============================================================
-// SINGLE ACTIONS — Có áp dụng Hook Pipeline
-// ============================================================
+// EnableOutbox — ghi event vòng đời vào outbox CÙNG transaction với thao tác ghi
+// aggregateType: tiền tố event, VD "user_catalogue"
+// → user_catalogue.created / user_catalogue.updated / user_catalogue.deleted
+// Bulk* ghi 1 event cho mỗi record bị ảnh hưởng (BulkRestore → user_catalogue.restored)
+//
+// Dispatcher chạy nền sẽ relay event sang Sink (xem internal/outbox)
+// Bật outbox = bật luôn transactional mode (xem UseTransaction)
+func (s *BaseService[T, K]) EnableOutbox(aggregateType string) *BaseService[T, K] {
+	s.aggregateType = aggregateType
+	return s
+}
 
-func (s *BaseService[T]) Create(payload *T) error {
-	// 1. Hook Before
-	if s.hook != nil {
-		if err := s.hook.BeforeCreate(payload); err != nil {
-			return err // Dừng sớm nều validate/logic trước khi tạo fail
-		}
-	}
+// WithEventBus — publish EntityCreated / EntityUpdated / EntityDeleted sau mỗi thao tác ghi
+// Subscriber đăng ký qua event.Subscribe / event.SubscribeAsync
+func (s *BaseService[T, K]) WithEventBus(bus *event.Bus) *BaseService[T, K] {
+	s.bus = bus
+	return s
+}
 
-	// 2. Action chính
-	if err := s.br.Create(payload); err != nil {
-		return err
+// WithPolicy — phân quyền theo record, principal lấy từ ctx (auth.PrincipalFrom)
+// Từ chối → auth.ErrUnauthenticated (chưa đăng nhập) / auth.ErrForbidden
+func (s *BaseService[T, K]) WithPolicy(policy auth.Policy[T]) *BaseService[T, K] {
+	s.policy = policy
+	return s
+}
+
+// UseTransaction — bật transactional mode
+// Before* + thao tác chính + After* (+ outbox event) chạy trong CÙNG 1 transaction
+// → After* trả lỗi = rollback luôn record vừa ghi
+// → Side effect chỉ được chạy khi data đã durable: implement IAfterCommitHook
+//
+// EnableOutbox cũng tự bọc transaction (event phải commit cùng data)
+func (s *BaseService[T, K]) UseTransaction() *BaseService[T, K] {
+	s.transactional = true
+	return s
+}
+
+// DisableValidation — tắt bước validate tự động (model tự validate ở chỗ khác)
+func (s *BaseService[T, K]) DisableValidation() *BaseService[T, K] {
+	s.skipValidation = true
+	return s
+}
+
+// validate — validate payload theo tag `validate` (xem pkg/validation)
+// Chạy SAU Before* hook: hook có thể điền thêm data (slug, timestamp...) trước khi validate
+// id != nil → rule unique bỏ qua chính record đang sửa
+// partial → Update: chỉ validate field có giá trị
+func (s *BaseService[T, K]) validate(ctx context.Context, repo *r.BaseRepository[T, K], payload *T, id *K, partial bool) error {
+	if s.skipValidation {
+		return nil
 	}
+	ctx = s.uniqueContext(ctx, repo, id)
+	if partial {
+		return validation.Partial(ctx, payload)
+	}
+	return validation.Struct(ctx, payload)
+}
 
-	// 3. Hook After
-	if s.hook != nil {
-		if err := s.hook.AfterCreate(payload); err != nil {
-			return err // Báo lỗi nhưng record đã được insert (trừ khi quấn trong Transaction)
+// uniqueContext — ctx mang checker cho rule unique, chạy trên repo của transaction hiện tại
+func (s *BaseService[T, K]) uniqueContext(ctx context.Context, repo *r.BaseRepository[T, K], id *K) context.Context {
+	return validation.WithUniqueChecker(ctx, func(column string, value any) (bool, error) {
+		if id != nil {
+			return repo.ExistsByFieldExcept(column, value, *id)
 		}
+		return repo.ExistsByField(column, value)
+	})
+}
+
+// checkPrecondition — ctx có Precondition → đọc + lock record hiện tại, không khớp → ErrPreconditionFailed
+// Record không tồn tại → bỏ qua, để thao tác chính trả lỗi not found như bình thường
+func (s *BaseService[T, K]) checkPrecondition(ctx context.Context, tx *gorm.DB, repo *r.BaseRepository[T, K], id K) error {
+	check, ok := si.PreconditionFrom[T](ctx)
+	if !ok {
+		return nil
+	}
+	current, err := repo.FindByIdForUpdate(tx, id)
+	if err != nil {
+		return err
+	}
+	if current != nil && !check(current) {
+		return fmt.Errorf("%w: %s %v", si.ErrPreconditionFailed, s.entity, id)
 	}
 	return nil
 }
 
-func (s *BaseService[T]) Update(id uint, payload *T) error {
-	if s.hook != nil {
-		if err := s.hook.BeforeUpdate(id, payload); err != nil {
+// authorize — hỏi policy với principal trong ctx, không có policy = cho qua
+func (s *BaseService[T, K]) authorize(ctx context.Context, allowed func(p *auth.Principal) bool) error {
+	if s.policy == nil {
+		return nil
+	}
+	p, ok := auth.PrincipalFrom(ctx)
+	if allowed(p) {
+		return nil
+	}
+	if !ok {
+		return auth.ErrUnauthenticated
+	}
+	return auth.ErrForbidden
+}
+
+// authorizeEach — bulk: từ chối 1 record = từ chối cả batch
+func (s *BaseService[T, K]) authorizeEach(ctx context.Context, records []T, allowed func(p *auth.Principal, record *T) bool) error {
+	for i := range records {
+		if err := s.authorize(ctx, func(p *auth.Principal) bool { return allowed(p, &records[i]) }); err != nil {
 			return err
 		}
 	}
+	return nil
+}
 
-	if err := s.br.Update(id, payload); err != nil {
+// write — chạy pipeline ghi (hook + thao tác chính)
+// Transactional mode → bọc trong transaction, kèm outbox event nếu bật
+// ctx có Precondition → luôn bọc transaction (lock record từ lúc kiểm tra tới lúc ghi)
+// fn nhận tx (đã gắn ctx) để truyền xuống hook, trả về các event cần ghi (bulk: 1 event / record)
+func (s *BaseService[T, K]) write(ctx context.Context, fn func(tx *gorm.DB, repo *r.BaseRepository[T, K]) ([]outbox.Event, error)) error {
+	_, conditional := si.PreconditionFrom[T](ctx)
+	return s.writeIn(ctx, s.transactional || s.aggregateType != "" || conditional, fn)
+}
+
+// writeIn — như write, inTx = false → chạy thẳng trên DB gốc
+// Upsert gọi với inTx = true: tra record hiện tại và ghi phải nằm trong cùng 1 transaction
+func (s *BaseService[T, K]) writeIn(ctx context.Context, inTx bool, fn func(tx *gorm.DB, repo *r.BaseRepository[T, K]) ([]outbox.Event, error)) error {
+	db := s.br.DB.WithContext(ctx)
+	if !inTx {
+		_, err := fn(db, s.br.WithTx(db))
 		return err
 	}
 
-	if s.hook != nil {
-		return s.hook.AfterUpdate(id, payload)
+	return db.Transaction(func(tx *gorm.DB) error {
+		events, err := fn(tx, s.br.WithTx(tx))
+		if err != nil {
+			return err
+		}
+		if s.aggregateType == "" {
+			return nil
+		}
+		return outbox.Enqueue(tx, events...)
+	})
+}
+
+// event — dựng outbox event theo quy ước "{aggregate}.{action}"
+func (s *BaseService[T, K]) event(action string, id K, payload any) outbox.Event {
+	return outbox.Event{
+		AggregateType: s.aggregateType,
+		AggregateID:   fmt.Sprint(id),
+		EventType:     s.aggregateType + "." + action,
+		Payload:       payload,
 	}
-	return nil
 }
 
-func (s *BaseService[T]) Delete(id uint) error {
-	if s.hook != nil {
-		if err := s.hook.BeforeDelete(id); err != nil {
+// idEvents — 1 event "{aggregate}.{action}" cho mỗi id (bulk delete / restore), payload giống Delete
+func (s *BaseService[T, K]) idEvents(action string, ids []K) []outbox.Event {
+	if s.aggregateType == "" {
+		return nil
+	}
+	events := make([]outbox.Event, len(ids))
+	for i, id := range ids {
+		events[i] = s.event(action, id, map[string]any{"id": id})
+	}
+	return events
+}
+
+// ============================================================
+// SINGLE ACTIONS — Có áp dụng Hook Pipeline
+//
+//	[BEGIN] → precondition → policy → Before* → validate → repo → After* → outbox → [COMMIT] → After*Commit → domain event
+//
+// precondition: chỉ Update / Replace / Delete khi ctx có si.Precondition (If-Match)
+// ============================================================
+
+func (s *BaseService[T, K]) Create(ctx context.Context, payload *T) error {
+	return s.intercept(ctx, "Create", func() error {
+		// 0. Phân quyền
+		if err := s.authorize(ctx, func(p *auth.Principal) bool {
+			return s.policy.CanCreate(ctx, p, payload)
+		}); err != nil {
 			return err
 		}
-	}
 
-	if err := s.br.Delete(id); err != nil {
-		return err
-	}
+		if err := s.write(ctx, func(tx *gorm.DB, repo *r.BaseRepository[T, K]) ([]outbox.Event, error) {
+			// 1. Hook Before
+			if err := runHooks(s.hooks, func(h si.IBeforeCreate[T]) error {
+				return h.BeforeCreate(tx, payload)
+			}); err != nil {
+				return nil, err // Dừng sớm nếu validate/logic trước khi tạo fail
+			}
 
-	if s.hook != nil {
-		return s.hook.AfterDelete(id)
-	}
-	return nil
+			// 2. Validate theo tag
+			if err := s.validate(ctx, repo, payload, nil, false); err != nil {
+				return nil, err
+			}
+
+			// 3. Action chính
+			if err := repo.Create(payload); err != nil {
+				return nil, err
+			}
+
+			// 4. Hook After — transactional mode: lỗi ở đây rollback cả record vừa insert
+			if err := runHooks(s.hooks, func(h si.IAfterCreate[T]) error {
+				return h.AfterCreate(tx, payload)
+			}); err != nil {
+				return nil, err
+			}
+
+			id, err := repo.IDOf(payload)
+			if err != nil {
+				return nil, err
+			}
+			return []outbox.Event{s.event("created", id, payload)}, nil
+		}); err != nil {
+			return err
+		}
+
+		// 5. Sau commit
+		notifyHooks(s.hooks, func(h si.IAfterCreateCommit[T]) { h.AfterCreateCommit(ctx, payload) })
+
+		// 6. Domain event
+		s.publishCreated(ctx, payload)
+		return nil
+	})
+}
+
+// Update — partial update, field zero value bị bỏ qua (xem BaseRepository.Update)
+func (s *BaseService[T, K]) Update(ctx context.Context, id K, payload *T) error {
+	return s.update(ctx, "Update", id, payload, true)
+}
+
+// Replace — ghi đè toàn bộ record KỂ CẢ zero values (HTTP PUT), validate đầy đủ như Create
+// Hook + policy + event giống Update
+func (s *BaseService[T, K]) Replace(ctx context.Context, id K, payload *T) error {
+	return s.update(ctx, "Replace", id, payload, false)
+}
+
+func (s *BaseService[T, K]) update(ctx context.Context, method string, id K, payload *T, partial bool) error {
+	return s.intercept(ctx, method, func() error {
+		if err := s.write(ctx, func(tx *gorm.DB, repo *r.BaseRepository[T, K]) ([]outbox.Event, error) {
+			// If-Match / optimistic lock — trước mọi thứ khác, record đã đổi thì không cần chạy tiếp
+			if err := s.checkPrecondition(ctx, tx, repo, id); err != nil {
+				return nil, err
+			}
+
+			// Policy cần record hiện tại (VD: chỉ chủ sở hữu được sửa)
+			if s.policy != nil {
+				current, err := repo.FindById(id, nil)
+				if err != nil {
+					return nil, err
+				}
+				// current == nil → không tồn tại, để repo.Update trả lỗi not found
+				if current != nil {
+					if err := s.authorize(ctx, func(p *auth.Principal) bool {
+						return s.policy.CanUpdate(ctx, p, current, payload)
+					}); err != nil {
+						return nil, err
+					}
+				}
+			}
+
+			if err := runHooks(s.hooks, func(h si.IBeforeUpdate[T, K]) error {
+				return h.BeforeUpdate(tx, id, payload)
+			}); err != nil {
+				return nil, err
+			}
+
+			if err := s.validate(ctx, repo, payload, &id, partial); err != nil {
+				return nil, err
+			}
+
+			save := repo.Update
+			if !partial {
+				save = repo.Replace
+			}
+			if err := save(id, payload); err != nil {
+				return nil, err
+			}
+
+			if err := runHooks(s.hooks, func(h si.IAfterUpdate[T, K]) error {
+				return h.AfterUpdate(tx, id, payload)
+			}); err != nil {
+				return nil, err
+			}
+			return []outbox.Event{s.event("updated", id, payload)}, nil
+		}); err != nil {
+			return err
+		}
+
+		notifyHooks(s.hooks, func(h si.IAfterUpdateCommit[T, K]) { h.AfterUpdateCommit(ctx, id, payload) })
+
+		event.Publish(ctx, s.bus, event.EntityUpdated[T, K]{ID: id, Entity: payload})
+		return nil
+	})
+}
+
+func (s *BaseService[T, K]) Delete(ctx context.Context, id K) error {
+	return s.intercept(ctx, "Delete", func() error {
+		if err := s.write(ctx, func(tx *gorm.DB, repo *r.BaseRepository[T, K]) ([]outbox.Event, error) {
+			if err := s.checkPrecondition(ctx, tx, repo, id); err != nil {
+				return nil, err
+			}
+
+			if s.policy != nil {
+				current, err := repo.FindById(id, nil)
+				if err != nil {
+					return nil, err
+				}
+				if current != nil {
+					if err := s.authorize(ctx, func(p *auth.Principal) bool {
+						return s.policy.CanDelete(ctx, p, current)
+					}); err != nil {
+						return nil, err
+					}
+				}
+			}
+
+			if err := runHooks(s.hooks, func(h si.IBeforeDelete[K]) error {
+				return h.BeforeDelete(tx, id)
+			}); err != nil {
+				return nil, err
+			}
+
+			if err := repo.Delete(id); err != nil {
+				return nil, err
+			}
+
+			if err := runHooks(s.hooks, func(h si.IAfterDelete[K]) error {
+				return h.AfterDelete(tx, id)
+			}); err != nil {
+				return nil, err
+			}
+			return []outbox.Event{s.event("deleted", id, map[string]any{"id": id})}, nil
+		}); err != nil {
+			return err
+		}
+
+		notifyHooks(s.hooks, func(h si.IAfterDeleteCommit[K]) { h.AfterDeleteCommit(ctx, id) })
+
+		event.Publish(ctx, s.bus, event.EntityDeleted[T, K]{ID: id})
+		return nil
+	})
 }
 
 // ============================================================
 // BULK ACTIONS — Dành riêng cho hiệu suất
-// Không nên chạy bulk qua Single Hook vì sẽ lặp vòng for rất chậm.
+// Không chạy bulk qua Single Hook vì sẽ lặp vòng for rất chậm.
+// → Hook implement IBeforeBulk* / IAfterBulk* để can thiệp 1 lần cho cả batch
+//
+//	[BEGIN] → policy → BeforeBulk* → repo → AfterBulk* → outbox (1 event / record) → [COMMIT] → domain event
+//
+// Có policy → phải load các record bị ảnh hưởng để hỏi từng cái (thêm 1 query)
 // ============================================================
 
-func (s *BaseService[T]) BulkCreate(payloads []T) error {
-	// Delegate việc batch size (vd: 500) xuống cho DB xử lý an toàn
-	return s.br.InsertInBatches(payloads, 500)
+func (s *BaseService[T, K]) BulkCreate(ctx context.Context, payloads []T) error {
+	if len(payloads) == 0 {
+		return nil
+	}
+
+	return s.intercept(ctx, "BulkCreate", func() error {
+		if err := s.authorizeEach(ctx, payloads, func(p *auth.Principal, payload *T) bool {
+			return s.policy.CanCreate(ctx, p, payload)
+		}); err != nil {
+			return err
+		}
+
+		if err := s.write(ctx, func(tx *gorm.DB, repo *r.BaseRepository[T, K]) ([]outbox.Event, error) {
+			if err := runHooks(s.hooks, func(h si.IBeforeBulkCreate[T]) error {
+				return h.BeforeBulkCreate(tx, payloads)
+			}); err != nil {
+				return nil, err
+			}
+
+			// Validate từng phần tử, lỗi gom chung: "[3].name"
+			// Lưu ý: unique chỉ so với data ĐÃ có trong DB, 2 phần tử trùng nhau trong batch → DB unique index chặn
+			if !s.skipValidation {
+				if err := validation.Each(s.uniqueContext(ctx, repo, nil), payloads); err != nil {
+					return nil, err
+				}
+			}
+
+			// Delegate việc batch size (vd: 500) xuống cho DB xử lý an toàn
+			if err := repo.InsertInBatches(payloads, 500); err != nil {
+				return nil, err
+			}
+
+			if err := runHooks(s.hooks, func(h si.IAfterBulkCreate[T]) error {
+				return h.AfterBulkCreate(tx, payloads)
+			}); err != nil {
+				return nil, err
+			}
+			if s.aggregateType == "" {
+				return nil, nil
+			}
+
+			// GORM đã gán ID vào từng phần tử → 1 event created / record, giống Create
+			events := make([]outbox.Event, len(payloads))
+			for i := range payloads {
+				id, err := repo.IDOf(&payloads[i])
+				if err != nil {
+					return nil, err
+				}
+				events[i] = s.event("created", id, &payloads[i])
+			}
+			return events, nil
+		}); err != nil {
+			return err
+		}
+
+		// GORM đã gán ID vào từng phần tử → publish từng record
+		for i := range payloads {
+			s.publishCreated(ctx, &payloads[i])
+		}
+		return nil
+	})
+}
+
+func (s *BaseService[T, K]) BulkUpdate(ctx context.Context, conditions map[string]any, payload map[string]any) (int64, error) {
+	var affected int64
+
+	err := s.intercept(ctx, "BulkUpdate", func() error {
+		if err := s.write(ctx, func(tx *gorm.DB, repo *r.BaseRepository[T, K]) ([]outbox.Event, error) {
+			// Policy / outbox cần biết record nào bị ảnh hưởng → load trước khi update (điều kiện có thể đổi sau update)
+			var records []T
+			if s.policy != nil || s.aggregateType != "" {
+				var err error
+				if records, err = repo.FindAllByFields(conditions); err != nil {
+					return nil, err
+				}
+			}
+			if s.policy != nil {
+				if err := s.authorizeEach(ctx, records, func(p *auth.Principal, current *T) bool {
+					return s.policy.CanUpdate(ctx, p, current, nil)
+				}); err != nil {
+					return nil, err
+				}
+			}
+
+			if err := runHooks(s.hooks, func(h si.IBeforeBulkUpdate) error {
+				return h.BeforeBulkUpdate(tx, conditions, payload)
+			}); err != nil {
+				return nil, err
+			}
+
+			var err error
+			if affected, err = repo.BulkUpdateFields(conditions, payload); err != nil {
+				return nil, err
+			}
+
+			if err := runHooks(s.hooks, func(h si.IAfterBulkUpdate) error {
+				return h.AfterBulkUpdate(tx, conditions, payload, affected)
+			}); err != nil {
+				return nil, err
+			}
+			if s.aggregateType == "" {
+				return nil, nil
+			}
+
+			// 1 event updated / record: payload = id + các field đã đổi
+			events := make([]outbox.Event, len(records))
+			for i := range records {
+				id, err := repo.IDOf(&records[i])
+				if err != nil {
+					return nil, err
+				}
+				events[i] = s.event("updated", id, map[string]any{"id": id, "fields": payload})
+			}
+			return events, nil
+		}); err != nil {
+			return err
+		}
+
+		event.Publish(ctx, s.bus, event.EntityUpdated[T, K]{
+			Conditions: conditions,
+			Fields:     payload,
+			Affected:   affected,
+		})
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return affected, nil
+}
+
+// Upsert — insert hoặc update theo conflictColumns (xem BaseRepository.Upsert)
+// Tra + lock record trùng conflictColumns trước để biết nhánh nào, rồi chạy pipeline tương ứng:
+// → chưa có: CanCreate + BeforeCreate → validate → upsert → AfterCreate → AfterCreateCommit
+// → đã có:   CanUpdate + BeforeUpdate → validate (unique bỏ qua chính record đó) → upsert → AfterUpdate → AfterUpdateCommit
+//
+// Luôn chạy trong transaction (không phụ thuộc UseTransaction): record có thể xuất hiện giữa lúc tra và lúc ghi
+// Outbox / domain event vẫn là "upserted" cho cả 2 nhánh
+func (s *BaseService[T, K]) Upsert(ctx context.Context, payload *T, conflictColumns []string, updateColumns []string) error {
+	return s.intercept(ctx, "Upsert", func() error {
+		var id K
+		var existed bool
+		if err := s.writeIn(ctx, true, func(tx *gorm.DB, repo *r.BaseRepository[T, K]) ([]outbox.Event, error) {
+			current, err := repo.FindConflictForUpdate(tx, payload, conflictColumns)
+			if err != nil {
+				return nil, err
+			}
+			existed = current != nil
+
+			if existed {
+				if id, err = repo.IDOf(current); err != nil {
+					return nil, err
+				}
+				if err := s.authorize(ctx, func(p *auth.Principal) bool {
+					return s.policy.CanUpdate(ctx, p, current, payload)
+				}); err != nil {
+					return nil, err
+				}
+				if err := runHooks(s.hooks, func(h si.IBeforeUpdate[T, K]) error {
+					return h.BeforeUpdate(tx, id, payload)
+				}); err != nil {
+					return nil, err
+				}
+				if err := s.validate(ctx, repo, payload, &id, false); err != nil {
+					return nil, err
+				}
+			} else {
+				if err := s.authorize(ctx, func(p *auth.Principal) bool {
+					return s.policy.CanCreate(ctx, p, payload)
+				}); err != nil {
+					return nil, err
+				}
+				if err := runHooks(s.hooks, func(h si.IBeforeCreate[T]) error {
+					return h.BeforeCreate(tx, payload)
+				}); err != nil {
+					return nil, err
+				}
+				if err := s.validate(ctx, repo, payload, nil, false); err != nil {
+					return nil, err
+				}
+			}
+
+			if err := repo.Upsert(payload, conflictColumns, updateColumns); err != nil {
+				return nil, err
+			}
+
+			if existed {
+				err = runHooks(s.hooks, func(h si.IAfterUpdate[T, K]) error {
+					return h.AfterUpdate(tx, id, payload)
+				})
+			} else {
+				// Insert → GORM đã gán ID vào payload
+				if id, err = repo.IDOf(payload); err != nil {
+					return nil, err
+				}
+				err = runHooks(s.hooks, func(h si.IAfterCreate[T]) error {
+					return h.AfterCreate(tx, payload)
+				})
+			}
+			if err != nil {
+				return nil, err
+			}
+			return []outbox.Event{s.event("upserted", id, payload)}, nil
+		}); err != nil {
+			return err
+		}
+
+		if existed {
+			notifyHooks(s.hooks, func(h si.IAfterUpdateCommit[T, K]) { h.AfterUpdateCommit(ctx, id, payload) })
+		} else {
+			notifyHooks(s.hooks, func(h si.IAfterCreateCommit[T]) { h.AfterCreateCommit(ctx, payload) })
+		}
+
+		event.Publish(ctx, s.bus, event.EntityUpserted[T, K]{ID: id, Entity: payload})
+		return nil
+	})
+}
+
+func (s *BaseService[T, K]) BulkDelete(ctx context.Context, ids []K) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return s.intercept(ctx, "BulkDelete", func() error {
+		var deleted []K
+		if err := s.write(ctx, func(tx *gorm.DB, repo *r.BaseRepository[T, K]) ([]outbox.Event, error) {
+			// Policy / outbox / bus cần biết record nào thực sự bị xóa
+			// → id không tồn tại hoặc đã xóa mềm trước đó không sinh event
+			if s.policy != nil || s.aggregateType != "" || s.bus != nil {
+				records, err := repo.FindByIds(ids)
+				if err != nil {
+					return nil, err
+				}
+				if err := s.authorizeEach(ctx, records, func(p *auth.Principal, record *T) bool {
+					return s.policy.CanDelete(ctx, p, record)
+				}); err != nil {
+					return nil, err
+				}
+				if deleted, err = idsOf(repo, records); err != nil {
+					return nil, err
+				}
+			}
+
+			if err := runHooks(s.hooks, func(h si.IBeforeBulkDelete[K]) error {
+				return h.BeforeBulkDelete(tx, ids)
+			}); err != nil {
+				return nil, err
+			}
+
+			if err := repo.BulkDelete(ids); err != nil {
+				return nil, err
+			}
+
+			if err := runHooks(s.hooks, func(h si.IAfterBulkDelete[K]) error {
+				return h.AfterBulkDelete(tx, ids)
+			}); err != nil {
+				return nil, err
+			}
+			return s.idEvents("deleted", deleted), nil
+		}); err != nil {
+			return err
+		}
+
+		for _, id := range deleted {
+			event.Publish(ctx, s.bus, event.EntityDeleted[T, K]{ID: id})
+		}
+		return nil
+	})
+}
+
+// BulkRestore — Policy: hỏi CanDelete (ai được xóa thì được khôi phục)
+func (s *BaseService[T, K]) BulkRestore(ctx context.Context, ids []K) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	var affected int64
+
+	err := s.intercept(ctx, "BulkRestore", func() error {
+		if err := s.write(ctx, func(tx *gorm.DB, repo *r.BaseRepository[T, K]) ([]outbox.Event, error) {
+			if s.policy != nil {
+				records, err := repo.FindTrashedByIds(ids)
+				if err != nil {
+					return nil, err
+				}
+				if err := s.authorizeEach(ctx, records, func(p *auth.Principal, record *T) bool {
+					return s.policy.CanDelete(ctx, p, record)
+				}); err != nil {
+					return nil, err
+				}
+			}
+
+			if err := runHooks(s.hooks, func(h si.IBeforeBulkRestore[K]) error {
+				return h.BeforeBulkRestore(tx, ids)
+			}); err != nil {
+				return nil, err
+			}
+
+			var err error
+			if affected, err = repo.BulkRestore(ids); err != nil {
+				return nil, err
+			}
+
+			if err := runHooks(s.hooks, func(h si.IAfterBulkRestore[K]) error {
+				return h.AfterBulkRestore(tx, ids, affected)
+			}); err != nil {
+				return nil, err
+			}
+			return s.idEvents("restored", ids), nil
+		}); err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			event.Publish(ctx, s.bus, event.EntityRestored[T, K]{ID: id})
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return affected, nil
+}
+
+// idsOf — primary key của từng record
+func idsOf[T any, K comparable](repo *r.BaseRepository[T, K], records []T) ([]K, error) {
+	ids := make([]K, len(records))
+	for i := range records {
+		id, err := repo.IDOf(&records[i])
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+	}
+	return ids, nil
 }
 
-func (s *BaseService[T]) BulkUpdate(conditions map[string]any, payload map[string]any) (int64, error) {
-	return s.br.BulkUpdateFields(conditions, payload)
+// publishCreated — publish EntityCreated kèm ID đọc từ record
+func (s *BaseService[T, K]) publishCreated(ctx context.Context, payload *T) {
+	if s.bus == nil {
+		return
+	}
+	id, _ := s.br.IDOf(payload)
+	event.Publish(ctx, s.bus, event.EntityCreated[T, K]{ID: id, Entity: payload})
 }
 
-func (s *BaseService[T]) FindById(id uint) (*T, error) {
-	return s.br.FindById(id, nil)
+// ============================================================
+// READ
+// ============================================================
+
+func (s *BaseService[T, K]) FindById(ctx context.Context, id K) (*T, error) {
+	var record *T
+	err := s.intercept(ctx, "FindById", func() (err error) {
+		if record, err = s.br.WithTx(s.br.DB.WithContext(ctx)).FindById(id, nil); err != nil || record == nil {
+			return err // nil, nil = không tìm thấy, giống BaseRepository.FindById
+		}
+		return s.authorize(ctx, func(p *auth.Principal) bool {
+			return s.policy.CanView(ctx, p, record)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
 }
 
-func (s *BaseService[T]) Paginate(specs common.Specs) (*common.PaginateResult[T], error) {
-	return s.br.Paginate(specs)
+// Paginate — có policy → chỉ trả về record trong Scope của principal
+// Scope nil = không giới hạn (mọi record), CanView KHÔNG được hỏi cho từng record trong trang
+func (s *BaseService[T, K]) Paginate(ctx context.Context, specs common.Specs) (*common.PaginateResult[T], error) {
+	var result *common.PaginateResult[T]
+	err := s.intercept(ctx, "Paginate", func() (err error) {
+		repo := s.br.WithTx(s.br.DB.WithContext(ctx))
+		if s.policy != nil {
+			p, _ := auth.PrincipalFrom(ctx)
+			if scope := s.policy.Scope(ctx, p); scope != nil {
+				repo = repo.WithScopes(scope)
+			}
+		}
+		result, err = repo.Paginate(specs)
+		return err
+	})
+	return result, err
 }
@@ -0,0 +1,693 @@
+package impl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang-base/global/common"
+	"golang-base/internal/auth"
+	"golang-base/internal/event"
+	"golang-base/internal/model"
+	r "golang-base/internal/repository"
+	"golang-base/pkg/validation"
+
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testNote — model mẫu, có validate + soft delete, slug unique dùng làm conflict column của Upsert
+type testNote struct {
+	ID        uint   `json:"id"`
+	Slug      string `json:"slug" gorm:"uniqueIndex;default:null"`
+	Title     string `json:"title" validate:"required"`
+	Body      string `json:"body"`
+	DeletedAt gorm.DeletedAt
+}
+
+// testAudit — bảng phụ, hook ghi thêm qua tx để kiểm tra rollback cùng thao tác chính
+type testAudit struct {
+	ID     uint
+	Action string
+}
+
+// recorder — implement đủ hook, ghi lại tên hook theo thứ tự gọi
+// fail[tên hook] != nil → hook đó trả lỗi
+type recorder struct {
+	calls []string
+	fail  map[string]error
+}
+
+func (h *recorder) call(name string) error {
+	h.calls = append(h.calls, name)
+	return h.fail[name]
+}
+
+func (h *recorder) BeforeCreate(tx *gorm.DB, p *testNote) error {
+	return h.call("BeforeCreate")
+}
+
+func (h *recorder) AfterCreate(tx *gorm.DB, p *testNote) error {
+	// Ghi thêm qua tx → phải rollback cùng record nếu pipeline lỗi
+	if err := tx.Create(&testAudit{Action: "created"}).Error; err != nil {
+		return err
+	}
+	return h.call("AfterCreate")
+}
+
+func (h *recorder) BeforeUpdate(tx *gorm.DB, id uint, p *testNote) error {
+	return h.call("BeforeUpdate")
+}
+
+func (h *recorder) AfterUpdate(tx *gorm.DB, id uint, p *testNote) error {
+	return h.call("AfterUpdate")
+}
+
+func (h *recorder) BeforeDelete(tx *gorm.DB, id uint) error {
+	return h.call("BeforeDelete")
+}
+
+func (h *recorder) AfterDelete(tx *gorm.DB, id uint) error {
+	return h.call("AfterDelete")
+}
+
+func (h *recorder) AfterCreateCommit(ctx context.Context, p *testNote) {
+	_ = h.call("AfterCreateCommit")
+}
+
+func (h *recorder) AfterUpdateCommit(ctx context.Context, id uint, p *testNote) {
+	_ = h.call("AfterUpdateCommit")
+}
+
+func (h *recorder) AfterDeleteCommit(ctx context.Context, id uint) {
+	_ = h.call("AfterDeleteCommit")
+}
+
+func (h *recorder) BeforeBulkCreate(tx *gorm.DB, payloads []testNote) error {
+	return h.call(fmt.Sprintf("BeforeBulkCreate(%d)", len(payloads)))
+}
+
+func (h *recorder) AfterBulkCreate(tx *gorm.DB, payloads []testNote) error {
+	return h.call(fmt.Sprintf("AfterBulkCreate(%d)", len(payloads)))
+}
+
+func (h *recorder) BeforeBulkUpdate(tx *gorm.DB, conditions, fields map[string]any) error {
+	return h.call("BeforeBulkUpdate")
+}
+
+func (h *recorder) AfterBulkUpdate(tx *gorm.DB, conditions, fields map[string]any, affected int64) error {
+	return h.call(fmt.Sprintf("AfterBulkUpdate(%d)", affected))
+}
+
+func (h *recorder) BeforeBulkDelete(tx *gorm.DB, ids []uint) error {
+	return h.call(fmt.Sprintf("BeforeBulkDelete%v", ids))
+}
+
+func (h *recorder) AfterBulkDelete(tx *gorm.DB, ids []uint) error {
+	return h.call(fmt.Sprintf("AfterBulkDelete%v", ids))
+}
+
+func (h *recorder) BeforeBulkRestore(tx *gorm.DB, ids []uint) error {
+	return h.call(fmt.Sprintf("BeforeBulkRestore%v", ids))
+}
+
+func (h *recorder) AfterBulkRestore(tx *gorm.DB, ids []uint, affected int64) error {
+	return h.call(fmt.Sprintf("AfterBulkRestore(%d)", affected))
+}
+
+// newTestDB — sqlite in-memory riêng cho từng test, đã migrate testNote + testAudit + outbox
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", strings.ReplaceAll(t.Name(), "/", "_"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Discard, TranslateError: true})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	if err := db.AutoMigrate(&testNote{}, &testAudit{}, &model.OutboxEvent{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func newNoteService(t *testing.T, hooks ...any) (*BaseService[testNote, uint], *gorm.DB) {
+	t.Helper()
+	db := newTestDB(t)
+	return NewBaseService(r.NewBaseRepository[testNote, uint](db), hooks...), db
+}
+
+func seedNotes(t *testing.T, db *gorm.DB, titles ...string) []testNote {
+	t.Helper()
+	notes := make([]testNote, len(titles))
+	for i, title := range titles {
+		notes[i] = testNote{Slug: title, Title: title}
+	}
+	if err := db.Create(&notes).Error; err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	return notes
+}
+
+func count(t *testing.T, db *gorm.DB, model any) int64 {
+	t.Helper()
+	var n int64
+	if err := db.Model(model).Count(&n).Error; err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	return n
+}
+
+// outboxEvents — "event_type aggregate_id" theo thứ tự ghi
+func outboxEvents(t *testing.T, db *gorm.DB) []string {
+	t.Helper()
+	var rows []model.OutboxEvent
+	if err := db.Order("id").Find(&rows).Error; err != nil {
+		t.Fatalf("load outbox: %v", err)
+	}
+	out := make([]string, len(rows))
+	for i, row := range rows {
+		out[i] = row.EventType + " " + row.AggregateID
+	}
+	return out
+}
+
+var errHook = errors.New("hook failed")
+
+func TestTransactionRollsBackOnAfterHookError(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("create", func(t *testing.T) {
+		hook := &recorder{fail: map[string]error{"AfterCreate": errHook}}
+		s, db := newNoteService(t, hook)
+		s.UseTransaction()
+
+		if err := s.Create(ctx, &testNote{Title: "draft"}); !errors.Is(err, errHook) {
+			t.Fatalf("expected hook error, got %v", err)
+		}
+		// Record + audit do hook ghi qua tx đều bị rollback, after-commit không chạy
+		if count(t, db, &testNote{}) != 0 || count(t, db, &testAudit{}) != 0 {
+			t.Fatal("after hook error must roll back the insert and the hook's own writes")
+		}
+		if !slices.Equal(hook.calls, []string{"BeforeCreate", "AfterCreate"}) {
+			t.Fatalf("unexpected hook calls: %v", hook.calls)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		hook := &recorder{fail: map[string]error{"AfterUpdate": errHook}}
+		s, db := newNoteService(t, hook)
+		s.UseTransaction()
+		note := seedNotes(t, db, "draft")[0]
+
+		if err := s.Update(ctx, note.ID, &testNote{Title: "final"}); !errors.Is(err, errHook) {
+			t.Fatalf("expected hook error, got %v", err)
+		}
+		var got testNote
+		db.First(&got, note.ID)
+		if got.Title != "draft" || slices.Contains(hook.calls, "AfterUpdateCommit") {
+			t.Fatalf("update must roll back, got %q, calls %v", got.Title, hook.calls)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		hook := &recorder{fail: map[string]error{"AfterDelete": errHook}}
+		s, db := newNoteService(t, hook)
+		s.UseTransaction()
+		note := seedNotes(t, db, "draft")[0]
+
+		if err := s.Delete(ctx, note.ID); !errors.Is(err, errHook) {
+			t.Fatalf("expected hook error, got %v", err)
+		}
+		if count(t, db, &testNote{}) != 1 || slices.Contains(hook.calls, "AfterDeleteCommit") {
+			t.Fatalf("delete must roll back, calls %v", hook.calls)
+		}
+	})
+}
+
+func TestWithoutTransactionAfterHookErrorKeepsWrite(t *testing.T) {
+	// Mode thường: mỗi bước tự commit → lỗi After* không gỡ được record đã insert
+	hook := &recorder{fail: map[string]error{"AfterCreate": errHook}}
+	s, db := newNoteService(t, hook)
+
+	if err := s.Create(context.Background(), &testNote{Title: "draft"}); !errors.Is(err, errHook) {
+		t.Fatalf("expected hook error, got %v", err)
+	}
+	if count(t, db, &testNote{}) != 1 || slices.Contains(hook.calls, "AfterCreateCommit") {
+		t.Fatalf("expected the insert to stay and after-commit to be skipped, calls %v", hook.calls)
+	}
+}
+
+func TestAfterCommitHooksRunAfterCommit(t *testing.T) {
+	ctx := context.Background()
+	hook := &recorder{}
+	s, db := newNoteService(t, hook)
+	s.UseTransaction()
+
+	// Bus subscriber chạy sau after-commit hook, lúc đó data đã thấy được từ ngoài transaction
+	var visible []int64
+	bus := event.NewBus(zap.NewNop())
+	s.WithEventBus(bus)
+	event.Subscribe(bus, "count", func(ctx context.Context, e event.EntityCreated[testNote, uint]) error {
+		hook.calls = append(hook.calls, "EntityCreated")
+		visible = append(visible, count(t, db, &testNote{}))
+		return nil
+	})
+
+	note := &testNote{Title: "draft"}
+	if err := s.Create(ctx, note); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := s.Update(ctx, note.ID, &testNote{Title: "final"}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if err := s.Delete(ctx, note.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+
+	want := []string{
+		"BeforeCreate", "AfterCreate", "AfterCreateCommit", "EntityCreated",
+		"BeforeUpdate", "AfterUpdate", "AfterUpdateCommit",
+		"BeforeDelete", "AfterDelete", "AfterDeleteCommit",
+	}
+	if !slices.Equal(hook.calls, want) {
+		t.Fatalf("expected\n%v\ngot\n%v", want, hook.calls)
+	}
+	if !slices.Equal(visible, []int64{1}) {
+		t.Fatalf("created record must be committed before domain event, got %v", visible)
+	}
+}
+
+func TestBeforeHookRunsBeforeValidation(t *testing.T) {
+	s, _ := newNoteService(t)
+	s.AddHook(beforeCreateFunc(func(tx *gorm.DB, p *testNote) error {
+		if p.Title == "" {
+			p.Title = "untitled"
+		}
+		return nil
+	}), 0)
+
+	if err := s.Create(context.Background(), &testNote{}); err != nil {
+		t.Fatalf("hook must fill title before validate, got %v", err)
+	}
+	if err := s.DisableValidation().Create(context.Background(), &testNote{Body: "x"}); err != nil {
+		t.Fatalf("disabled validation: %v", err)
+	}
+}
+
+func TestValidationFailureRunsNoWrite(t *testing.T) {
+	hook := &recorder{}
+	s, db := newNoteService(t, hook)
+
+	err := s.Create(context.Background(), &testNote{Body: "no title"})
+	if err == nil || !strings.Contains(err.Error(), "title") {
+		t.Fatalf("expected title validation error, got %v", err)
+	}
+	if count(t, db, &testNote{}) != 0 || !slices.Equal(hook.calls, []string{"BeforeCreate"}) {
+		t.Fatalf("validation error must stop before insert, calls %v", hook.calls)
+	}
+}
+
+func TestOutboxEnqueuedWithWrite(t *testing.T) {
+	ctx := context.Background()
+	hook := &recorder{}
+	s, db := newNoteService(t, hook)
+	s.EnableOutbox("note")
+
+	note := &testNote{Title: "draft"}
+	if err := s.Create(ctx, note); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if err := s.Update(ctx, note.ID, &testNote{Title: "final"}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if err := s.Delete(ctx, note.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	id := fmt.Sprint(note.ID)
+	want := []string{"note.created " + id, "note.updated " + id, "note.deleted " + id}
+	if got := outboxEvents(t, db); !slices.Equal(got, want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	var created model.OutboxEvent
+	db.Where("event_type = ?", "note.created").First(&created)
+	var payload testNote
+	if err := json.Unmarshal([]byte(created.Payload), &payload); err != nil || payload.ID != note.ID || payload.Title != "draft" {
+		t.Fatalf("unexpected created payload %s: %v", created.Payload, err)
+	}
+
+	// Outbox bật = transactional: hook lỗi → không có event nào cho thao tác bị rollback
+	hook.fail = map[string]error{"AfterCreate": errHook}
+	if err := s.Create(ctx, &testNote{Title: "ghost"}); !errors.Is(err, errHook) {
+		t.Fatalf("expected hook error, got %v", err)
+	}
+	if got := outboxEvents(t, db); len(got) != 3 {
+		t.Fatalf("rolled back write must not leave an outbox event, got %v", got)
+	}
+}
+
+// beforeCreateFunc — hook 1 method dựng từ func, dùng cho test
+type beforeCreateFunc func(tx *gorm.DB, p *testNote) error
+
+func (f beforeCreateFunc) BeforeCreate(tx *gorm.DB, p *testNote) error { return f(tx, p) }
+
+// notePolicy — cho phép mọi thứ trừ thao tác có tên trong deny ("view", "create", "update", "delete")
+// scoped → listing chỉ thấy note có slug = principal ID, không scoped → Scope nil
+type notePolicy struct {
+	auth.AllowAll[testNote]
+	deny   string
+	scoped bool
+}
+
+func (p notePolicy) CanView(ctx context.Context, _ *auth.Principal, _ *testNote) bool {
+	return p.deny != "view"
+}
+
+func (p notePolicy) CanCreate(ctx context.Context, _ *auth.Principal, _ *testNote) bool {
+	return p.deny != "create"
+}
+
+func (p notePolicy) CanUpdate(ctx context.Context, _ *auth.Principal, _ *testNote, _ *testNote) bool {
+	return p.deny != "update"
+}
+
+func (p notePolicy) CanDelete(ctx context.Context, _ *auth.Principal, _ *testNote) bool {
+	return p.deny != "delete"
+}
+
+func (p notePolicy) Scope(ctx context.Context, principal *auth.Principal) func(*gorm.DB) *gorm.DB {
+	if !p.scoped {
+		return nil
+	}
+	if principal == nil {
+		return func(db *gorm.DB) *gorm.DB { return db.Where("1 = 0") }
+	}
+	return func(db *gorm.DB) *gorm.DB { return db.Where("slug = ?", principal.ID) }
+}
+
+// upserted — ID của mọi EntityUpserted publish qua bus
+func upserted(s *BaseService[testNote, uint]) *[]uint {
+	var ids []uint
+	bus := event.NewBus(zap.NewNop())
+	s.WithEventBus(bus)
+	event.Subscribe(bus, "ids", func(ctx context.Context, e event.EntityUpserted[testNote, uint]) error {
+		ids = append(ids, e.ID)
+		return nil
+	})
+	return &ids
+}
+
+func TestUpsertInsertRunsCreatePipeline(t *testing.T) {
+	hook := &recorder{}
+	s, db := newNoteService(t, hook)
+	s.EnableOutbox("note")
+	ids := upserted(s)
+
+	note := &testNote{Slug: "go", Title: "Go"}
+	if err := s.Upsert(context.Background(), note, []string{"slug"}, []string{"title"}); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+	if !slices.Equal(hook.calls, []string{"BeforeCreate", "AfterCreate", "AfterCreateCommit"}) {
+		t.Fatalf("expected create hooks, got %v", hook.calls)
+	}
+	if note.ID == 0 || !slices.Equal(*ids, []uint{note.ID}) {
+		t.Fatalf("expected EntityUpserted with new id %d, got %v", note.ID, *ids)
+	}
+	if got := outboxEvents(t, db); !slices.Equal(got, []string{fmt.Sprintf("note.upserted %d", note.ID)}) {
+		t.Fatalf("unexpected outbox events %v", got)
+	}
+}
+
+func TestUpsertConflictRunsUpdatePipeline(t *testing.T) {
+	hook := &recorder{}
+	s, db := newNoteService(t, hook)
+	s.EnableOutbox("note")
+	ids := upserted(s)
+	existing := seedNotes(t, db, "go")[0]
+
+	if err := s.Upsert(context.Background(), &testNote{Slug: "go", Title: "Go 2"}, []string{"slug"}, []string{"title"}); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+	if !slices.Equal(hook.calls, []string{"BeforeUpdate", "AfterUpdate", "AfterUpdateCommit"}) {
+		t.Fatalf("expected update hooks, got %v", hook.calls)
+	}
+	var got testNote
+	db.First(&got, existing.ID)
+	if got.Title != "Go 2" || count(t, db, &testNote{}) != 1 {
+		t.Fatalf("expected existing row to be updated in place, got %+v", got)
+	}
+	// ID lấy từ record đã có, không phụ thuộc DB trả về sau ON CONFLICT
+	if !slices.Equal(*ids, []uint{existing.ID}) {
+		t.Fatalf("expected EntityUpserted with existing id %d, got %v", existing.ID, *ids)
+	}
+	if got := outboxEvents(t, db); !slices.Equal(got, []string{fmt.Sprintf("note.upserted %d", existing.ID)}) {
+		t.Fatalf("unexpected outbox events %v", got)
+	}
+}
+
+func TestUpsertValidates(t *testing.T) {
+	s, db := newNoteService(t)
+	seedNotes(t, db, "go")
+
+	for _, slug := range []string{"go", "rust"} {
+		err := s.Upsert(context.Background(), &testNote{Slug: slug}, []string{"slug"}, []string{"title"})
+		if err == nil || !strings.Contains(err.Error(), "title") {
+			t.Fatalf("%s: expected title validation error, got %v", slug, err)
+		}
+	}
+	var got testNote
+	db.Where("slug = ?", "go").First(&got)
+	if got.Title != "go" || count(t, db, &testNote{}) != 1 {
+		t.Fatalf("invalid upsert must not write, got %+v", got)
+	}
+}
+
+func TestUpsertPolicy(t *testing.T) {
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{ID: "1"})
+
+	t.Run("update denied on conflict", func(t *testing.T) {
+		s, db := newNoteService(t)
+		s.WithPolicy(notePolicy{deny: "update"})
+		seedNotes(t, db, "go")
+
+		err := s.Upsert(ctx, &testNote{Slug: "go", Title: "hijacked"}, []string{"slug"}, []string{"title"})
+		if !errors.Is(err, auth.ErrForbidden) {
+			t.Fatalf("expected ErrForbidden, got %v", err)
+		}
+		var got testNote
+		db.Where("slug = ?", "go").First(&got)
+		if got.Title != "go" {
+			t.Fatalf("denied upsert must not update, got %q", got.Title)
+		}
+		// Chưa có record → nhánh create, chỉ hỏi CanCreate
+		if err := s.Upsert(ctx, &testNote{Slug: "rust", Title: "Rust"}, []string{"slug"}, []string{"title"}); err != nil {
+			t.Fatalf("insert must be allowed: %v", err)
+		}
+	})
+
+	t.Run("create denied", func(t *testing.T) {
+		s, db := newNoteService(t)
+		s.WithPolicy(notePolicy{deny: "create"})
+		seedNotes(t, db, "go")
+
+		err := s.Upsert(ctx, &testNote{Slug: "rust", Title: "Rust"}, []string{"slug"}, []string{"title"})
+		if !errors.Is(err, auth.ErrForbidden) || count(t, db, &testNote{}) != 1 {
+			t.Fatalf("expected ErrForbidden without insert, got %v", err)
+		}
+		if err := s.Upsert(ctx, &testNote{Slug: "go", Title: "Go 2"}, []string{"slug"}, []string{"title"}); err != nil {
+			t.Fatalf("update must be allowed: %v", err)
+		}
+	})
+}
+
+func TestBulkDeleteEmitsOnlyDeletedIDs(t *testing.T) {
+	hook := &recorder{}
+	s, db := newNoteService(t, hook)
+	s.EnableOutbox("note")
+	var published []uint
+	bus := event.NewBus(zap.NewNop())
+	s.WithEventBus(bus)
+	event.Subscribe(bus, "ids", func(ctx context.Context, e event.EntityDeleted[testNote, uint]) error {
+		published = append(published, e.ID)
+		return nil
+	})
+
+	notes := seedNotes(t, db, "go", "rust")
+	db.Delete(&notes[1]) // đã xóa mềm từ trước
+
+	if err := s.BulkDelete(context.Background(), []uint{notes[0].ID, notes[1].ID, 999}); err != nil {
+		t.Fatalf("bulk delete: %v", err)
+	}
+	if !slices.Equal(published, []uint{notes[0].ID}) {
+		t.Fatalf("expected EntityDeleted only for %d, got %v", notes[0].ID, published)
+	}
+	if got := outboxEvents(t, db); !slices.Equal(got, []string{fmt.Sprintf("note.deleted %d", notes[0].ID)}) {
+		t.Fatalf("expected one outbox event, got %v", got)
+	}
+	// Hook vẫn nhận đúng danh sách được yêu cầu
+	want := fmt.Sprintf("BeforeBulkDelete[%d %d 999]", notes[0].ID, notes[1].ID)
+	if len(hook.calls) != 2 || hook.calls[0] != want {
+		t.Fatalf("unexpected hook calls %v", hook.calls)
+	}
+}
+
+func TestBulkHooks(t *testing.T) {
+	ctx := context.Background()
+	hook := &recorder{}
+	s, db := newNoteService(t, hook)
+	s.UseTransaction()
+
+	notes := []testNote{{Slug: "go", Title: "draft"}, {Slug: "rust", Title: "draft"}}
+	if err := s.BulkCreate(ctx, notes); err != nil {
+		t.Fatalf("bulk create: %v", err)
+	}
+	if _, err := s.BulkUpdate(ctx, map[string]any{"title": "draft"}, map[string]any{"body": "x"}); err != nil {
+		t.Fatalf("bulk update: %v", err)
+	}
+	ids := []uint{notes[0].ID, notes[1].ID}
+	if err := s.BulkDelete(ctx, ids); err != nil {
+		t.Fatalf("bulk delete: %v", err)
+	}
+	if _, err := s.BulkRestore(ctx, ids); err != nil {
+		t.Fatalf("bulk restore: %v", err)
+	}
+
+	// 1 lần cho cả batch, không gọi hook đơn lẻ
+	want := []string{
+		"BeforeBulkCreate(2)", "AfterBulkCreate(2)",
+		"BeforeBulkUpdate", "AfterBulkUpdate(2)",
+		fmt.Sprintf("BeforeBulkDelete%v", ids), fmt.Sprintf("AfterBulkDelete%v", ids),
+		fmt.Sprintf("BeforeBulkRestore%v", ids), "AfterBulkRestore(2)",
+	}
+	if !slices.Equal(hook.calls, want) {
+		t.Fatalf("expected\n%v\ngot\n%v", want, hook.calls)
+	}
+	if count(t, db, &testNote{}) != 2 {
+		t.Fatal("expected both notes restored")
+	}
+}
+
+func TestBulkHookErrorRollsBack(t *testing.T) {
+	ctx := context.Background()
+	hook := &recorder{fail: map[string]error{"AfterBulkCreate(2)": errHook, "AfterBulkUpdate(1)": errHook}}
+	s, db := newNoteService(t, hook)
+	s.UseTransaction()
+
+	err := s.BulkCreate(ctx, []testNote{{Slug: "go", Title: "Go"}, {Slug: "rust", Title: "Rust"}})
+	if !errors.Is(err, errHook) || count(t, db, &testNote{}) != 0 {
+		t.Fatalf("expected rollback of the whole batch, got %v", err)
+	}
+
+	seedNotes(t, db, "go")
+	if _, err := s.BulkUpdate(ctx, map[string]any{"slug": "go"}, map[string]any{"title": "Go 2"}); !errors.Is(err, errHook) {
+		t.Fatalf("expected hook error, got %v", err)
+	}
+	var got testNote
+	db.Where("slug = ?", "go").First(&got)
+	if got.Title != "go" {
+		t.Fatalf("bulk update must roll back, got %q", got.Title)
+	}
+}
+
+func TestBulkCreateValidatesEachPayload(t *testing.T) {
+	hook := &recorder{}
+	s, db := newNoteService(t, hook)
+
+	err := s.BulkCreate(context.Background(), []testNote{{Slug: "go", Title: "Go"}, {Slug: "rust"}})
+	var verrs validation.Errors
+	if !errors.As(err, &verrs) || len(verrs) != 1 || verrs[0].Field != "[1].title" {
+		t.Fatalf("expected error on [1].title, got %v", err)
+	}
+	if count(t, db, &testNote{}) != 0 || !slices.Equal(hook.calls, []string{"BeforeBulkCreate(2)"}) {
+		t.Fatalf("invalid batch must not be inserted, calls %v", hook.calls)
+	}
+}
+
+func TestPolicyDenial(t *testing.T) {
+	user := auth.WithPrincipal(context.Background(), &auth.Principal{ID: "1", Kind: auth.KindUser})
+	anonymous := context.Background()
+
+	hook := &recorder{}
+	s, db := newNoteService(t, hook)
+	s.WithPolicy(notePolicy{deny: "view"})
+	note := seedNotes(t, db, "go")[0]
+
+	// Chưa đăng nhập → ErrUnauthenticated, đã đăng nhập → ErrForbidden
+	if _, err := s.FindById(anonymous, note.ID); !errors.Is(err, auth.ErrUnauthenticated) {
+		t.Fatalf("anonymous view: expected ErrUnauthenticated, got %v", err)
+	}
+	if _, err := s.FindById(user, note.ID); !errors.Is(err, auth.ErrForbidden) {
+		t.Fatalf("view: expected ErrForbidden, got %v", err)
+	}
+	// Record không tồn tại → nil, nil như không có policy (không lộ qua lỗi khác nhau)
+	if found, err := s.FindById(user, 999); found != nil || err != nil {
+		t.Fatalf("missing record: expected nil, nil, got %v, %v", found, err)
+	}
+
+	cases := []struct {
+		deny string
+		call func() error
+	}{
+		{"create", func() error { return s.Create(user, &testNote{Slug: "rust", Title: "Rust"}) }},
+		{"create", func() error { return s.BulkCreate(user, []testNote{{Slug: "rust", Title: "Rust"}}) }},
+		{"update", func() error { return s.Update(user, note.ID, &testNote{Title: "x"}) }},
+		{"update", func() error {
+			_, err := s.BulkUpdate(user, map[string]any{"slug": "go"}, map[string]any{"title": "x"})
+			return err
+		}},
+		{"delete", func() error { return s.Delete(user, note.ID) }},
+		{"delete", func() error { return s.BulkDelete(user, []uint{note.ID}) }},
+	}
+	for _, tc := range cases {
+		s.WithPolicy(notePolicy{deny: tc.deny})
+		if err := tc.call(); !errors.Is(err, auth.ErrForbidden) {
+			t.Fatalf("deny %s: expected ErrForbidden, got %v", tc.deny, err)
+		}
+	}
+	// Bị từ chối trước khi chạm DB hay hook
+	var got testNote
+	db.First(&got, note.ID)
+	if got.Title != "go" || count(t, db, &testNote{}) != 1 || len(hook.calls) != 0 {
+		t.Fatalf("denied calls must not write, got %+v, calls %v", got, hook.calls)
+	}
+}
+
+func TestPolicyScope(t *testing.T) {
+	s, db := newNoteService(t)
+	seedNotes(t, db, "1", "2", "3")
+	list := func(ctx context.Context) []string {
+		t.Helper()
+		result, err := s.Paginate(ctx, common.Specs{Sort: "id"})
+		if err != nil {
+			t.Fatalf("paginate: %v", err)
+		}
+		out := make([]string, len(result.Data))
+		for i, n := range result.Data {
+			out[i] = n.Slug
+		}
+		return out
+	}
+	user := auth.WithPrincipal(context.Background(), &auth.Principal{ID: "2", Kind: auth.KindUser})
+
+	s.WithPolicy(notePolicy{scoped: true})
+	if got := list(user); !slices.Equal(got, []string{"2"}) {
+		t.Fatalf("scoped: expected only own note, got %v", got)
+	}
+	if got := list(context.Background()); len(got) != 0 {
+		t.Fatalf("scoped anonymous: expected nothing, got %v", got)
+	}
+
+	// Scope nil = KHÔNG giới hạn: thấy mọi record, kể cả khi CanView từ chối từng record
+	s.WithPolicy(notePolicy{deny: "view"})
+	if got := list(user); !slices.Equal(got, []string{"1", "2", "3"}) {
+		t.Fatalf("nil scope: expected every note, got %v", got)
+	}
+}
@@ -0,0 +1,124 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	si "golang-base/internal/service/interfaces"
+
+	"gorm.io/gorm"
+)
+
+// ============================================================
+// HOOK REGISTRY — đăng ký nhiều hook từng phần, sắp theo priority
+//
+//	service.
+//		AddHook(hooks.Slug[model.Post, uint]("Title", "Slug"), 10).
+//		AddHook(hooks.Audit[model.Post, uint]("post"), 100).
+//		AddHook(&PostService{}, 50)
+//
+// → priority NHỎ chạy TRƯỚC, cùng priority thì theo thứ tự đăng ký
+// → Before* và After* đều chạy theo cùng 1 thứ tự
+// → 1 hook lỗi = dừng chuỗi, các hook sau không chạy
+// ============================================================
+
+// registeredHook — hook kèm priority (sort stable → trùng priority giữ thứ tự đăng ký)
+type registeredHook struct {
+	hook     any
+	priority int
+}
+
+// NoopHook — implement sẵn đủ IHook, trả về nil hết
+// Embed vào module service rồi chỉ override method cần dùng:
+//
+//	type PostService struct {
+//		impl.NoopHook[model.Post, uint]
+//	}
+//	func (s *PostService) BeforeCreate(tx *gorm.DB, p *model.Post) error { ... }
+type NoopHook[T any, K comparable] struct{}
+
+func (NoopHook[T, K]) BeforeCreate(tx *gorm.DB, payload *T) error       { return nil }
+func (NoopHook[T, K]) AfterCreate(tx *gorm.DB, payload *T) error        { return nil }
+func (NoopHook[T, K]) BeforeUpdate(tx *gorm.DB, id K, payload *T) error { return nil }
+func (NoopHook[T, K]) AfterUpdate(tx *gorm.DB, id K, payload *T) error  { return nil }
+func (NoopHook[T, K]) BeforeDelete(tx *gorm.DB, id K) error             { return nil }
+func (NoopHook[T, K]) AfterDelete(tx *gorm.DB, id K) error              { return nil }
+
+// AddHook — đăng ký 1 hook với priority
+// hook phải implement ít nhất 1 interface hook (si.IBeforeCreate, si.IAfterBulkDelete...)
+// → không khớp interface nào = lỗi lập trình, panic ngay lúc khởi động
+func (s *BaseService[T, K]) AddHook(hook any, priority int) *BaseService[T, K] {
+	if hook == nil {
+		return s
+	}
+	if !isHook[T, K](hook) {
+		panic(fmt.Sprintf("service %s: %T does not implement any hook interface", s.entity, hook))
+	}
+
+	s.hooks = append(s.hooks, registeredHook{hook: hook, priority: priority})
+	sort.SliceStable(s.hooks, func(i, j int) bool {
+		return s.hooks[i].priority < s.hooks[j].priority
+	})
+	return s
+}
+
+// Use — thêm interceptor bọc quanh mọi method của service
+func (s *BaseService[T, K]) Use(interceptors ...si.Interceptor) *BaseService[T, K] {
+	s.interceptors = append(s.interceptors, interceptors...)
+	return s
+}
+
+// intercept — chạy fn qua chuỗi interceptor, interceptor đăng ký trước nằm ngoài cùng
+func (s *BaseService[T, K]) intercept(ctx context.Context, method string, fn func() error) error {
+	if len(s.interceptors) == 0 {
+		return fn()
+	}
+
+	call := si.Call{Context: ctx, Entity: s.entity, Method: method}
+	next := fn
+	for i := len(s.interceptors) - 1; i >= 0; i-- {
+		interceptor, inner := s.interceptors[i], next
+		next = func() error { return interceptor(call, inner) }
+	}
+	return next()
+}
+
+// runHooks — gọi fn trên mọi hook implement interface H, theo thứ tự priority
+func runHooks[H any](hooks []registeredHook, fn func(h H) error) error {
+	for _, rh := range hooks {
+		h, ok := rh.hook.(H)
+		if !ok {
+			continue
+		}
+		if err := fn(h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notifyHooks — như runHooks nhưng cho hook không trả error (after-commit)
+func notifyHooks[H any](hooks []registeredHook, fn func(h H)) {
+	for _, rh := range hooks {
+		if h, ok := rh.hook.(H); ok {
+			fn(h)
+		}
+	}
+}
+
+// isHook — hook có implement ít nhất 1 interface hook của [T, K]
+func isHook[T any, K comparable](hook any) bool {
+	switch hook.(type) {
+	case si.IBeforeCreate[T], si.IAfterCreate[T],
+		si.IBeforeUpdate[T, K], si.IAfterUpdate[T, K],
+		si.IBeforeDelete[K], si.IAfterDelete[K],
+		si.IAfterCreateCommit[T], si.IAfterUpdateCommit[T, K], si.IAfterDeleteCommit[K],
+		si.IBeforeBulkCreate[T], si.IAfterBulkCreate[T],
+		si.IBeforeBulkUpdate, si.IAfterBulkUpdate,
+		si.IBeforeBulkDelete[K], si.IAfterBulkDelete[K],
+		si.IBeforeBulkRestore[K], si.IAfterBulkRestore[K]:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package impl
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	si "golang-base/internal/service/interfaces"
+
+	"gorm.io/gorm"
+)
+
+// namedHook — BeforeCreate + AfterCreate ghi tên vào log chung
+type namedHook struct {
+	name string
+	log  *[]string
+}
+
+func (h namedHook) BeforeCreate(tx *gorm.DB, p *testNote) error {
+	*h.log = append(*h.log, "before:"+h.name)
+	return nil
+}
+
+func (h namedHook) AfterCreate(tx *gorm.DB, p *testNote) error {
+	*h.log = append(*h.log, "after:"+h.name)
+	return nil
+}
+
+func TestAddHookPriority(t *testing.T) {
+	var log []string
+	s, _ := newNoteService(t, namedHook{"ctor-1", &log}, namedHook{"ctor-2", &log})
+	s.AddHook(namedHook{"audit", &log}, 100).
+		AddHook(namedHook{"slug", &log}, -10).
+		AddHook(namedHook{"zero", &log}, 0)
+
+	if err := s.Create(context.Background(), &testNote{Title: "Go"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	// Priority nhỏ chạy trước, cùng priority giữ thứ tự đăng ký, Before* và After* cùng 1 thứ tự
+	order := []string{"slug", "ctor-1", "ctor-2", "zero", "audit"}
+	var want []string
+	for _, phase := range []string{"before:", "after:"} {
+		for _, name := range order {
+			want = append(want, phase+name)
+		}
+	}
+	if !slices.Equal(log, want) {
+		t.Fatalf("expected\n%v\ngot\n%v", want, log)
+	}
+}
+
+func TestHookErrorStopsChain(t *testing.T) {
+	var log []string
+	first := &recorder{fail: map[string]error{"BeforeCreate": errHook}}
+	s, db := newNoteService(t)
+	s.AddHook(first, 0).AddHook(namedHook{"later", &log}, 10)
+
+	if err := s.Create(context.Background(), &testNote{Title: "Go"}); !errors.Is(err, errHook) {
+		t.Fatalf("expected hook error, got %v", err)
+	}
+	if len(log) != 0 || count(t, db, &testNote{}) != 0 {
+		t.Fatalf("hooks after a failing one must not run, got %v", log)
+	}
+}
+
+func TestAddHookRejectsNonHook(t *testing.T) {
+	s, _ := newNoteService(t)
+	s.AddHook(nil, 0) // nil → bỏ qua
+
+	defer func() {
+		r := recover()
+		if r == nil || !strings.Contains(r.(string), "does not implement any hook interface") {
+			t.Fatalf("expected panic for non-hook, got %v", r)
+		}
+	}()
+	s.AddHook(struct{}{}, 0)
+}
+
+func TestInterceptors(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("order and call info", func(t *testing.T) {
+		var log []string
+		s, _ := newNoteService(t)
+		trace := func(name string) si.Interceptor {
+			return func(call si.Call, next func() error) error {
+				log = append(log, name+">"+call.Entity+"."+call.Method)
+				err := next()
+				log = append(log, "<"+name)
+				return err
+			}
+		}
+		s.Use(trace("outer"), trace("middle")).Use(trace("inner"))
+
+		if err := s.Create(ctx, &testNote{Title: "Go"}); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		want := []string{"outer>testNote.Create", "middle>testNote.Create", "inner>testNote.Create", "<inner", "<middle", "<outer"}
+		if !slices.Equal(log, want) {
+			t.Fatalf("expected %v, got %v", want, log)
+		}
+	})
+
+	t.Run("block", func(t *testing.T) {
+		errBlocked := errors.New("read only")
+		hook := &recorder{}
+		s, db := newNoteService(t, hook)
+		s.Use(func(call si.Call, next func() error) error {
+			if call.Method != "FindById" {
+				return errBlocked // không gọi next = chặn
+			}
+			return next()
+		})
+
+		if err := s.Create(ctx, &testNote{Title: "Go"}); !errors.Is(err, errBlocked) {
+			t.Fatalf("expected blocked, got %v", err)
+		}
+		if len(hook.calls) != 0 || count(t, db, &testNote{}) != 0 {
+			t.Fatalf("blocked call must not reach the pipeline, calls %v", hook.calls)
+		}
+		if _, err := s.FindById(ctx, 1); err != nil {
+			t.Fatalf("FindById must pass: %v", err)
+		}
+	})
+
+	t.Run("retry", func(t *testing.T) {
+		errDeadlock := errors.New("deadlock")
+		hook := &recorder{fail: map[string]error{"AfterCreate": errDeadlock}}
+		s, db := newNoteService(t, hook)
+		s.UseTransaction()
+
+		attempts := 0
+		s.Use(func(call si.Call, next func() error) error {
+			for {
+				attempts++
+				err := next()
+				if !errors.Is(err, errDeadlock) || attempts == 3 {
+					return err
+				}
+				if attempts == 2 {
+					hook.fail = nil // lần 3 thành công
+				}
+			}
+		})
+
+		if err := s.Create(ctx, &testNote{Title: "Go"}); err != nil {
+			t.Fatalf("expected success after retry, got %v", err)
+		}
+		// 2 lần lỗi đã rollback → chỉ còn 1 record của lần thành công
+		if attempts != 3 || count(t, db, &testNote{}) != 1 || count(t, db, &testAudit{}) != 1 {
+			t.Fatalf("expected 3 attempts and a single committed row, got %d attempts", attempts)
+		}
+	})
+}
@@ -0,0 +1,89 @@
+// Package interceptor — các interceptor dùng lại được cho BaseService
+//
+//	service.Use(
+//		interceptor.Logging(log),
+//		interceptor.Metrics(recorder),
+//		interceptor.Retry(3, 50*time.Millisecond, interceptor.IsDeadlock),
+//	)
+package interceptor
+
+import (
+	"errors"
+	"time"
+
+	si "golang-base/internal/service/interfaces"
+
+	"github.com/go-sql-driver/mysql"
+	"go.uber.org/zap"
+)
+
+// Logging — log mỗi lời gọi service kèm thời gian chạy, lỗi log ở mức Warn
+func Logging(log *zap.Logger) si.Interceptor {
+	return func(call si.Call, next func() error) error {
+		start := time.Now()
+		err := next()
+
+		fields := []zap.Field{
+			zap.String("entity", call.Entity),
+			zap.String("method", call.Method),
+			zap.Duration("duration", time.Since(start)),
+		}
+		if err != nil {
+			log.Warn("service call failed", append(fields, zap.Error(err))...)
+			return err
+		}
+		log.Debug("service call", fields...)
+		return nil
+	}
+}
+
+// Recorder — nơi nhận số liệu (Prometheus, OpenTelemetry, StatsD...)
+// Không ràng buộc thư viện metrics cụ thể, tự viết adapter
+type Recorder interface {
+	ObserveCall(call si.Call, duration time.Duration, err error)
+}
+
+// Metrics — đo thời gian + kết quả mỗi lời gọi
+func Metrics(recorder Recorder) si.Interceptor {
+	return func(call si.Call, next func() error) error {
+		start := time.Now()
+		err := next()
+		recorder.ObserveCall(call, time.Since(start), err)
+		return err
+	}
+}
+
+// Retry — chạy lại cả lời gọi khi lỗi tạm thời (deadlock, lock wait timeout...)
+// attempts: tổng số lần chạy tối đa (tính cả lần đầu)
+// backoff: chờ giữa 2 lần, nhân đôi sau mỗi lần
+// retryable: lỗi nào được retry, nil = IsDeadlock
+//
+// Nên đặt SAU Logging/Metrics để mỗi lời gọi chỉ log 1 lần.
+// Chỉ an toàn khi method chạy trong transaction (UseTransaction / EnableOutbox):
+// lần chạy lỗi đã rollback sạch, không ghi dở dang
+func Retry(attempts int, backoff time.Duration, retryable func(error) bool) si.Interceptor {
+	if retryable == nil {
+		retryable = IsDeadlock
+	}
+	return func(call si.Call, next func() error) error {
+		var err error
+		delay := backoff
+		for attempt := 1; ; attempt++ {
+			if err = next(); err == nil || attempt >= attempts || !retryable(err) {
+				return err
+			}
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+}
+
+// IsDeadlock — MySQL 1213 (deadlock) / 1205 (lock wait timeout)
+// → transaction đã bị rollback, chạy lại thường sẽ thành công
+func IsDeadlock(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1213 || mysqlErr.Number == 1205
+	}
+	return false
+}
@@ -9,34 +9,28 @@
 package interfaces
 
 import (
+	"context"
+
 	"golang-base/global/common"
 )
 
-// IHook — Định nghĩa các điểm neo (hooks) để module con can thiệp vào lồng xử lý
-// Nếu module con không implement, mặc định sẽ chạy logic rỗng (trả về nil).
-type IHook[T any] interface {
-	BeforeCreate(payload *T) error
-	AfterCreate(payload *T) error
-
-	BeforeUpdate(id uint, payload *T) error
-	AfterUpdate(id uint, payload *T) error
-
-	BeforeDelete(id uint) error
-	AfterDelete(id uint) error
-}
-
 // IBaseService — Định nghĩa các hành vi nghiệp vụ dùng chung
-type IBaseService[T any] interface {
-	IHook[T] // Kế thừa toàn bộ hook
+// ctx: mang principal (phân quyền) + deadline của request
+// Hook KHÔNG nằm trong interface: đăng ký qua BaseService.AddHook, service chỉ implement hook cần dùng
+type IBaseService[T any, K comparable] interface {
+	Create(ctx context.Context, payload *T) error
+	BulkCreate(ctx context.Context, payloads []T) error
 
-	Create(payload *T) error
-	BulkCreate(payloads []T) error
+	Update(ctx context.Context, id K, payload *T) error
+	Replace(ctx context.Context, id K, payload *T) error
+	BulkUpdate(ctx context.Context, conditions map[string]any, payload map[string]any) (int64, error)
 
-	Update(id uint, payload *T) error
-	BulkUpdate(conditions map[string]any, payload map[string]any) (int64, error)
+	Upsert(ctx context.Context, payload *T, conflictColumns []string, updateColumns []string) error
 
-	Delete(id uint) error
+	Delete(ctx context.Context, id K) error
+	BulkDelete(ctx context.Context, ids []K) error
+	BulkRestore(ctx context.Context, ids []K) (int64, error)
 
-	FindById(id uint) (*T, error)
-	Paginate(specs common.Specs) (*common.PaginateResult[T], error)
+	FindById(ctx context.Context, id K) (*T, error)
+	Paginate(ctx context.Context, specs common.Specs) (*common.PaginateResult[T], error)
 }
@@ -0,0 +1,132 @@
+package interfaces
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// ============================================================
+// HOOK — các điểm neo để module con can thiệp vào pipeline ghi của BaseService
+//
+// Mỗi hook là 1 interface 1 method → chỉ implement đúng cái cần:
+//
+//	type SlugHook struct{}
+//	func (SlugHook) BeforeCreate(tx *gorm.DB, p *model.Post) error { ... }
+//
+//	service.AddHook(SlugHook{}, 10)
+//
+// tx: DB handle mà hook PHẢI dùng khi đọc/ghi thêm data
+// → Transactional mode (UseTransaction / EnableOutbox): tx là transaction đang mở,
+// Before + thao tác chính + After cùng commit hoặc cùng rollback
+// → Mode thường: tx là DB gốc, mỗi bước tự commit riêng
+// → ctx của lời gọi service (principal...) lấy qua tx.Statement.Context
+//
+// K: kiểu primary key (uint, string cho ULID, uuid.UUID cho UUIDv7...)
+// ============================================================
+
+// Single actions
+type IBeforeCreate[T any] interface {
+	BeforeCreate(tx *gorm.DB, payload *T) error
+}
+
+type IAfterCreate[T any] interface {
+	AfterCreate(tx *gorm.DB, payload *T) error
+}
+
+type IBeforeUpdate[T any, K comparable] interface {
+	BeforeUpdate(tx *gorm.DB, id K, payload *T) error
+}
+
+type IAfterUpdate[T any, K comparable] interface {
+	AfterUpdate(tx *gorm.DB, id K, payload *T) error
+}
+
+type IBeforeDelete[K comparable] interface {
+	BeforeDelete(tx *gorm.DB, id K) error
+}
+
+type IAfterDelete[K comparable] interface {
+	AfterDelete(tx *gorm.DB, id K) error
+}
+
+// IHook — đủ bộ 6 hook của single actions
+// Module con muốn implement tất cả thì dùng interface này (hoặc embed impl.NoopHook)
+type IHook[T any, K comparable] interface {
+	IBeforeCreate[T]
+	IAfterCreate[T]
+	IBeforeUpdate[T, K]
+	IAfterUpdate[T, K]
+	IBeforeDelete[K]
+	IAfterDelete[K]
+}
+
+// After commit — chạy SAU KHI data đã commit xuống DB
+// Dành cho side effect không rollback được: gửi mail, gọi API ngoài, xóa cache...
+// → Không nhận tx, không trả error: data đã durable, lỗi ở đây hook tự log/retry
+// → ctx có thể đã bị cancel (request xong) → việc chạy nền dùng context.WithoutCancel(ctx)
+type IAfterCreateCommit[T any] interface {
+	AfterCreateCommit(ctx context.Context, payload *T)
+}
+
+type IAfterUpdateCommit[T any, K comparable] interface {
+	AfterUpdateCommit(ctx context.Context, id K, payload *T)
+}
+
+type IAfterDeleteCommit[K comparable] interface {
+	AfterDeleteCommit(ctx context.Context, id K)
+}
+
+// IAfterCommitHook — đủ bộ after-commit hook
+type IAfterCommitHook[T any, K comparable] interface {
+	IAfterCreateCommit[T]
+	IAfterUpdateCommit[T, K]
+	IAfterDeleteCommit[K]
+}
+
+// Bulk actions — hook theo TẬP record, chạy 1 lần cho cả batch
+// KHÔNG lặp hook từng record → import 10k dòng vẫn nhanh
+// → Validate cả tập, chặn mass edit nguy hiểm, ghi audit 1 dòng cho cả batch...
+type IBeforeBulkCreate[T any] interface {
+	BeforeBulkCreate(tx *gorm.DB, payloads []T) error
+}
+
+type IAfterBulkCreate[T any] interface {
+	AfterBulkCreate(tx *gorm.DB, payloads []T) error // payloads đã có ID
+}
+
+type IBeforeBulkUpdate interface {
+	BeforeBulkUpdate(tx *gorm.DB, conditions map[string]any, fields map[string]any) error
+}
+
+type IAfterBulkUpdate interface {
+	AfterBulkUpdate(tx *gorm.DB, conditions map[string]any, fields map[string]any, affected int64) error
+}
+
+type IBeforeBulkDelete[K comparable] interface {
+	BeforeBulkDelete(tx *gorm.DB, ids []K) error
+}
+
+type IAfterBulkDelete[K comparable] interface {
+	AfterBulkDelete(tx *gorm.DB, ids []K) error
+}
+
+type IBeforeBulkRestore[K comparable] interface {
+	BeforeBulkRestore(tx *gorm.DB, ids []K) error
+}
+
+type IAfterBulkRestore[K comparable] interface {
+	AfterBulkRestore(tx *gorm.DB, ids []K, affected int64) error
+}
+
+// IBulkHook — đủ bộ hook hàng loạt
+type IBulkHook[T any, K comparable] interface {
+	IBeforeBulkCreate[T]
+	IAfterBulkCreate[T]
+	IBeforeBulkUpdate
+	IAfterBulkUpdate
+	IBeforeBulkDelete[K]
+	IAfterBulkDelete[K]
+	IBeforeBulkRestore[K]
+	IAfterBulkRestore[K]
+}
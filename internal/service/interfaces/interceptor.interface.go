@@ -0,0 +1,23 @@
+package interfaces
+
+import "context"
+
+// ============================================================
+// INTERCEPTOR — bọc quanh MỌI lời gọi service (giống middleware của Gin)
+//
+//	Logging → Metrics → Retry → [BaseService.Create]
+//
+// Interceptor đăng ký trước nằm ngoài cùng.
+// Dùng cho concern cắt ngang: log, đo thời gian, retry khi deadlock...
+// ============================================================
+
+// Call — thông tin lời gọi đang được intercept
+type Call struct {
+	Context context.Context // ctx của lời gọi (principal, request id...)
+	Entity  string          // tên model, VD: "UserCatalogue"
+	Method  string          // tên method service, VD: "Create", "BulkUpdate"
+}
+
+// Interceptor — next() chạy phần còn lại của chuỗi (interceptor sau + method thật)
+// Không gọi next = chặn lời gọi, gọi nhiều lần = retry
+type Interceptor func(call Call, next func() error) error
@@ -0,0 +1,36 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+)
+
+// ============================================================
+// PRECONDITION — điều kiện trên record hiện tại trước khi ghi (optimistic concurrency)
+//
+//	ctx = WithPrecondition(ctx, func(current *Post) bool { return current.Version == 3 })
+//	err := service.Update(ctx, id, payload) → errors.Is(err, ErrPreconditionFailed)
+//
+// BaseService đọc lại record bằng SELECT ... FOR UPDATE trong transaction của Update / Replace / Delete
+// → kiểm tra và ghi là 1 thao tác nguyên tử, không mất update khi 2 client sửa cùng lúc
+// Controller dùng cho header If-Match (xem base.IfMatch)
+// ============================================================
+
+// ErrPreconditionFailed — record đã bị thay đổi từ lần đọc trước của client
+var ErrPreconditionFailed = errors.New("precondition failed")
+
+// Precondition — true = được phép ghi
+type Precondition[T any] func(current *T) bool
+
+type preconditionKey struct{}
+
+// WithPrecondition — gắn điều kiện vào ctx của lời gọi service
+func WithPrecondition[T any](ctx context.Context, check Precondition[T]) context.Context {
+	return context.WithValue(ctx, preconditionKey{}, check)
+}
+
+// PreconditionFrom — điều kiện đã gắn, false nếu không có (hoặc khác kiểu T)
+func PreconditionFrom[T any](ctx context.Context) (Precondition[T], bool) {
+	check, ok := ctx.Value(preconditionKey{}).(Precondition[T])
+	return check, ok && check != nil
+}
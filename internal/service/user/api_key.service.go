@@ -0,0 +1,207 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"golang-base/internal/auth"
+	"golang-base/internal/auth/apikey"
+	"golang-base/internal/event"
+	"golang-base/internal/model"
+	ur "golang-base/internal/repository/user"
+	"golang-base/pkg/validation"
+
+	"gorm.io/gorm"
+)
+
+// ErrAPIKeyNotFound — key không tồn tại / đã thu hồi
+var ErrAPIKeyNotFound = errors.New("api key not found")
+
+// CreateAPIKeyInput — scopes là tên permission (user.view, catalogue.update...)
+type CreateAPIKeyInput struct {
+	Name      string     `json:"name"       validate:"required,min=2,max=255"`
+	Scopes    []string   `json:"scopes"     validate:"required,min=1,dive,required,max=100"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// RotateAPIKeyInput — GraceSeconds: key cũ còn dùng được thêm bao lâu (0 = thu hồi ngay)
+type RotateAPIKeyInput struct {
+	GraceSeconds int `json:"grace_seconds" validate:"omitempty,min=0,max=604800"`
+}
+
+// APIKeyService — tạo / rotate / thu hồi API key
+type APIKeyService struct {
+	db          *gorm.DB
+	keys        *ur.APIKeyRepository
+	permissions *ur.PermissionRepository
+	bus         *event.Bus
+	now         func() time.Time
+}
+
+func NewAPIKeyService(db *gorm.DB, bus *event.Bus) *APIKeyService {
+	return &APIKeyService{
+		db:          db,
+		keys:        ur.NewAPIKeyRepository(db),
+		permissions: ur.NewPermissionRepository(db),
+		bus:         bus,
+		now:         time.Now,
+	}
+}
+
+// List — key chưa thu hồi, mới tạo trước
+func (s *APIKeyService) List(ctx context.Context) ([]model.APIKey, error) {
+	var keys []model.APIKey
+	err := s.db.WithContext(ctx).Where("revoked_at IS NULL").Order("id DESC").Find(&keys).Error
+	if err != nil {
+		return nil, fmt.Errorf("list api keys failed: %w", err)
+	}
+	return keys, nil
+}
+
+// Create — tạo key mới, trả về key gốc (CHỈ hiện 1 lần, DB chỉ lưu hash)
+// Scope phải là permission có thật VÀ người tạo cũng có quyền đó (không tạo key mạnh hơn chính mình)
+func (s *APIKeyService) Create(ctx context.Context, in CreateAPIKeyInput) (*model.APIKey, string, error) {
+	if err := validation.Struct(ctx, &in); err != nil {
+		return nil, "", err
+	}
+	if in.ExpiresAt != nil && !in.ExpiresAt.After(s.now()) {
+		return nil, "", validation.Errors{validation.NewFieldError("expires_at", "gtfield", "now")}
+	}
+	scopes, err := s.checkScopes(ctx, in.Scopes)
+	if err != nil {
+		return nil, "", err
+	}
+	return s.issue(ctx, s.keys.WithTx(s.db.WithContext(ctx)), in.Name, scopes, in.ExpiresAt)
+}
+
+// Rotate — tạo key mới cùng tên / scopes / hạn, key cũ hết hiệu lực sau GraceSeconds
+func (s *APIKeyService) Rotate(ctx context.Context, id uint64, in RotateAPIKeyInput) (*model.APIKey, string, error) {
+	if err := validation.Struct(ctx, &in); err != nil {
+		return nil, "", err
+	}
+
+	var (
+		old, key *model.APIKey
+		raw      string
+	)
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		keys := s.keys.WithTx(tx)
+		var err error
+		if old, err = s.active(keys, id); err != nil {
+			return err
+		}
+		if key, raw, err = s.issue(ctx, keys, old.Name, old.Scopes, old.ExpiresAt); err != nil {
+			return err
+		}
+
+		now := s.now()
+		if in.GraceSeconds == 0 {
+			return keys.UpdateFields(id, map[string]any{"revoked_at": now})
+		}
+		graceUntil := now.Add(time.Duration(in.GraceSeconds) * time.Second)
+		if old.ExpiresAt != nil && old.ExpiresAt.Before(graceUntil) {
+			return nil // hạn cũ đến trước → giữ nguyên
+		}
+		return keys.UpdateFields(id, map[string]any{"expires_at": graceUntil})
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	event.Publish(ctx, s.bus, apikey.Revoked{ID: old.ID, Prefix: old.Prefix})
+	return key, raw, nil
+}
+
+// Revoke — thu hồi key ngay
+func (s *APIKeyService) Revoke(ctx context.Context, id uint64) error {
+	var key *model.APIKey
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		keys := s.keys.WithTx(tx)
+		var err error
+		if key, err = s.active(keys, id); err != nil {
+			return err
+		}
+		return keys.UpdateFields(id, map[string]any{"revoked_at": s.now()})
+	})
+	if err != nil {
+		return err
+	}
+
+	event.Publish(ctx, s.bus, apikey.Revoked{ID: key.ID, Prefix: key.Prefix})
+	return nil
+}
+
+func (s *APIKeyService) active(keys *ur.APIKeyRepository, id uint64) (*model.APIKey, error) {
+	key, err := keys.FindById(id, nil)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil || key.RevokedAt != nil {
+		return nil, ErrAPIKeyNotFound
+	}
+	return key, nil
+}
+
+func (s *APIKeyService) issue(ctx context.Context, keys *ur.APIKeyRepository, name string, scopes []string, expiresAt *time.Time) (*model.APIKey, string, error) {
+	raw, prefix, hash, err := apikey.Generate()
+	if err != nil {
+		return nil, "", err
+	}
+
+	key := &model.APIKey{
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   hash,
+		Scopes:    scopes,
+		ExpiresAt: expiresAt,
+		CreatedBy: creatorID(ctx),
+	}
+	if err := keys.Create(key); err != nil {
+		return nil, "", err
+	}
+	return key, raw, nil
+}
+
+// checkScopes — scope tồn tại + người tạo có quyền, trả về scope đã sort, bỏ trùng
+func (s *APIKeyService) checkScopes(ctx context.Context, requested []string) ([]string, error) {
+	p, _ := auth.PrincipalFrom(ctx)
+	scopes := slices.Compact(slices.Sorted(slices.Values(requested)))
+
+	found, err := s.permissions.WithTx(s.db.WithContext(ctx)).FindByNames(scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	var errs validation.Errors
+	for i, scope := range requested {
+		exists := slices.ContainsFunc(found, func(perm model.Permission) bool { return perm.Name == scope })
+		switch {
+		case !exists:
+			errs = append(errs, validation.NewFieldError(fmt.Sprintf("scopes[%d]", i), "exists", ""))
+		case !p.Can(scope):
+			return nil, auth.ErrForbidden
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return scopes, nil
+}
+
+// creatorID — user đang thao tác (nil nếu principal không phải user)
+func creatorID(ctx context.Context) *uint {
+	p, ok := auth.PrincipalFrom(ctx)
+	if !ok || p.Kind != auth.KindUser {
+		return nil
+	}
+	id, err := strconv.ParseUint(p.ID, 10, strconv.IntSize)
+	if err != nil {
+		return nil
+	}
+	uid := uint(id)
+	return &uid
+}
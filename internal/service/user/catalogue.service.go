@@ -0,0 +1,113 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"golang-base/internal/event"
+	"golang-base/internal/model"
+	ur "golang-base/internal/repository/user"
+	"golang-base/internal/service/hooks"
+	"golang-base/internal/service/impl"
+	"golang-base/pkg/validation"
+
+	"gorm.io/gorm"
+)
+
+// ErrSlugTaken — slug trùng catalogue khác, bọc cùng validation.Errors (client vẫn nhận lỗi field)
+var ErrSlugTaken = errors.New("catalogue slug already taken")
+
+// BulkPublishInput — publish / unpublish nhiều catalogue cùng lúc
+type BulkPublishInput struct {
+	IDs []uint `json:"ids" validate:"required,min=1,max=100"`
+}
+
+// CatalogueService — CRUD nhóm user trên BaseService
+//
+// Pipeline ghi (cùng 1 transaction):
+//
+//	SlugHook (slug từ name nếu bỏ trống) → CatalogueService (role mặc định) → validate (slug unique) → DB → AuditHook
+//
+// Event EntityUpdated / EntityDeleted → rbac.Resolver tự xóa cache quyền của catalogue
+type CatalogueService struct {
+	*impl.BaseService[model.UserCatalogue, uint]
+
+	catalogues *ur.CatalogueRepository
+}
+
+func NewCatalogueService(db *gorm.DB, bus *event.Bus) *CatalogueService {
+	s := &CatalogueService{catalogues: ur.NewCatalogueRepository(db)}
+	s.BaseService = impl.NewBaseService[model.UserCatalogue, uint](s.catalogues.BaseRepository).
+		AddHook(hooks.Slug[model.UserCatalogue, uint]("Name", "Slug"), 10).
+		AddHook(s, 20).
+		AddHook(hooks.Audit[model.UserCatalogue, uint]("user_catalogue"), 100).
+		UseTransaction().
+		WithEventBus(bus)
+	return s
+}
+
+// BeforeCreate — cột role NOT NULL không có default → gán role mặc định
+func (s *CatalogueService) BeforeCreate(tx *gorm.DB, catalogue *model.UserCatalogue) error {
+	if catalogue.Role == "" {
+		catalogue.Role = model.DefaultCatalogueRole
+	}
+	return nil
+}
+
+func (s *CatalogueService) BeforeBulkCreate(tx *gorm.DB, catalogues []model.UserCatalogue) error {
+	for i := range catalogues {
+		if err := s.BeforeCreate(tx, &catalogues[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ============================================================
+// GHI — giống BaseService, lỗi unique trên slug → ErrSlugTaken
+// ============================================================
+
+func (s *CatalogueService) Create(ctx context.Context, catalogue *model.UserCatalogue) error {
+	return slugTaken(s.BaseService.Create(ctx, catalogue))
+}
+
+func (s *CatalogueService) Update(ctx context.Context, id uint, catalogue *model.UserCatalogue) error {
+	return slugTaken(s.BaseService.Update(ctx, id, catalogue))
+}
+
+func (s *CatalogueService) Replace(ctx context.Context, id uint, catalogue *model.UserCatalogue) error {
+	return slugTaken(s.BaseService.Replace(ctx, id, catalogue))
+}
+
+func slugTaken(err error) error {
+	var verrs validation.Errors
+	if errors.As(err, &verrs) && slices.ContainsFunc(verrs, func(fe validation.FieldError) bool {
+		return fe.Field == "slug" && fe.Rule == "unique"
+	}) {
+		return fmt.Errorf("%w: %w", ErrSlugTaken, err)
+	}
+	return err
+}
+
+// ============================================================
+// PUBLISH — đổi trạng thái hàng loạt, đi qua BulkUpdate (hook bulk + event + audit)
+// ============================================================
+
+// Publish — chuyển các catalogue sang published, trả về số record thay đổi
+func (s *CatalogueService) Publish(ctx context.Context, in BulkPublishInput) (int64, error) {
+	return s.setPublish(ctx, in, model.PublishPublished)
+}
+
+// Unpublish — chuyển các catalogue về draft
+func (s *CatalogueService) Unpublish(ctx context.Context, in BulkPublishInput) (int64, error) {
+	return s.setPublish(ctx, in, model.PublishDraft)
+}
+
+func (s *CatalogueService) setPublish(ctx context.Context, in BulkPublishInput, publish uint) (int64, error) {
+	if err := validation.Struct(ctx, &in); err != nil {
+		return 0, err
+	}
+	return s.BulkUpdate(ctx, map[string]any{"id": in.IDs}, map[string]any{"publish": publish})
+}
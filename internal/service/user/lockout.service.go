@@ -0,0 +1,133 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"golang-base/internal/auth"
+	"golang-base/internal/auth/throttle"
+	"golang-base/internal/event"
+	ur "golang-base/internal/repository/user"
+	"golang-base/pkg/validation"
+
+	"gorm.io/gorm"
+)
+
+// ErrUserNotFound — user không tồn tại (admin thao tác theo id)
+var ErrUserNotFound = errors.New("user not found")
+
+// AccountUnlockRequested — tài khoản có thật bị khóa do đăng nhập sai nhiều lần
+// Module mail subscribe để gửi link mở khóa chứa Token (token gốc CHỈ có ở đây)
+type AccountUnlockRequested struct {
+	UserID uint
+	Name   string
+	Email  string
+	Token  string
+	Until  time.Time // hết khóa tự động lúc này
+}
+
+// LockoutStatus — GET /users/:id/lockout
+type LockoutStatus struct {
+	Locked     bool  `json:"locked"`
+	RetryAfter int64 `json:"retry_after"` // giây
+}
+
+// LockoutService — chống dò mật khẩu (bọc throttle.Throttler) + mở khóa tài khoản
+type LockoutService struct {
+	db        *gorm.DB
+	throttler *throttle.Throttler
+	users     *ur.UserRepository
+	bus       *event.Bus
+}
+
+func NewLockoutService(db *gorm.DB, throttler *throttle.Throttler, bus *event.Bus) *LockoutService {
+	return &LockoutService{
+		db:        db,
+		throttler: throttler,
+		users:     ur.NewUserRepository(db),
+		bus:       bus,
+	}
+}
+
+// ============================================================
+// ĐĂNG NHẬP — Check trước, Failed / Succeeded sau khi kiểm tra mật khẩu
+// account rỗng → chỉ theo IP (VD: bước 2FA chưa biết email)
+// ============================================================
+
+// Check — đang bị delay / khóa → *throttle.RetryError
+func (s *LockoutService) Check(ctx context.Context, account, ip string) error {
+	return s.throttler.Check(ctx, account, ip)
+}
+
+func (s *LockoutService) Failed(ctx context.Context, account, ip string) error {
+	return s.throttler.Fail(ctx, account, ip)
+}
+
+func (s *LockoutService) Succeeded(ctx context.Context, account string) error {
+	return s.throttler.Succeed(ctx, account)
+}
+
+// ============================================================
+// MỞ KHÓA
+// ============================================================
+
+// Unlock — chủ tài khoản mở khóa bằng token trong mail
+func (s *LockoutService) Unlock(ctx context.Context, in UnlockAccountInput) error {
+	if err := validation.Struct(ctx, &in); err != nil {
+		return err
+	}
+	_, err := s.throttler.Redeem(ctx, in.Token)
+	return err
+}
+
+// Status — tài khoản của user có đang bị khóa không
+func (s *LockoutService) Status(ctx context.Context, userID uint) (*LockoutStatus, error) {
+	user, err := s.users.WithTx(s.db.WithContext(ctx)).FindById(userID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	ttl, err := s.throttler.LockedFor(ctx, user.Email)
+	if err != nil {
+		return nil, err
+	}
+	return &LockoutStatus{Locked: ttl > 0, RetryAfter: int64(ttl.Seconds())}, nil
+}
+
+// Release — admin mở khóa tài khoản của user
+func (s *LockoutService) Release(ctx context.Context, userID uint) error {
+	user, err := s.users.WithTx(s.db.WithContext(ctx)).FindById(userID, nil)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return ErrUserNotFound
+	}
+	var by string
+	if p, ok := auth.PrincipalFrom(ctx); ok {
+		by = p.ID
+	}
+	return s.throttler.Unlock(ctx, user.Email, by)
+}
+
+// Subscribe — tài khoản bị khóa mà có thật → publish AccountUnlockRequested
+// Trả về hàm hủy đăng ký
+func (s *LockoutService) Subscribe(bus *event.Bus) func() {
+	return event.Subscribe(bus, "user.account_locked", func(ctx context.Context, e throttle.AccountLocked) error {
+		user, err := s.users.WithTx(s.db.WithContext(ctx)).FindByEmail(e.Account)
+		if err != nil || user == nil {
+			return err
+		}
+		event.Publish(ctx, bus, AccountUnlockRequested{
+			UserID: user.ID,
+			Name:   user.Name,
+			Email:  user.Email,
+			Token:  e.UnlockToken,
+			Until:  e.Until,
+		})
+		return nil
+	})
+}
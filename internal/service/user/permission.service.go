@@ -0,0 +1,116 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"golang-base/internal/auth/rbac"
+	"golang-base/internal/event"
+	"golang-base/internal/model"
+	ur "golang-base/internal/repository/user"
+	"golang-base/pkg/validation"
+
+	"gorm.io/gorm"
+)
+
+// ErrCatalogueNotFound — catalogue không tồn tại
+var ErrCatalogueNotFound = errors.New("user catalogue not found")
+
+// SyncPermissionsInput — danh sách permission MỚI của catalogue (thay thế toàn bộ)
+type SyncPermissionsInput struct {
+	Permissions []string `json:"permissions" validate:"omitempty,dive,required,max=100"`
+}
+
+// PermissionService — danh sách permission + gán permission cho catalogue
+type PermissionService struct {
+	db          *gorm.DB
+	permissions *ur.PermissionRepository
+	catalogues  *ur.CatalogueRepository
+	bus         *event.Bus
+}
+
+func NewPermissionService(db *gorm.DB, bus *event.Bus) *PermissionService {
+	return &PermissionService{
+		db:          db,
+		permissions: ur.NewPermissionRepository(db),
+		catalogues:  ur.NewCatalogueRepository(db),
+		bus:         bus,
+	}
+}
+
+// List — toàn bộ permission, sort theo tên
+func (s *PermissionService) List(ctx context.Context) ([]model.Permission, error) {
+	var permissions []model.Permission
+	if err := s.db.WithContext(ctx).Order("name").Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+// CataloguePermissions — tên permission đang gán cho catalogue
+func (s *PermissionService) CataloguePermissions(ctx context.Context, catalogueID uint) ([]string, error) {
+	if err := s.ensureCatalogue(s.db.WithContext(ctx), catalogueID); err != nil {
+		return nil, err
+	}
+	return s.permissions.WithTx(s.db.WithContext(ctx)).NamesByCatalogue(catalogueID)
+}
+
+// SyncCatalogue — gán lại permission cho catalogue, publish rbac.PermissionsChanged
+// Tên permission không tồn tại → validation error (rule "exists")
+func (s *PermissionService) SyncCatalogue(ctx context.Context, catalogueID uint, in SyncPermissionsInput) ([]string, error) {
+	if err := validation.Struct(ctx, &in); err != nil {
+		return nil, err
+	}
+	names := slices.Compact(slices.Sorted(slices.Values(in.Permissions)))
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.ensureCatalogue(tx, catalogueID); err != nil {
+			return err
+		}
+
+		permissions := s.permissions.WithTx(tx)
+		found, err := permissions.FindByNames(names)
+		if err != nil {
+			return err
+		}
+		if len(found) != len(names) {
+			return unknownPermissions(in.Permissions, found)
+		}
+
+		ids := make([]uint, len(found))
+		for i := range found {
+			ids[i] = found[i].ID
+		}
+		return permissions.SyncCatalogue(catalogueID, ids)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	event.Publish(ctx, s.bus, rbac.PermissionsChanged{CatalogueID: catalogueID})
+	return names, nil
+}
+
+func (s *PermissionService) ensureCatalogue(db *gorm.DB, catalogueID uint) error {
+	catalogue, err := s.catalogues.WithTx(db).FindById(catalogueID, nil)
+	if err != nil {
+		return err
+	}
+	if catalogue == nil {
+		return ErrCatalogueNotFound
+	}
+	return nil
+}
+
+// unknownPermissions — lỗi field cho từng tên không tồn tại, index theo input: permissions[2]
+func unknownPermissions(names []string, found []model.Permission) validation.Errors {
+	var errs validation.Errors
+	for i, name := range names {
+		if !slices.ContainsFunc(found, func(p model.Permission) bool { return p.Name == name }) {
+			errs = append(errs, validation.NewFieldError(fmt.Sprintf("permissions[%d]", i), "exists", ""))
+		}
+	}
+	return errs
+}
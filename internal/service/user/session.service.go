@@ -0,0 +1,74 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"golang-base/internal/auth/session"
+	"golang-base/internal/event"
+	"golang-base/internal/model"
+	ur "golang-base/internal/repository/user"
+
+	"gorm.io/gorm"
+)
+
+// ErrSessionNotFound — session không tồn tại / không thuộc user / đã thu hồi
+var ErrSessionNotFound = errors.New("session not found")
+
+// SessionService — liệt kê + thu hồi session đăng nhập (đăng xuất, đăng xuất mọi nơi, admin kill)
+type SessionService struct {
+	db       *gorm.DB
+	sessions *ur.SessionRepository
+	bus      *event.Bus
+	now      func() time.Time
+}
+
+func NewSessionService(db *gorm.DB, bus *event.Bus) *SessionService {
+	return &SessionService{
+		db:       db,
+		sessions: ur.NewSessionRepository(db),
+		bus:      bus,
+		now:      time.Now,
+	}
+}
+
+// List — session còn hiệu lực của user, mới dùng gần nhất trước
+func (s *SessionService) List(ctx context.Context, userID uint) ([]model.Session, error) {
+	return s.sessions.WithTx(s.db.WithContext(ctx)).ActiveByUser(userID, s.now())
+}
+
+// Revoke — thu hồi 1 session của user (đăng xuất / kill 1 thiết bị)
+func (s *SessionService) Revoke(ctx context.Context, userID uint, sessionID string) error {
+	ids, err := s.revoke(ctx, userID, "id = ? AND user_id = ?", sessionID, userID)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// RevokeAll — thu hồi mọi session của user (đăng xuất mọi nơi / khóa tài khoản bị chiếm)
+// Trả về số session bị thu hồi
+func (s *SessionService) RevokeAll(ctx context.Context, userID uint) (int, error) {
+	ids, err := s.revoke(ctx, userID, "user_id = ?", userID)
+	return len(ids), err
+}
+
+func (s *SessionService) revoke(ctx context.Context, userID uint, query string, args ...any) ([]string, error) {
+	var ids []string
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		ids, err = s.sessions.WithTx(tx).Revoke(s.now(), query, args...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) > 0 {
+		event.Publish(ctx, s.bus, session.Revoked{UserID: userID, SessionIDs: ids})
+	}
+	return ids, nil
+}
@@ -0,0 +1,247 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"golang-base/internal/auth"
+	"golang-base/internal/auth/session"
+	"golang-base/internal/auth/token"
+	"golang-base/internal/event"
+	"golang-base/internal/model"
+	ur "golang-base/internal/repository/user"
+	"golang-base/pkg/secure"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidRefreshToken — refresh token sai / hết hạn / đã thu hồi
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+
+	// ErrRefreshTokenReused — refresh token đã dùng bị gửi lại → cả family bị thu hồi
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+)
+
+// TokenPair — response đăng nhập / refresh
+type TokenPair struct {
+	TokenType        string    `json:"token_type"`
+	AccessToken      string    `json:"access_token"`
+	ExpiresIn        int64     `json:"expires_in"` // giây
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// RefreshTokenReused — publish khi phát hiện refresh token bị dùng lại
+// Thường là dấu hiệu token bị đánh cắp → module security có thể cảnh báo user
+type RefreshTokenReused struct {
+	UserID   uint
+	FamilyID string
+}
+
+// SessionMeta — thông tin thiết bị của lần đăng nhập / refresh
+type SessionMeta struct {
+	Device    string
+	UserAgent string
+	IP        string
+	TwoFactor bool // đăng nhập đã qua bước 2FA (chỉ dùng khi Issue)
+}
+
+// TokenService — cấp access / refresh token, xoay vòng refresh token, mỗi lần đăng nhập 1 session
+type TokenService struct {
+	db       *gorm.DB
+	tokens   *token.Manager
+	users    *ur.UserRepository
+	refresh  *ur.RefreshTokenRepository
+	sessions *ur.SessionRepository
+	bus      *event.Bus
+	now      func() time.Time
+}
+
+func NewTokenService(db *gorm.DB, tokens *token.Manager, bus *event.Bus) *TokenService {
+	return &TokenService{
+		db:       db,
+		tokens:   tokens,
+		users:    ur.NewUserRepository(db),
+		refresh:  ur.NewRefreshTokenRepository(db),
+		sessions: ur.NewSessionRepository(db),
+		bus:      bus,
+		now:      time.Now,
+	}
+}
+
+// Issue — cấp cặp token cho 1 lần đăng nhập mới (session mới = family mới)
+func (s *TokenService) Issue(ctx context.Context, user *model.User, meta SessionMeta) (*TokenPair, error) {
+	var pair *TokenPair
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := s.now()
+		session := &model.Session{
+			ID:         uuid.NewString(),
+			UserID:     user.ID,
+			Device:     truncate(meta.Device, 255),
+			UserAgent:  truncate(meta.UserAgent, 512),
+			IP:         meta.IP,
+			LastSeenAt: now,
+			ExpiresAt:  now.Add(s.tokens.RefreshTTL()),
+		}
+		if meta.TwoFactor {
+			session.TwoFactorAt = &now
+		}
+		if err := s.sessions.WithTx(tx).Create(session); err != nil {
+			return err
+		}
+
+		var err error
+		pair, err = s.issue(s.refresh.WithTx(tx), user, session)
+		return err
+	})
+	return pair, err
+}
+
+// Refresh — đổi refresh token lấy cặp token mới, token cũ hết hiệu lực
+//
+//	token hợp lệ, chưa dùng   → cặp mới cùng family
+//	token đã dùng (reuse)     → thu hồi cả family, ErrRefreshTokenReused
+//	sai / hết hạn / thu hồi   → ErrInvalidRefreshToken
+func (s *TokenService) Refresh(ctx context.Context, raw string, meta SessionMeta) (*TokenPair, error) {
+	if raw == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+	now := s.now()
+
+	current, err := s.refresh.WithTx(s.db.WithContext(ctx)).FindByHash(secure.HashToken(raw))
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case current == nil:
+		return nil, ErrInvalidRefreshToken
+	case current.UsedAt != nil:
+		return nil, s.reused(ctx, current)
+	case current.RevokedAt != nil, !current.ExpiresAt.After(now):
+		return nil, ErrInvalidRefreshToken
+	}
+
+	var pair *TokenPair
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		refresh := s.refresh.WithTx(tx)
+
+		// 2 request cùng token đồng thời → chỉ 1 request đánh dấu được
+		ok, err := refresh.MarkUsed(current.ID, now)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrRefreshTokenReused
+		}
+
+		user, err := s.users.WithTx(tx).FindById(current.UserID, nil)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return ErrInvalidRefreshToken
+		}
+
+		// Session bị thu hồi (đăng xuất, admin kill...) → refresh token của nó cũng hết dùng được
+		sessions := s.sessions.WithTx(tx)
+		active, err := sessions.FindById(current.FamilyID, nil)
+		if err != nil {
+			return err
+		}
+		if active == nil || active.RevokedAt != nil {
+			return ErrInvalidRefreshToken
+		}
+		touch := map[string]any{
+			"last_seen_at": now,
+			"expires_at":   now.Add(s.tokens.RefreshTTL()),
+		}
+		if meta.IP != "" {
+			touch["ip"] = meta.IP
+		}
+		if meta.UserAgent != "" {
+			touch["user_agent"] = truncate(meta.UserAgent, 512)
+		}
+		if err := sessions.Touch(active.ID, touch); err != nil {
+			return err
+		}
+
+		pair, err = s.issue(refresh, user, active)
+		return err
+	})
+	if errors.Is(err, ErrRefreshTokenReused) {
+		return nil, s.reused(ctx, current)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return pair, nil
+}
+
+// reused — thu hồi session (kèm cả family) + publish RefreshTokenReused, session.Revoked
+func (s *TokenService) reused(ctx context.Context, rt *model.RefreshToken) error {
+	ids, err := s.sessions.WithTx(s.db.WithContext(ctx)).Revoke(s.now(), "id = ?", rt.FamilyID)
+	if err != nil {
+		return err
+	}
+	event.Publish(ctx, s.bus, RefreshTokenReused{UserID: rt.UserID, FamilyID: rt.FamilyID})
+	if len(ids) > 0 {
+		event.Publish(ctx, s.bus, session.Revoked{UserID: rt.UserID, SessionIDs: ids})
+	}
+	return ErrRefreshTokenReused
+}
+
+// issue — ký access token + lưu refresh token mới trong family (family = session)
+func (s *TokenService) issue(refresh *ur.RefreshTokenRepository, user *model.User, session *model.Session) (*TokenPair, error) {
+	access, expiresAt, err := s.tokens.Sign(s.claims(user, session))
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := secure.RandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	refreshExpiresAt := s.now().Add(s.tokens.RefreshTTL())
+	err = refresh.Create(&model.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  session.ID,
+		TokenHash: secure.HashToken(raw),
+		ExpiresAt: refreshExpiresAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("store refresh token failed: %w", err)
+	}
+
+	return &TokenPair{
+		TokenType:        "Bearer",
+		AccessToken:      access,
+		ExpiresIn:        int64(s.tokens.AccessTTL().Seconds()),
+		ExpiresAt:        expiresAt,
+		RefreshToken:     raw,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+// claims — nội dung access token của user, session đã qua 2FA → amr có "otp"
+func (s *TokenService) claims(user *model.User, session *model.Session) token.Claims {
+	claims := token.Claims{Kind: auth.KindUser, SessionID: session.ID, AMR: []string{token.AMRPassword}}
+	if session.TwoFactorAt != nil {
+		claims.AMR = append(claims.AMR, token.AMROTP)
+	}
+	claims.Subject = strconv.FormatUint(uint64(user.ID), 10)
+	return claims
+}
+
+// truncate — cắt chuỗi theo số rune (vừa cột DB)
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
@@ -0,0 +1,460 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang-base/internal/auth/totp"
+	"golang-base/internal/event"
+	"golang-base/internal/model"
+	ur "golang-base/internal/repository/user"
+	"golang-base/pkg/secure"
+	"golang-base/pkg/validation"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidTwoFactorChallenge — pending token sai / hết hạn / đã dùng / nhập sai quá nhiều lần
+	ErrInvalidTwoFactorChallenge = errors.New("invalid or expired two-factor challenge")
+
+	// ErrInvalidTwoFactorCode — mã TOTP / mã dự phòng sai khi đăng nhập
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+
+	// ErrTwoFactorEnabled — đã bật 2FA, muốn đổi điện thoại thì tắt rồi bật lại
+	ErrTwoFactorEnabled = errors.New("two-factor authentication already enabled")
+
+	// ErrTwoFactorNotEnabled — chưa bật / chưa enrol
+	ErrTwoFactorNotEnabled = errors.New("two-factor authentication not enabled")
+
+	// ErrTwoFactorRequired — nhóm của user bắt buộc 2FA → không được tắt
+	ErrTwoFactorRequired = errors.New("two-factor authentication is required for your role")
+)
+
+const (
+	ChallengeTTL         = 5 * time.Minute // thời hạn pending token
+	MaxChallengeAttempts = 5               // nhập sai quá số lần → phải đăng nhập lại
+	RecoveryCodeCount    = 10
+)
+
+// TwoFactorEnabled — publish khi user bật 2FA thành công
+type TwoFactorEnabled struct {
+	UserID uint
+}
+
+// TwoFactorDisabled — publish khi user tắt 2FA (module mail có thể cảnh báo)
+type TwoFactorDisabled struct {
+	UserID uint
+}
+
+// RecoveryCodeUsed — publish khi đăng nhập bằng mã dự phòng, Remaining thấp → nhắc user tạo bộ mới
+type RecoveryCodeUsed struct {
+	UserID    uint
+	Remaining int64
+}
+
+// TwoFactorStatus — GET /me/two-factor
+type TwoFactorStatus struct {
+	Enabled           bool       `json:"enabled"`
+	ConfirmedAt       *time.Time `json:"confirmed_at"`
+	Required          bool       `json:"required"` // nhóm bắt buộc 2FA
+	RecoveryCodesLeft int64      `json:"recovery_codes_left"`
+}
+
+// TwoFactorEnrollment — secret vừa sinh, client hiện QR từ URI (secret gốc chỉ trả 1 lần)
+type TwoFactorEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+// PendingChallenge — đăng nhập đúng mật khẩu, gửi kèm mã 2FA lên /auth/two-factor/verify
+type PendingChallenge struct {
+	PendingToken string    `json:"pending_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// TwoFactorService — bật / tắt 2FA (TOTP), mã dự phòng, bước 2 khi đăng nhập
+type TwoFactorService struct {
+	db         *gorm.DB
+	totp       *totp.Manager
+	users      *ur.UserRepository
+	factors    *ur.TwoFactorRepository
+	codes      *ur.RecoveryCodeRepository
+	challenges *ur.TwoFactorChallengeRepository
+	sessions   *ur.SessionRepository
+	bus        *event.Bus
+	now        func() time.Time
+}
+
+func NewTwoFactorService(db *gorm.DB, manager *totp.Manager, bus *event.Bus) *TwoFactorService {
+	return &TwoFactorService{
+		db:         db,
+		totp:       manager,
+		users:      ur.NewUserRepository(db),
+		factors:    ur.NewTwoFactorRepository(db),
+		codes:      ur.NewRecoveryCodeRepository(db),
+		challenges: ur.NewTwoFactorChallengeRepository(db),
+		sessions:   ur.NewSessionRepository(db),
+		bus:        bus,
+		now:        time.Now,
+	}
+}
+
+// ============================================================
+// ĐĂNG NHẬP — bước 2
+// ============================================================
+
+// Challenge — user đã bật 2FA → tạo pending token, chưa bật → nil
+// Gọi sau khi UserService.Login thành công, challenge cũ chưa dùng bị vô hiệu
+func (s *TwoFactorService) Challenge(ctx context.Context, user *model.User) (*PendingChallenge, error) {
+	tf, err := s.factors.WithTx(s.db.WithContext(ctx)).FindById(user.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if tf == nil || tf.ConfirmedAt == nil {
+		return nil, nil
+	}
+
+	raw, err := secure.RandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	challenge := &model.TwoFactorChallenge{
+		UserID:    user.ID,
+		TokenHash: secure.HashToken(raw),
+		ExpiresAt: now.Add(ChallengeTTL),
+	}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		challenges := s.challenges.WithTx(tx)
+		if err := challenges.InvalidateUser(user.ID, now); err != nil {
+			return err
+		}
+		return challenges.Create(challenge)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &PendingChallenge{PendingToken: raw, ExpiresAt: challenge.ExpiresAt}, nil
+}
+
+// Verify — pending token + mã TOTP (hoặc mã dự phòng) → user để cấp token
+// Sai mã → ErrInvalidTwoFactorCode, sai quá MaxChallengeAttempts lần → challenge bị hủy
+func (s *TwoFactorService) Verify(ctx context.Context, in VerifyTwoFactorInput) (*model.User, error) {
+	if err := validation.Struct(ctx, &in); err != nil {
+		return nil, err
+	}
+	now := s.now()
+	challenges := s.challenges.WithTx(s.db.WithContext(ctx))
+
+	challenge, err := challenges.FindByHash(secure.HashToken(in.PendingToken))
+	if err != nil {
+		return nil, err
+	}
+	if challenge == nil || challenge.UsedAt != nil || !challenge.ExpiresAt.After(now) {
+		return nil, ErrInvalidTwoFactorChallenge
+	}
+	if challenge.Attempts >= MaxChallengeAttempts {
+		if _, err := challenges.Consume(challenge.ID, now); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidTwoFactorChallenge
+	}
+
+	ok, err := s.secondFactor(ctx, challenge.UserID, in.Code, in.RecoveryCode)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if err := challenges.Fail(challenge.ID); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	// 2 request cùng pending token → chỉ 1 request được cấp token
+	consumed, err := challenges.Consume(challenge.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, ErrInvalidTwoFactorChallenge
+	}
+
+	user, err := s.users.WithTx(s.db.WithContext(ctx)).FindById(challenge.UserID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrInvalidTwoFactorChallenge
+	}
+	return user, nil
+}
+
+// ============================================================
+// ME — bật / tắt 2FA
+// ============================================================
+
+// Status — trạng thái 2FA của user
+func (s *TwoFactorService) Status(ctx context.Context, userID uint) (*TwoFactorStatus, error) {
+	db := s.db.WithContext(ctx)
+	tf, err := s.factors.WithTx(db).FindById(userID, nil)
+	if err != nil {
+		return nil, err
+	}
+	required, err := s.Required(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &TwoFactorStatus{Required: required}
+	if tf != nil && tf.ConfirmedAt != nil {
+		status.Enabled = true
+		status.ConfirmedAt = tf.ConfirmedAt
+		if status.RecoveryCodesLeft, err = s.codes.WithTx(db).Remaining(userID); err != nil {
+			return nil, err
+		}
+	}
+	return status, nil
+}
+
+// Enroll — sinh secret mới (chưa có hiệu lực đến khi Confirm)
+// Gọi lại khi chưa Confirm → secret cũ bị thay (VD: quét QR lỗi)
+func (s *TwoFactorService) Enroll(ctx context.Context, userID uint) (*TwoFactorEnrollment, error) {
+	db := s.db.WithContext(ctx)
+	user, err := s.users.WithTx(db).FindById(userID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrInvalidCredentials
+	}
+	tf, err := s.factors.WithTx(db).FindById(userID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if tf != nil && tf.ConfirmedAt != nil {
+		return nil, ErrTwoFactorEnabled
+	}
+
+	key, err := s.totp.Generate(user.Email)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.factors.WithTx(db).Replace(&model.TwoFactor{UserID: userID, Secret: key.Sealed}); err != nil {
+		return nil, err
+	}
+	return &TwoFactorEnrollment{Secret: key.Secret, URI: key.URI}, nil
+}
+
+// Confirm — nhập mã từ app để kích hoạt, trả về mã dự phòng (CHỈ hiện 1 lần)
+// Session đang thao tác được tính là đã qua 2FA → refresh để lấy access token mới có amr "otp"
+func (s *TwoFactorService) Confirm(ctx context.Context, userID uint, sessionID string, in ConfirmTwoFactorInput) ([]string, error) {
+	if err := validation.Struct(ctx, &in); err != nil {
+		return nil, err
+	}
+	tf, err := s.factors.WithTx(s.db.WithContext(ctx)).FindById(userID, nil)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case tf == nil:
+		return nil, ErrTwoFactorNotEnabled
+	case tf.ConfirmedAt != nil:
+		return nil, ErrTwoFactorEnabled
+	}
+
+	now := s.now()
+	step, err := s.totp.Verify(tf.Secret, in.Code, now, tf.LastUsedStep)
+	if errors.Is(err, totp.ErrInvalidCode) {
+		return nil, validation.Errors{validation.NewFieldError("code", "otp", "")}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.factors.WithTx(tx).UpdateFields(userID, map[string]any{"confirmed_at": now, "last_used_step": step}); err != nil {
+			return err
+		}
+		if err := s.codes.WithTx(tx).Replace(userID, hashes); err != nil {
+			return err
+		}
+		if sessionID == "" {
+			return nil
+		}
+		return s.sessions.WithTx(tx).Touch(sessionID, map[string]any{"two_factor_at": now})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	event.Publish(ctx, s.bus, TwoFactorEnabled{UserID: userID})
+	return codes, nil
+}
+
+// Disable — tắt 2FA, cần mật khẩu + mã TOTP / mã dự phòng
+// Nhóm bắt buộc 2FA → ErrTwoFactorRequired
+func (s *TwoFactorService) Disable(ctx context.Context, userID uint, in DisableTwoFactorInput) error {
+	if err := validation.Struct(ctx, &in); err != nil {
+		return err
+	}
+	required, err := s.Required(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if required {
+		return ErrTwoFactorRequired
+	}
+
+	user, err := s.users.WithTx(s.db.WithContext(ctx)).FindById(userID, nil)
+	if err != nil {
+		return err
+	}
+	if user == nil || !secure.CheckPassword(user.Password, in.Password) {
+		return validation.Errors{validation.NewFieldError("password", "current_password", "")}
+	}
+	if err := s.requireSecondFactor(ctx, userID, in.Code, in.RecoveryCode); err != nil {
+		return err
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.TwoFactor{}).Error; err != nil {
+			return fmt.Errorf("delete two-factor failed: %w", err)
+		}
+		return s.codes.WithTx(tx).DeleteUser(userID)
+	})
+	if err != nil {
+		return err
+	}
+
+	event.Publish(ctx, s.bus, TwoFactorDisabled{UserID: userID})
+	return nil
+}
+
+// RegenerateRecoveryCodes — bộ mã dự phòng mới, bộ cũ hết dùng được
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID uint, in RegenerateRecoveryCodesInput) ([]string, error) {
+	if err := validation.Struct(ctx, &in); err != nil {
+		return nil, err
+	}
+	if err := s.requireSecondFactor(ctx, userID, in.Code, ""); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.codes.WithTx(s.db.WithContext(ctx)).Replace(userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Required — nhóm của user có bắt buộc 2FA không
+func (s *TwoFactorService) Required(ctx context.Context, userID uint) (bool, error) {
+	var required []bool
+	err := s.db.WithContext(ctx).
+		Model(&model.UserCatalogue{}).
+		Joins("JOIN users ON users.user_catalogue_id = user_catalogues.id").
+		Where("users.id = ?", userID).
+		Pluck("user_catalogues.require_two_factor", &required).Error
+	if err != nil {
+		return false, fmt.Errorf("load two-factor requirement failed: %w", err)
+	}
+	return len(required) > 0 && required[0], nil
+}
+
+// requireSecondFactor — 2FA đã bật + mã đúng, sai → validation.Errors field "code"
+func (s *TwoFactorService) requireSecondFactor(ctx context.Context, userID uint, code, recoveryCode string) error {
+	tf, err := s.factors.WithTx(s.db.WithContext(ctx)).FindById(userID, nil)
+	if err != nil {
+		return err
+	}
+	if tf == nil || tf.ConfirmedAt == nil {
+		return ErrTwoFactorNotEnabled
+	}
+
+	ok, err := s.secondFactor(ctx, userID, code, recoveryCode)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		field := "code"
+		if code == "" {
+			field = "recovery_code"
+		}
+		return validation.Errors{validation.NewFieldError(field, "otp", "")}
+	}
+	return nil
+}
+
+// secondFactor — kiểm tra mã TOTP (ưu tiên) hoặc mã dự phòng, mã đúng bị đánh dấu đã dùng
+func (s *TwoFactorService) secondFactor(ctx context.Context, userID uint, code, recoveryCode string) (bool, error) {
+	db := s.db.WithContext(ctx)
+	now := s.now()
+
+	if code != "" {
+		tf, err := s.factors.WithTx(db).FindById(userID, nil)
+		if err != nil || tf == nil || tf.ConfirmedAt == nil {
+			return false, err
+		}
+		step, err := s.totp.Verify(tf.Secret, code, now, tf.LastUsedStep)
+		if errors.Is(err, totp.ErrInvalidCode) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		// request khác vừa dùng cùng mã → coi như sai
+		return s.factors.WithTx(db).UseStep(userID, step)
+	}
+
+	codes := s.codes.WithTx(db)
+	ok, err := codes.Consume(userID, hashRecoveryCode(recoveryCode), now)
+	if err != nil || !ok {
+		return false, err
+	}
+	remaining, err := codes.Remaining(userID)
+	if err != nil {
+		return false, err
+	}
+	event.Publish(ctx, s.bus, RecoveryCodeUsed{UserID: userID, Remaining: remaining})
+	return true, nil
+}
+
+// ============================================================
+// MÃ DỰ PHÒNG — dạng "abcd-efgh-jklm-npqr", 80 bit ngẫu nhiên
+// ============================================================
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func generateRecoveryCodes() (codes, hashes []string, err error) {
+	codes = make([]string, RecoveryCodeCount)
+	hashes = make([]string, RecoveryCodeCount)
+	for i := range codes {
+		b := make([]byte, 10)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, fmt.Errorf("generate recovery code failed: %w", err)
+		}
+		raw := strings.ToLower(recoveryEncoding.EncodeToString(b)) // 16 ký tự
+		codes[i] = raw[0:4] + "-" + raw[4:8] + "-" + raw[8:12] + "-" + raw[12:16]
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode — bỏ gạch ngang / khoảng trắng, không phân biệt hoa thường
+func hashRecoveryCode(code string) string {
+	code = strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(code)))
+	return secure.HashToken(code)
+}
@@ -0,0 +1,47 @@
+package user
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if len(codes) != RecoveryCodeCount || len(hashes) != RecoveryCodeCount {
+		t.Fatalf("expected %d codes, got %d / %d", RecoveryCodeCount, len(codes), len(hashes))
+	}
+
+	format := regexp.MustCompile(`^[a-z2-7]{4}-[a-z2-7]{4}-[a-z2-7]{4}-[a-z2-7]{4}$`)
+	seen := make(map[string]bool, len(codes))
+	for i, code := range codes {
+		if !format.MatchString(code) {
+			t.Fatalf("unexpected format: %s", code)
+		}
+		if seen[code] {
+			t.Fatalf("duplicate code: %s", code)
+		}
+		seen[code] = true
+		if hashes[i] == code || hashes[i] != hashRecoveryCode(code) {
+			t.Fatalf("hash %d does not match code", i)
+		}
+	}
+}
+
+func TestHashRecoveryCodeNormalizes(t *testing.T) {
+	want := hashRecoveryCode("abcd-efgh-ijkl-mnop")
+	for _, input := range []string{"ABCD-EFGH-IJKL-MNOP", " abcdefghijklmnop ", "abcd efgh ijkl mnop"} {
+		if hashRecoveryCode(input) != want {
+			t.Fatalf("%q should hash like the canonical code", input)
+		}
+	}
+	if hashRecoveryCode("abcd-efgh-ijkl-mnoq") == want {
+		t.Fatal("different codes must hash differently")
+	}
+	if strings.Contains(want, "abcd") {
+		t.Fatal("hash leaks the code")
+	}
+}
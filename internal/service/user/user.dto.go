@@ -0,0 +1,77 @@
+package user
+
+// ============================================================
+// INPUT DTO — data client gửi lên, validate bằng tag `validate`
+// Tách khỏi model.User: client KHÔNG gửi được field ngoài danh sách (id, timestamps...)
+// ============================================================
+
+type RegisterInput struct {
+	Name                 string `json:"name"                  validate:"required,min=2,max=255"`
+	Email                string `json:"email"                 validate:"required,email,max=255"`
+	Password             string `json:"password"              validate:"required,min=8,max=72"`
+	PasswordConfirmation string `json:"password_confirmation" validate:"required,eqfield=Password"`
+}
+
+type LoginInput struct {
+	Email      string `json:"email"       validate:"required,email"`
+	Password   string `json:"password"    validate:"required"`
+	DeviceName string `json:"device_name" validate:"omitempty,max=255"` // tên thiết bị hiển thị trong danh sách session
+}
+
+type RefreshInput struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type UpdateProfileInput struct {
+	Name  string `json:"name"  validate:"omitempty,min=2,max=255"`
+	Email string `json:"email" validate:"omitempty,email,max=255"`
+}
+
+type ChangePasswordInput struct {
+	CurrentPassword      string `json:"current_password"      validate:"required"`
+	Password             string `json:"password"              validate:"required,min=8,max=72,nefield=CurrentPassword"`
+	PasswordConfirmation string `json:"password_confirmation" validate:"required,eqfield=Password"`
+}
+
+type ForgotPasswordInput struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordInput struct {
+	Token                string `json:"token"                 validate:"required"`
+	Password             string `json:"password"              validate:"required,min=8,max=72"`
+	PasswordConfirmation string `json:"password_confirmation" validate:"required,eqfield=Password"`
+}
+
+// ============================================================
+// 2FA
+// ============================================================
+
+type VerifyTwoFactorInput struct {
+	PendingToken string `json:"pending_token" validate:"required"`
+	Code         string `json:"code"          validate:"required_without=RecoveryCode,omitempty,len=6,numeric"`
+	RecoveryCode string `json:"recovery_code" validate:"required_without=Code,omitempty,max=32"`
+	DeviceName   string `json:"device_name"   validate:"omitempty,max=255"`
+}
+
+type ConfirmTwoFactorInput struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+type DisableTwoFactorInput struct {
+	Password     string `json:"password"      validate:"required"`
+	Code         string `json:"code"          validate:"required_without=RecoveryCode,omitempty,len=6,numeric"`
+	RecoveryCode string `json:"recovery_code" validate:"required_without=Code,omitempty,max=32"`
+}
+
+type RegenerateRecoveryCodesInput struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// ============================================================
+// KHÓA TÀI KHOẢN
+// ============================================================
+
+type UnlockAccountInput struct {
+	Token string `json:"token" validate:"required"`
+}
@@ -0,0 +1,69 @@
+package user
+
+import (
+	"context"
+	"strconv"
+
+	"golang-base/internal/auth"
+	"golang-base/internal/model"
+
+	"gorm.io/gorm"
+)
+
+// ============================================================
+// USER POLICY — user chỉ xem / sửa được chính mình, admin được tất cả
+//
+//	CanView   — chính user đó hoặc admin
+//	CanCreate — ai cũng được (đăng ký không cần đăng nhập), chỉ admin được gán nhóm quyền
+//	CanUpdate — chính user đó hoặc admin, chỉ admin được đổi nhóm quyền / bulk update
+//	CanDelete — chỉ admin
+//	Scope     — admin: nil (mọi record), user: chỉ chính mình, còn lại: không record nào
+//
+// "admin" = role AdminRole hoặc tác vụ nội bộ (auth.KindService)
+// ============================================================
+
+// AdminRole — role được toàn quyền trên user
+const AdminRole = "admin"
+
+type UserPolicy struct {
+	auth.AllowAll[model.User]
+}
+
+func (UserPolicy) CanView(ctx context.Context, p *auth.Principal, user *model.User) bool {
+	return isAdmin(p) || isSelf(p, user.ID)
+}
+
+func (UserPolicy) CanCreate(ctx context.Context, p *auth.Principal, user *model.User) bool {
+	return user.UserCatalogueID == nil || isAdmin(p)
+}
+
+// CanUpdate — changes nil (BulkUpdate) → không biết field nào bị đổi, chỉ admin
+func (UserPolicy) CanUpdate(ctx context.Context, p *auth.Principal, current *model.User, changes *model.User) bool {
+	if isAdmin(p) {
+		return true
+	}
+	return changes != nil && changes.UserCatalogueID == nil && isSelf(p, current.ID)
+}
+
+func (UserPolicy) CanDelete(ctx context.Context, p *auth.Principal, user *model.User) bool {
+	return isAdmin(p)
+}
+
+func (UserPolicy) Scope(ctx context.Context, p *auth.Principal) func(*gorm.DB) *gorm.DB {
+	if isAdmin(p) {
+		return nil
+	}
+	if p == nil || p.Kind != auth.KindUser {
+		return func(db *gorm.DB) *gorm.DB { return db.Where("1 = 0") }
+	}
+	return func(db *gorm.DB) *gorm.DB { return db.Where("users.id = ?", p.ID) }
+}
+
+func isAdmin(p *auth.Principal) bool {
+	return p != nil && (p.Kind == auth.KindService || p.HasRole(AdminRole))
+}
+
+// isSelf — principal là user đăng nhập có id = id (API key trùng số id không tính)
+func isSelf(p *auth.Principal, id uint) bool {
+	return p != nil && p.Kind == auth.KindUser && p.ID == strconv.FormatUint(uint64(id), 10)
+}
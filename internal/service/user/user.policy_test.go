@@ -0,0 +1,166 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"golang-base/global/common"
+	"golang-base/internal/auth"
+	"golang-base/internal/model"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newPolicyService — UserService trên sqlite in-memory, đã có An (1), Bình (2), Chi (3)
+func newPolicyService(t *testing.T) (*UserService, *gorm.DB) {
+	t.Helper()
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", strings.ReplaceAll(t.Name(), "/", "_"))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Discard, TranslateError: true})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	if err := db.AutoMigrate(&model.User{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	users := []model.User{
+		{Name: "An", Email: "an@example.com", Password: "x"},
+		{Name: "Bình", Email: "binh@example.com", Password: "x"},
+		{Name: "Chi", Email: "chi@example.com", Password: "x"},
+	}
+	if err := db.Create(&users).Error; err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	return NewUserService(db, nil), db
+}
+
+func asUser(id uint, roles ...string) context.Context {
+	return auth.WithPrincipal(context.Background(), &auth.Principal{
+		ID:    strconv.FormatUint(uint64(id), 10),
+		Kind:  auth.KindUser,
+		Roles: roles,
+	})
+}
+
+func TestUserPolicyView(t *testing.T) {
+	s, _ := newPolicyService(t)
+	apiKey := auth.WithPrincipal(context.Background(), &auth.Principal{ID: "2", Kind: auth.KindAPIKey})
+
+	cases := []struct {
+		name string
+		ctx  context.Context
+		id   uint
+		want error
+	}{
+		{"self", asUser(2), 2, nil},
+		{"other user", asUser(1), 2, auth.ErrForbidden},
+		{"admin", asUser(1, AdminRole), 2, nil},
+		{"api key with same id", apiKey, 2, auth.ErrForbidden},
+		{"anonymous", context.Background(), 2, auth.ErrUnauthenticated},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			user, err := s.FindById(tc.ctx, tc.id)
+			if !errors.Is(err, tc.want) || (tc.want == nil && (user == nil || user.ID != tc.id)) {
+				t.Fatalf("expected %v, got %+v, %v", tc.want, user, err)
+			}
+		})
+	}
+}
+
+func TestUserPolicyUpdate(t *testing.T) {
+	s, db := newPolicyService(t)
+	name := func(id uint) string {
+		var u model.User
+		db.First(&u, id)
+		return u.Name
+	}
+
+	if _, err := s.UpdateProfile(asUser(2), 2, UpdateProfileInput{Name: "Bình 2", Email: "binh@example.com"}); err != nil {
+		t.Fatalf("self update: %v", err)
+	}
+	if err := s.Update(asUser(1), 2, &model.User{Name: "hijacked"}); !errors.Is(err, auth.ErrForbidden) || name(2) != "Bình 2" {
+		t.Fatalf("other user: expected ErrForbidden, got %v (name %q)", err, name(2))
+	}
+	if err := s.Update(asUser(1, AdminRole), 2, &model.User{Name: "Bình 3"}); err != nil || name(2) != "Bình 3" {
+		t.Fatalf("admin update: %v (name %q)", err, name(2))
+	}
+
+	// Tự gán nhóm quyền cho mình = leo quyền
+	catalogue := uint(1)
+	if err := s.Update(asUser(2), 2, &model.User{UserCatalogueID: &catalogue}); !errors.Is(err, auth.ErrForbidden) {
+		t.Fatalf("self catalogue change: expected ErrForbidden, got %v", err)
+	}
+	// BulkUpdate không biết field nào bị đổi → chỉ admin
+	if _, err := s.BulkUpdate(asUser(2), map[string]any{"id": 2}, map[string]any{"name": "bulk"}); !errors.Is(err, auth.ErrForbidden) {
+		t.Fatalf("self bulk update: expected ErrForbidden, got %v", err)
+	}
+}
+
+func TestUserPolicyCreateAndDelete(t *testing.T) {
+	s, db := newPolicyService(t)
+
+	// Đăng ký không có principal vẫn phải được
+	in := RegisterInput{Name: "Dũng", Email: "dung@example.com", Password: "Str0ng-password!", PasswordConfirmation: "Str0ng-password!"}
+	if _, err := s.Register(context.Background(), in); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	catalogue := uint(1)
+	err := s.Create(context.Background(), &model.User{Name: "Em", Email: "em@example.com", Password: "x", UserCatalogueID: &catalogue})
+	if !errors.Is(err, auth.ErrUnauthenticated) {
+		t.Fatalf("anonymous create with catalogue: expected ErrUnauthenticated, got %v", err)
+	}
+
+	if err := s.Delete(asUser(2), 2); !errors.Is(err, auth.ErrForbidden) {
+		t.Fatalf("self delete: expected ErrForbidden, got %v", err)
+	}
+	if err := s.Delete(asUser(1, AdminRole), 2); err != nil {
+		t.Fatalf("admin delete: %v", err)
+	}
+	var n int64
+	db.Model(&model.User{}).Count(&n)
+	if n != 3 {
+		t.Fatalf("expected 3 users left, got %d", n)
+	}
+}
+
+func TestUserPolicyScope(t *testing.T) {
+	s, _ := newPolicyService(t)
+	ids := func(ctx context.Context) []uint {
+		t.Helper()
+		result, err := s.Paginate(ctx, common.Specs{Sort: "id"})
+		if err != nil {
+			t.Fatalf("paginate: %v", err)
+		}
+		out := make([]uint, len(result.Data))
+		for i, u := range result.Data {
+			out[i] = u.ID
+		}
+		return out
+	}
+
+	if got := ids(asUser(2)); !slices.Equal(got, []uint{2}) {
+		t.Fatalf("user: expected only self, got %v", got)
+	}
+	// Admin → Scope nil → không giới hạn
+	if got := ids(asUser(1, AdminRole)); !slices.Equal(got, []uint{1, 2, 3}) {
+		t.Fatalf("admin: expected every user, got %v", got)
+	}
+	service := auth.WithPrincipal(context.Background(), &auth.Principal{ID: "cron", Kind: auth.KindService})
+	if got := ids(service); len(got) != 3 {
+		t.Fatalf("service: expected every user, got %v", got)
+	}
+	if got := ids(context.Background()); len(got) != 0 {
+		t.Fatalf("anonymous: expected nothing, got %v", got)
+	}
+}
@@ -0,0 +1,353 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"golang-base/internal/auth"
+	"golang-base/internal/auth/session"
+	"golang-base/internal/event"
+	"golang-base/internal/model"
+	ur "golang-base/internal/repository/user"
+	"golang-base/internal/service/impl"
+	"golang-base/pkg/secure"
+	"golang-base/pkg/validation"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidCredentials — sai email hoặc mật khẩu (KHÔNG nói rõ cái nào sai → chống dò email)
+	ErrInvalidCredentials = errors.New("invalid credentials")
+
+	// ErrInvalidResetToken — token đặt lại mật khẩu sai / hết hạn / đã dùng
+	ErrInvalidResetToken = errors.New("invalid or expired reset token")
+)
+
+// ResetTokenTTL — thời hạn token đặt lại mật khẩu
+const ResetTokenTTL = time.Hour
+
+// PasswordResetRequested — publish lên event bus khi user yêu cầu đặt lại mật khẩu
+// Module mail subscribe để gửi link chứa Token (token gốc CHỈ có ở đây, DB lưu hash)
+type PasswordResetRequested struct {
+	UserID    uint
+	Name      string
+	Email     string
+	Token     string
+	ExpiresAt time.Time
+}
+
+// dummyHash — hash giả để so khi email không tồn tại
+// → thời gian phản hồi như nhau, không dò được email nào đã đăng ký
+var dummyHash = sync.OnceValue(func() string {
+	hash, _ := secure.HashPassword("dummy-password-for-timing")
+	return hash
+})
+
+// UserService — tài khoản người dùng: đăng ký, đăng nhập, hồ sơ, mật khẩu
+type UserService struct {
+	*impl.BaseService[model.User, uint]
+
+	db       *gorm.DB
+	users    *ur.UserRepository
+	resets   *ur.PasswordResetRepository
+	sessions *ur.SessionRepository
+	bus      *event.Bus
+	now      func() time.Time
+}
+
+func NewUserService(db *gorm.DB, bus *event.Bus) *UserService {
+	s := &UserService{
+		db:       db,
+		users:    ur.NewUserRepository(db),
+		resets:   ur.NewPasswordResetRepository(db),
+		sessions: ur.NewSessionRepository(db),
+		bus:      bus,
+		now:      time.Now,
+	}
+	s.BaseService = impl.NewBaseService[model.User, uint](s.users.BaseRepository, s).
+		UseTransaction().
+		WithPolicy(UserPolicy{}).
+		WithEventBus(bus)
+	return s
+}
+
+// ============================================================
+// HOOKS — chuẩn hóa email + hash mật khẩu trước khi ghi
+// Chạy cho MỌI đường ghi qua BaseService (đăng ký, admin tạo user...)
+// ============================================================
+
+func (s *UserService) BeforeCreate(tx *gorm.DB, user *model.User) error {
+	user.Email = normalizeEmail(user.Email)
+	return hashPassword(tx.Statement.Context, user)
+}
+
+func (s *UserService) BeforeUpdate(tx *gorm.DB, id uint, user *model.User) error {
+	user.Email = normalizeEmail(user.Email)
+	return hashPassword(tx.Statement.Context, user)
+}
+
+type hashedPasswordKey struct{}
+
+// WithHashedPassword — Password trong payload đã là bcrypt hash do code nội bộ dựng (seed, import từ hệ thống cũ...)
+// → hook ghi nguyên, không hash lại. Chỉ dùng cho đường nội bộ, KHÔNG gắn cho input từ client
+func WithHashedPassword(ctx context.Context) context.Context {
+	return context.WithValue(ctx, hashedPasswordKey{}, true)
+}
+
+// hashPassword — bỏ qua nếu rỗng (partial update)
+// Chuỗi trông như hash vẫn bị hash lại: client gửi "$2a$04$..." không được vượt rule độ dài + cost hiện tại
+// Trừ khi ctx đánh dấu WithHashedPassword VÀ giá trị đúng là bcrypt hash
+func hashPassword(ctx context.Context, user *model.User) error {
+	if user.Password == "" {
+		return nil
+	}
+	if trusted, _ := ctx.Value(hashedPasswordKey{}).(bool); trusted && secure.IsPasswordHash(user.Password) {
+		return nil
+	}
+	hash, err := secure.HashPassword(user.Password)
+	if err != nil {
+		return err
+	}
+	user.Password = hash
+	return nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// ============================================================
+// ĐĂNG KÝ / ĐĂNG NHẬP
+// ============================================================
+
+// Register — tạo tài khoản, email trùng → validation.Errors (rule unique trên model)
+func (s *UserService) Register(ctx context.Context, in RegisterInput) (*model.User, error) {
+	in.Email = normalizeEmail(in.Email)
+	if err := validation.Struct(ctx, &in); err != nil {
+		return nil, err
+	}
+
+	user := &model.User{
+		Name:     strings.TrimSpace(in.Name),
+		Email:    in.Email,
+		Password: in.Password,
+	}
+	if err := s.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// Login — kiểm tra email + mật khẩu, trả về user nếu đúng
+// Hash cũ (cost thấp) → hash lại bằng cost hiện tại
+func (s *UserService) Login(ctx context.Context, in LoginInput) (*model.User, error) {
+	in.Email = normalizeEmail(in.Email)
+	if err := validation.Struct(ctx, &in); err != nil {
+		return nil, err
+	}
+
+	user, err := s.users.WithTx(s.db.WithContext(ctx)).FindByEmail(in.Email)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		secure.CheckPassword(dummyHash(), in.Password)
+		return nil, ErrInvalidCredentials
+	}
+	if !secure.CheckPassword(user.Password, in.Password) {
+		return nil, ErrInvalidCredentials
+	}
+
+	if secure.NeedsRehash(user.Password) {
+		if hash, err := secure.HashPassword(in.Password); err == nil {
+			// Lỗi rehash không chặn đăng nhập, lần sau thử lại
+			_ = s.users.WithTx(s.db.WithContext(ctx)).UpdateFields(user.ID, map[string]any{"password": hash})
+		}
+	}
+	return user, nil
+}
+
+// ============================================================
+// HỒ SƠ
+// ============================================================
+
+// Profile — thông tin user đang đăng nhập
+func (s *UserService) Profile(ctx context.Context, id uint) (*model.User, error) {
+	user, err := s.FindById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrInvalidCredentials // token trỏ tới user đã bị xóa
+	}
+	return user, nil
+}
+
+// UpdateProfile — partial update tên / email (đổi mật khẩu dùng ChangePassword)
+func (s *UserService) UpdateProfile(ctx context.Context, id uint, in UpdateProfileInput) (*model.User, error) {
+	in.Email = normalizeEmail(in.Email)
+	if err := validation.Struct(ctx, &in); err != nil {
+		return nil, err
+	}
+	changes := &model.User{Name: strings.TrimSpace(in.Name), Email: in.Email}
+	if err := s.Update(ctx, id, changes); err != nil {
+		return nil, err
+	}
+	return s.Profile(ctx, id)
+}
+
+// ============================================================
+// MẬT KHẨU
+// ============================================================
+
+// ChangePassword — đổi mật khẩu khi đã đăng nhập, bắt buộc nhập đúng mật khẩu hiện tại
+func (s *UserService) ChangePassword(ctx context.Context, id uint, in ChangePasswordInput) error {
+	if err := validation.Struct(ctx, &in); err != nil {
+		return err
+	}
+
+	user, err := s.Profile(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !secure.CheckPassword(user.Password, in.CurrentPassword) {
+		return validation.Errors{validation.NewFieldError("current_password", "current_password", "")}
+	}
+	return s.setPassword(ctx, id, in.Password)
+}
+
+// RequestPasswordReset — sinh token đặt lại mật khẩu, publish PasswordResetRequested
+// Email không tồn tại → vẫn trả nil (không cho dò email đã đăng ký)
+func (s *UserService) RequestPasswordReset(ctx context.Context, in ForgotPasswordInput) error {
+	in.Email = normalizeEmail(in.Email)
+	if err := validation.Struct(ctx, &in); err != nil {
+		return err
+	}
+
+	user, err := s.users.WithTx(s.db.WithContext(ctx)).FindByEmail(in.Email)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return nil
+	}
+
+	token, err := secure.RandomToken(32)
+	if err != nil {
+		return err
+	}
+	now := s.now()
+	expiresAt := now.Add(ResetTokenTTL)
+
+	// Token mới thay thế mọi token cũ chưa dùng
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		resets := s.resets.WithTx(tx)
+		if err := resets.InvalidateUser(user.ID, now); err != nil {
+			return err
+		}
+		return resets.Create(&model.PasswordResetToken{
+			UserID:    user.ID,
+			TokenHash: secure.HashToken(token),
+			ExpiresAt: expiresAt,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("create reset token failed: %w", err)
+	}
+
+	event.Publish(ctx, s.bus, PasswordResetRequested{
+		UserID:    user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	})
+	return nil
+}
+
+// ResetPassword — đặt mật khẩu mới bằng token, token chỉ dùng được 1 lần
+func (s *UserService) ResetPassword(ctx context.Context, in ResetPasswordInput) error {
+	if err := validation.Struct(ctx, &in); err != nil {
+		return err
+	}
+
+	hash, err := secure.HashPassword(in.Password)
+	if err != nil {
+		return err
+	}
+
+	var (
+		userID  uint
+		revoked []string
+	)
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := s.now()
+		resets := s.resets.WithTx(tx)
+
+		token, err := resets.Consume(secure.HashToken(in.Token), now)
+		if err != nil {
+			return err
+		}
+		if token == nil {
+			return ErrInvalidResetToken
+		}
+		userID = token.UserID
+
+		if err := s.users.WithTx(tx).UpdateFields(userID, map[string]any{"password": hash}); err != nil {
+			return err
+		}
+		// Quên mật khẩu = có thể đã bị chiếm tài khoản → đăng xuất mọi thiết bị
+		if revoked, err = s.sessions.WithTx(tx).Revoke(now, "user_id = ?", userID); err != nil {
+			return err
+		}
+		return resets.InvalidateUser(userID, now)
+	})
+	if err != nil {
+		return err
+	}
+	s.publishRevoked(ctx, userID, revoked)
+	return nil
+}
+
+// setPassword — hash + lưu mật khẩu mới, vô hiệu token reset còn treo
+// Thu hồi mọi session khác của user, giữ session đang thao tác (principal.Attributes["session_id"])
+func (s *UserService) setPassword(ctx context.Context, id uint, password string) error {
+	hash, err := secure.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	var current string
+	if p, ok := auth.PrincipalFrom(ctx); ok {
+		current, _ = p.Attributes["session_id"].(string)
+	}
+
+	var revoked []string
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := s.now()
+		if err := s.users.WithTx(tx).UpdateFields(id, map[string]any{"password": hash}); err != nil {
+			return err
+		}
+		if revoked, err = s.sessions.WithTx(tx).Revoke(now, "user_id = ? AND id <> ?", id, current); err != nil {
+			return err
+		}
+		return s.resets.WithTx(tx).InvalidateUser(id, now)
+	})
+	if err != nil {
+		return err
+	}
+	s.publishRevoked(ctx, id, revoked)
+	return nil
+}
+
+// publishRevoked — báo revocation list cập nhật cache ngay
+func (s *UserService) publishRevoked(ctx context.Context, userID uint, sessionIDs []string) {
+	if len(sessionIDs) > 0 {
+		event.Publish(ctx, s.bus, session.Revoked{UserID: userID, SessionIDs: sessionIDs})
+	}
+}
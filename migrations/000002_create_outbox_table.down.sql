@@ -0,0 +1,2 @@
+-- xóa bảng outbox
+DROP TABLE IF EXISTS outbox;
@@ -0,0 +1,20 @@
+-- tạo bảng outbox — transactional outbox cho event publishing
+-- event được insert cùng transaction với data → không mất event khi crash
+CREATE TABLE IF NOT EXISTS outbox (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    aggregate_type VARCHAR(100) NOT NULL,
+    aggregate_id VARCHAR(100) NOT NULL,
+    event_type VARCHAR(150) NOT NULL,
+    payload JSON NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' COMMENT 'pending | sent | dead',
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT NULL,
+    available_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    processed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    -- dispatcher quét event pending theo thứ tự id
+    INDEX idx_outbox_status_available (status, available_at, id),
+    -- giữ thứ tự theo aggregate
+    INDEX idx_outbox_aggregate (aggregate_type, aggregate_id, status, id)
+);
@@ -0,0 +1,2 @@
+-- xóa bảng audit_logs
+DROP TABLE IF EXISTS audit_logs;
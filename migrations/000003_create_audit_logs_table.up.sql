@@ -0,0 +1,11 @@
+-- tạo bảng audit_logs — lịch sử thay đổi data do hooks.AuditHook ghi
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    auditable_type VARCHAR(100) NOT NULL,
+    auditable_id VARCHAR(100) NOT NULL DEFAULT '' COMMENT 'rỗng với bulk action',
+    action VARCHAR(50) NOT NULL,
+    changes JSON NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    -- xem lịch sử của 1 record
+    INDEX idx_audit_logs_auditable (auditable_type, auditable_id, id)
+);
@@ -0,0 +1,2 @@
+-- xóa bảng users
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,2 @@
+-- xóa bảng password_reset_tokens
+DROP TABLE IF EXISTS password_reset_tokens;
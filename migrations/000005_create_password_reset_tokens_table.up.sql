@@ -0,0 +1,13 @@
+-- tạo bảng password_reset_tokens — token đặt lại mật khẩu, dùng 1 lần, có hạn
+-- chỉ lưu SHA-256 của token → lộ DB cũng không dùng được token
+CREATE TABLE IF NOT EXISTS password_reset_tokens (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE INDEX uq_password_reset_tokens_hash (token_hash),
+    INDEX idx_password_reset_tokens_user (user_id),
+    CONSTRAINT fk_password_reset_tokens_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+-- xóa bảng refresh_tokens
+DROP TABLE IF EXISTS refresh_tokens;
@@ -0,0 +1,8 @@
+-- xóa bảng RBAC + cột users.user_catalogue_id
+DROP TABLE IF EXISTS user_catalogue_permissions;
+DROP TABLE IF EXISTS permissions;
+
+ALTER TABLE users
+    DROP FOREIGN KEY fk_users_user_catalogue,
+    DROP INDEX idx_users_user_catalogue,
+    DROP COLUMN user_catalogue_id;
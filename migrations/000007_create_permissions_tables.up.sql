@@ -0,0 +1,37 @@
+-- RBAC: user thuộc 1 user_catalogue, catalogue có nhiều permission
+ALTER TABLE users
+    ADD COLUMN user_catalogue_id BIGINT NULL AFTER password,
+    ADD INDEX idx_users_user_catalogue (user_catalogue_id),
+    ADD CONSTRAINT fk_users_user_catalogue FOREIGN KEY (user_catalogue_id) REFERENCES user_catalogues (id) ON DELETE SET NULL;
+
+-- tạo bảng permissions — name dạng "<resource>.<action>"
+CREATE TABLE IF NOT EXISTS permissions (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE INDEX uq_permissions_name (name)
+);
+
+-- tạo bảng pivot user_catalogue_permissions
+CREATE TABLE IF NOT EXISTS user_catalogue_permissions (
+    user_catalogue_id BIGINT NOT NULL,
+    permission_id BIGINT UNSIGNED NOT NULL,
+    PRIMARY KEY (user_catalogue_id, permission_id),
+    INDEX idx_user_catalogue_permissions_permission (permission_id),
+    CONSTRAINT fk_user_catalogue_permissions_catalogue FOREIGN KEY (user_catalogue_id) REFERENCES user_catalogues (id) ON DELETE CASCADE,
+    CONSTRAINT fk_user_catalogue_permissions_permission FOREIGN KEY (permission_id) REFERENCES permissions (id) ON DELETE CASCADE
+);
+
+-- permission mặc định
+INSERT INTO permissions (name, description) VALUES
+    ('user.view', 'Xem danh sách user'),
+    ('user.create', 'Tạo user'),
+    ('user.update', 'Cập nhật user'),
+    ('user.delete', 'Xóa user'),
+    ('catalogue.view', 'Xem nhóm user'),
+    ('catalogue.create', 'Tạo nhóm user'),
+    ('catalogue.update', 'Cập nhật nhóm user, gán permission'),
+    ('catalogue.delete', 'Xóa nhóm user'),
+    ('permission.view', 'Xem danh sách permission');
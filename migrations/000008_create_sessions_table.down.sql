@@ -0,0 +1,3 @@
+-- xóa bảng sessions
+DELETE FROM permissions WHERE name IN ('session.view', 'session.revoke');
+DROP TABLE IF EXISTS sessions;
@@ -0,0 +1,19 @@
+-- tạo bảng sessions — mỗi lần đăng nhập 1 session, id = family_id của refresh token
+CREATE TABLE IF NOT EXISTS sessions (
+    id CHAR(36) PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    device VARCHAR(255) NULL,
+    user_agent VARCHAR(512) NULL,
+    ip VARCHAR(45) NULL,
+    last_seen_at TIMESTAMP NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_sessions_user (user_id, revoked_at),
+    CONSTRAINT fk_sessions_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+-- permission quản lý session của user khác
+INSERT INTO permissions (name, description) VALUES
+    ('session.view', 'Xem session đăng nhập của user'),
+    ('session.revoke', 'Thu hồi session đăng nhập của user');
@@ -0,0 +1,3 @@
+-- xóa bảng api_keys
+DELETE FROM permissions WHERE name IN ('apikey.view', 'apikey.manage');
+DROP TABLE IF EXISTS api_keys;
@@ -0,0 +1,6 @@
+-- xóa bảng 2FA
+DROP TABLE IF EXISTS two_factor_challenges;
+DROP TABLE IF EXISTS two_factor_recovery_codes;
+DROP TABLE IF EXISTS user_two_factors;
+ALTER TABLE sessions DROP COLUMN two_factor_at;
+ALTER TABLE user_catalogues DROP COLUMN require_two_factor;
@@ -0,0 +1,44 @@
+-- bắt buộc 2FA theo nhóm user
+ALTER TABLE user_catalogues
+    ADD COLUMN require_two_factor TINYINT(1) NOT NULL DEFAULT 0 AFTER publish;
+
+-- session đã qua bước 2FA lúc nào (NULL = chỉ có mật khẩu)
+ALTER TABLE sessions
+    ADD COLUMN two_factor_at TIMESTAMP NULL AFTER revoked_at;
+
+-- TOTP của user, secret đã mã hóa, confirmed_at NULL = đang enrol
+CREATE TABLE IF NOT EXISTS user_two_factors (
+    user_id BIGINT UNSIGNED PRIMARY KEY,
+    secret VARCHAR(255) NOT NULL,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    confirmed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    CONSTRAINT fk_user_two_factors_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+-- mã dự phòng dùng 1 lần, chỉ lưu SHA-256
+CREATE TABLE IF NOT EXISTS two_factor_recovery_codes (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    code_hash CHAR(64) NOT NULL,
+    used_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE INDEX uq_two_factor_recovery_codes_hash (code_hash),
+    INDEX idx_two_factor_recovery_codes_user (user_id),
+    CONSTRAINT fk_two_factor_recovery_codes_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+-- đăng nhập đúng mật khẩu, chờ nhập mã 2FA (pending token chỉ lưu hash)
+CREATE TABLE IF NOT EXISTS two_factor_challenges (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE INDEX uq_two_factor_challenges_token (token_hash),
+    INDEX idx_two_factor_challenges_user (user_id),
+    CONSTRAINT fk_two_factor_challenges_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
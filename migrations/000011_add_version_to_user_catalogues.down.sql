@@ -0,0 +1,2 @@
+-- xóa cột version
+ALTER TABLE user_catalogues DROP COLUMN version;
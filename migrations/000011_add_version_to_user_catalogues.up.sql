@@ -0,0 +1,3 @@
+-- version của record, tăng mỗi lần ghi → ETag / If-Match (updated_at TIMESTAMP chỉ chính xác tới giây)
+ALTER TABLE user_catalogues
+    ADD COLUMN version INT UNSIGNED NOT NULL DEFAULT 1 AFTER updated_at;
@@ -0,0 +1,121 @@
+// Package apperror — lỗi ứng dụng có mã ổn định cho client
+//
+// Mỗi lỗi mang: HTTP status + code (VD: CATALOGUE_SLUG_TAKEN) + message key (i18n) + details
+// Code được khai báo 1 lần bằng Define → tự vào catalog (GET /v1/errors)
+//
+//	var ErrSlugTaken = apperror.Define(422, "CATALOGUE_SLUG_TAKEN", "catalogue.slug_taken", "slug already taken").
+//		Match(us.ErrSlugTaken)
+//
+// Match gắn sentinel của tầng dưới (service, repository) vào code → service không cần biết HTTP
+// From(err) tìm code phù hợp cho 1 lỗi bất kỳ, không khớp → INTERNAL_ERROR (không lộ chi tiết)
+package apperror
+
+import (
+	"errors"
+
+	"golang-base/pkg/validation"
+)
+
+// Error — lỗi ứng dụng, giá trị bất biến: WithDetails / Wrap trả về bản sao
+type Error struct {
+	Status  int    // HTTP status
+	Code    string // mã cho client, VD: CATALOGUE_SLUG_TAKEN
+	Key     string // message key cho i18n, VD: catalogue.slug_taken
+	Message string // message mặc định (tiếng Anh)
+	Details any    // lỗi field, thông tin thêm... nil = không có
+
+	cause error // lỗi gốc, chỉ để log / errors.Is, không trả về client
+}
+
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return e.Code + ": " + e.cause.Error()
+	}
+	return e.Code + ": " + e.Message
+}
+
+func (e *Error) Unwrap() error { return e.cause }
+
+// Is — so theo code → errors.Is(err, apperror.ErrNotFound) đúng cả với bản sao đã Wrap / WithDetails
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WithDetails — bản sao kèm details
+func (e *Error) WithDetails(details any) *Error {
+	clone := *e
+	clone.Details = details
+	return &clone
+}
+
+// Wrap — bản sao kèm lỗi gốc
+func (e *Error) Wrap(err error) *Error {
+	clone := *e
+	clone.cause = err
+	return &clone
+}
+
+// Localize — bản sao với message lỗi field theo ngôn ngữ (details là validation.Errors), còn lại giữ nguyên
+func (e *Error) Localize(lang string) *Error {
+	verrs, ok := e.Details.(validation.Errors)
+	if !ok {
+		return e
+	}
+	return e.WithDetails(verrs.Localize(lang))
+}
+
+// Match — lỗi nào errors.Is với 1 trong các sentinel → From trả về e
+func (e *Error) Match(sentinels ...error) *Error {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	for _, sentinel := range sentinels {
+		registry.matches = append(registry.matches, match{sentinel: sentinel, err: e})
+	}
+	return e
+}
+
+// From — map lỗi bất kỳ sang *Error
+//
+//	*Error (kể cả bị wrap)     → giữ nguyên
+//	sentinel đã Match           → code đã gắn
+//	validation.Errors           → VALIDATION_FAILED
+//	còn lại                     → INTERNAL_ERROR
+//
+// Trong chuỗi lỗi có validation.Errors mà code chưa có details → details = lỗi field
+func From(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	var verrs validation.Errors
+	hasFieldErrors := errors.As(err, &verrs)
+
+	var e *Error
+	if !errors.As(err, &e) {
+		switch matched := lookup(err); {
+		case matched != nil:
+			e = matched.Wrap(err)
+		case hasFieldErrors:
+			e = ErrValidation.Wrap(err)
+		default:
+			e = ErrInternal.Wrap(err)
+		}
+	}
+	if e.Details == nil && hasFieldErrors {
+		e = e.WithDetails(verrs)
+	}
+	return e
+}
+
+// lookup — sentinel Match sau cùng thắng (module override được mapping chung)
+func lookup(err error) *Error {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	for i := len(registry.matches) - 1; i >= 0; i-- {
+		if errors.Is(err, registry.matches[i].sentinel) {
+			return registry.matches[i].err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package apperror
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ============================================================
+// CATALOG — mọi code đã Define, client đọc qua GET /v1/errors
+// Code là hợp đồng với client: đã phát hành thì KHÔNG đổi tên / đổi nghĩa
+// ============================================================
+
+type match struct {
+	sentinel error
+	err      *Error
+}
+
+var registry = struct {
+	mu      sync.RWMutex
+	codes   map[string]*Error
+	matches []match
+}{codes: make(map[string]*Error)}
+
+// Define — khai báo code mới (gọi ở khai báo biến package), trùng code → panic lúc khởi động
+func Define(status int, code, key, message string) *Error {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if _, ok := registry.codes[code]; ok {
+		panic(fmt.Sprintf("apperror: code %s defined twice", code))
+	}
+	e := &Error{Status: status, Code: code, Key: key, Message: message}
+	registry.codes[code] = e
+	return e
+}
+
+// Entry — 1 dòng của catalog
+type Entry struct {
+	Code       string `json:"code"`
+	Status     int    `json:"status"`
+	MessageKey string `json:"message_key"`
+	Message    string `json:"message"`
+}
+
+// Catalog — toàn bộ code, sắp xếp theo code
+func Catalog() []Entry {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	entries := make([]Entry, 0, len(registry.codes))
+	for _, e := range registry.codes {
+		entries = append(entries, Entry{Code: e.Code, Status: e.Status, MessageKey: e.Key, Message: e.Message})
+	}
+	slices.SortFunc(entries, func(a, b Entry) int { return strings.Compare(a.Code, b.Code) })
+	return entries
+}
+
+// ============================================================
+// CODE CHUNG — module định nghĩa code riêng cạnh controller của mình
+// ============================================================
+
+var (
+	ErrBadRequest         = Define(http.StatusBadRequest, "BAD_REQUEST", "error.bad_request", "bad request")
+	ErrInvalidBody        = Define(http.StatusBadRequest, "INVALID_BODY", "error.invalid_body", "invalid request body")
+	ErrInvalidQuery       = Define(http.StatusBadRequest, "INVALID_QUERY", "error.invalid_query", "invalid query parameters")
+	ErrInvalidID          = Define(http.StatusBadRequest, "INVALID_ID", "error.invalid_id", "invalid id")
+	ErrUnauthenticated    = Define(http.StatusUnauthorized, "UNAUTHENTICATED", "error.unauthenticated", "unauthorized")
+	ErrForbidden          = Define(http.StatusForbidden, "FORBIDDEN", "error.forbidden", "forbidden")
+	ErrNotFound           = Define(http.StatusNotFound, "NOT_FOUND", "error.not_found", "record not found")
+	ErrRouteNotFound      = Define(http.StatusNotFound, "ROUTE_NOT_FOUND", "error.route_not_found", "route not found")
+	ErrConflict           = Define(http.StatusConflict, "CONFLICT", "error.conflict", "record already exists")
+	ErrPreconditionFailed = Define(http.StatusPreconditionFailed, "PRECONDITION_FAILED", "error.precondition_failed", "resource has been modified, reload and retry")
+	ErrValidation         = Define(http.StatusUnprocessableEntity, "VALIDATION_FAILED", "error.validation_failed", "validation failed")
+	ErrTooManyRequests    = Define(http.StatusTooManyRequests, "TOO_MANY_REQUESTS", "error.too_many_requests", "too many requests, please try again later")
+	ErrInternal           = Define(http.StatusInternalServerError, "INTERNAL_ERROR", "error.internal", "internal server error")
+)
@@ -8,6 +8,9 @@ const (
 	HTTP_FORBIDDEN                       = 403
 	HTTP_NOT_FOUND                       = 404
 	HTTP_METHOD_NOT_ALLOWED              = 405
+	HTTP_CONFLICT                        = 409
+	HTTP_UNPROCESSABLE_ENTITY            = 422
+	HTTP_TOO_MANY_REQUESTS               = 429
 	HTTP_INTERNAL_SERVER_ERROR           = 500
 	HTTP_NOT_IMPLEMENTED                 = 501
 	HTTP_BAD_GATEWAY                     = 502
@@ -0,0 +1,142 @@
+package pkg
+
+import (
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================================
+// PROBLEM DETAILS (RFC 7807) — định dạng lỗi thay thế cho envelope APIResponse
+//
+//	HTTP/1.1 422 Unprocessable Entity
+//	Content-Type: application/problem+json
+//
+//	{
+//	  "type": "/v1/errors#CATALOGUE_SLUG_TAKEN",
+//	  "title": "catalogue slug already taken",
+//	  "status": 422,
+//	  "detail": "...",                       // chỉ khi errors là chuỗi
+//	  "instance": "/v1/user-catalogues",
+//	  "code": "CATALOGUE_SLUG_TAKEN",
+//	  "errors": [{"field": "slug", ...}],    // lỗi field / details dạng object
+//	  "request_id": "0190b6a1-..."
+//	}
+//
+// Chọn định dạng (NegotiateFormat), ưu tiên từ trên xuống:
+//  1. Accept ưu tiên application/problem+json (q không thấp hơn application/json) → problem
+//  2. Route group gắn UseFormat(...) → định dạng của group
+//  3. Mặc định → envelope
+//
+// Response thành công KHÔNG đổi, luôn là envelope
+// ============================================================
+
+// Format — định dạng response lỗi
+type Format string
+
+const (
+	FormatEnvelope Format = "envelope" // APIResponse{status, code, message, error_code, errors}
+	FormatProblem  Format = "problem"  // application/problem+json
+
+	ProblemContentType = "application/problem+json"
+
+	formatKey      = "response.format"
+	problemTypeKey = "response.problem_type"
+)
+
+// Problem — body của application/problem+json
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	// Extension members
+	Code      string `json:"code,omitempty"`       // = error_code của envelope
+	Errors    any    `json:"errors,omitempty"`     // lỗi field / details không phải chuỗi
+	RequestID string `json:"request_id,omitempty"` // X-Request-ID (RequestLogger)
+}
+
+// UseFormat — middleware gắn định dạng lỗi mặc định cho route group
+//
+//	partner := v1.Group("/partner", response.UseFormat(response.FormatProblem))
+func UseFormat(format Format) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(formatKey, format)
+		c.Next()
+	}
+}
+
+// ProblemTypes — middleware gắn tiền tố "type" của problem cho route group: base + error_code
+// Không gắn hoặc lỗi không có error_code → "about:blank" (RFC 7807: lỗi chỉ mang ý nghĩa của HTTP status)
+//
+//	v1 := r.Group("/v1", response.ProblemTypes("/v1/errors#")) // → trỏ về catalog mã lỗi
+func ProblemTypes(base string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(problemTypeKey, base)
+		c.Next()
+	}
+}
+
+// NegotiateFormat — định dạng lỗi cho request hiện tại
+func NegotiateFormat(c *gin.Context) Format {
+	if acceptsProblem(c.GetHeader("Accept")) {
+		return FormatProblem
+	}
+	if format, ok := c.Get(formatKey); ok {
+		if f, ok := format.(Format); ok {
+			return f
+		}
+	}
+	return FormatEnvelope
+}
+
+// acceptsProblem — Accept có application/problem+json với q > 0 và không thấp hơn application/json
+// VD: "application/problem+json" → true, "application/json, application/problem+json;q=0.5" → false
+func acceptsProblem(accept string) bool {
+	problem, plain := -1.0, -1.0
+	for _, item := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(item))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if raw, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(raw, 64); err != nil {
+				continue
+			}
+		}
+		switch mediaType {
+		case ProblemContentType:
+			problem = max(problem, q)
+		case "application/json":
+			plain = max(plain, q)
+		}
+	}
+	return problem > 0 && problem >= plain
+}
+
+func writeProblem(c *gin.Context, status int, errorCode, message string, errors any) {
+	problem := Problem{
+		Type:      "about:blank",
+		Title:     message,
+		Status:    status,
+		Instance:  c.Request.URL.Path,
+		Code:      errorCode,
+		RequestID: c.GetString("request_id"),
+	}
+	if base := c.GetString(problemTypeKey); errorCode != "" && base != "" {
+		problem.Type = base + errorCode
+	}
+	if detail, ok := errors.(string); ok {
+		problem.Detail = detail
+	} else {
+		problem.Errors = errors
+	}
+
+	c.Header("Content-Type", ProblemContentType) // c.JSON giữ Content-Type đã set
+	c.JSON(status, problem)
+}
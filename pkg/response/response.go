@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"golang-base/pkg/apperror"
 )
 
 // APIResponse là cấu trúc chuẩn cho tất cả HTTP response
@@ -13,6 +15,7 @@ type APIResponse struct {
 	Code          int    `json:"code"`
 	Message       string `json:"message"`
 	Data          any    `json:"data,omitempty"`
+	ErrorCode     string `json:"error_code,omitempty"` // mã lỗi ổn định (apperror), VD: CATALOGUE_SLUG_TAKEN
 	Errors        any    `json:"errors,omitempty"`
 	ExecutionTime string `json:"execution_time"` // Thời gian xử lý request (ms)
 }
@@ -34,8 +37,17 @@ func getExecutionTime(c *gin.Context) string {
 	return fmt.Sprintf("%.3fms", float64(duration.Nanoseconds())/1e6)
 }
 
+// Transformer — data tự biến đổi trước khi trả về (serializer, DTO mapping...)
+// Success gọi Transform(c) → data có thể ẩn/hiện field theo user, ?fields=...
+type Transformer interface {
+	Transform(c *gin.Context) any
+}
+
 // Success trả về response thành công kèm data
 func Success(c *gin.Context, code int, message string, data any) {
+	if t, ok := data.(Transformer); ok {
+		data = t.Transform(c)
+	}
 	c.JSON(code, APIResponse{
 		Status:        true,
 		Code:          code,
@@ -47,10 +59,27 @@ func Success(c *gin.Context, code int, message string, data any) {
 
 // Error trả về response lỗi kèm errors
 func Error(c *gin.Context, code int, message string, errors any) {
-	c.JSON(code, APIResponse{
+	writeError(c, code, "", message, errors)
+}
+
+// AppError trả về response lỗi theo apperror: status + error_code + message mặc định, details → errors
+func AppError(c *gin.Context, e *apperror.Error) {
+	writeError(c, e.Status, e.Code, e.Message, e.Details)
+}
+
+// writeError — mọi response lỗi đi qua đây: envelope APIResponse hoặc problem+json (xem problem.go)
+func writeError(c *gin.Context, status int, errorCode, message string, errors any) {
+	// Cùng URL có thể trả 2 định dạng → cache phải phân biệt theo Accept
+	c.Writer.Header().Add("Vary", "Accept")
+	if NegotiateFormat(c) == FormatProblem {
+		writeProblem(c, status, errorCode, message, errors)
+		return
+	}
+	c.JSON(status, APIResponse{
 		Status:        false,
-		Code:          code,
+		Code:          status,
 		Message:       message,
+		ErrorCode:     errorCode,
 		Errors:        errors,
 		ExecutionTime: getExecutionTime(c),
 	})
@@ -61,12 +90,37 @@ func OK(c *gin.Context, data any) {
 	Success(c, HTTP_OK, "success", data)
 }
 
+// Created - 201
+func Created(c *gin.Context, data any) {
+	Success(c, HTTP_CREATED, "created", data)
+}
+
 // BadRequest - 400
 func BadRequest(c *gin.Context, errors any) {
-	Error(c, HTTP_BAD_REQUEST, "bad request", errors)
+	AppError(c, apperror.ErrBadRequest.WithDetails(errors))
+}
+
+// Unauthorized - 401
+func Unauthorized(c *gin.Context, errors any) {
+	AppError(c, apperror.ErrUnauthenticated.WithDetails(errors))
+}
+
+// Forbidden - 403
+func Forbidden(c *gin.Context, errors any) {
+	AppError(c, apperror.ErrForbidden.WithDetails(errors))
+}
+
+// NotFound - 404
+func NotFound(c *gin.Context, errors any) {
+	AppError(c, apperror.ErrNotFound.WithDetails(errors))
+}
+
+// UnprocessableEntity - 422 (data sai rule validate)
+func UnprocessableEntity(c *gin.Context, errors any) {
+	AppError(c, apperror.ErrValidation.WithDetails(errors))
 }
 
 // InternalServerError - 500
 func InternalServerError(c *gin.Context, errors any) {
-	Error(c, HTTP_INTERNAL_SERVER_ERROR, "internal server error", errors)
+	AppError(c, apperror.ErrInternal.WithDetails(errors))
 }
@@ -0,0 +1,64 @@
+package secure
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// MinCipherKeyLength — độ dài tối thiểu của key mã hóa (byte)
+const MinCipherKeyLength = 32
+
+// ErrDecrypt — dữ liệu bị sửa / sai key
+var ErrDecrypt = errors.New("decrypt failed")
+
+// Cipher — mã hóa đối xứng AES-256-GCM cho dữ liệu cần đọc lại (TOTP secret...)
+// Khác HashToken: hash không giải ngược được, Cipher thì có (cần key)
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher — key là chuỗi bí mật trong config, >= 32 byte (SHA-256 → key AES-256)
+func NewCipher(key string) (*Cipher, error) {
+	if len(key) < MinCipherKeyLength {
+		return nil, fmt.Errorf("cipher key must be at least %d bytes", MinCipherKeyLength)
+	}
+	sum := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, fmt.Errorf("init cipher failed: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("init cipher failed: %w", err)
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// Encrypt — base64url(nonce || ciphertext), mỗi lần gọi ra kết quả khác nhau
+func (c *Cipher) Encrypt(plain string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generate nonce failed: %w", err)
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(plain), nil)
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt — ngược lại Encrypt, sai key / dữ liệu bị sửa → ErrDecrypt
+func (c *Cipher) Decrypt(sealed string) (string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(sealed)
+	if err != nil || len(b) < c.aead.NonceSize() {
+		return "", ErrDecrypt
+	}
+	nonce, ciphertext := b[:c.aead.NonceSize()], b[c.aead.NonceSize():]
+	plain, err := c.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", ErrDecrypt
+	}
+	return string(plain), nil
+}
@@ -0,0 +1,45 @@
+// Package secure — hash mật khẩu, sinh/hash token ngẫu nhiên
+package secure
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordCost — cost bcrypt, mỗi +1 = chậm gấp đôi (~250ms với cost 12 trên CPU phổ thông)
+const PasswordCost = 12
+
+// ErrPasswordTooLong — bcrypt chỉ dùng 72 byte đầu, dài hơn bị cắt âm thầm → chặn luôn
+var ErrPasswordTooLong = errors.New("password exceeds 72 bytes")
+
+// HashPassword — bcrypt hash, mỗi lần gọi ra kết quả khác nhau (salt ngẫu nhiên)
+func HashPassword(plain string) (string, error) {
+	if len(plain) > 72 {
+		return "", ErrPasswordTooLong
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(plain), PasswordCost)
+	if err != nil {
+		return "", fmt.Errorf("hash password failed: %w", err)
+	}
+	return string(hash), nil
+}
+
+// CheckPassword — so khớp mật khẩu với hash, so sánh constant-time
+func CheckPassword(hash, plain string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(plain)) == nil
+}
+
+// IsPasswordHash — chuỗi đã là bcrypt hash chưa
+// KHÔNG dùng để quyết định có hash input của client hay không (client gửi được chuỗi dạng hash)
+func IsPasswordHash(s string) bool {
+	_, err := bcrypt.Cost([]byte(s))
+	return err == nil
+}
+
+// NeedsRehash — hash cũ dùng cost thấp hơn hiện tại → nên hash lại khi user đăng nhập thành công
+func NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost < PasswordCost
+}
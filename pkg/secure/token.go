@@ -0,0 +1,26 @@
+package secure
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// RandomToken — token ngẫu nhiên n byte, encode base64url (an toàn khi đặt trong URL)
+// n = 32 → 256 bit entropy, đủ cho reset token / refresh token / API key
+func RandomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate token failed: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken — SHA-256 hex của token, dùng để lưu DB / tra cứu
+// Token đã đủ entropy → không cần bcrypt, SHA-256 cho phép lookup bằng index
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,73 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"time"
+)
+
+// BodyField — tên field của lỗi thuộc về cả body (JSON hỏng, body rỗng, sai kiểu gốc)
+const BodyField = "body"
+
+// FromJSON — lỗi decode JSON của request body → Errors cùng format với lỗi validate
+//
+//	body rỗng                    → {"field": "body", "rule": "required"}
+//	JSON hỏng (thiếu ngoặc, ...) → {"field": "body", "rule": "json"}
+//	sai kiểu ("age": "abc")      → {"field": "age", "rule": "type", "param": "number"}
+//
+// false nếu err không phải lỗi decode (caller tự xử lý)
+func FromJSON(err error) (Errors, bool) {
+	var (
+		syntax   *json.SyntaxError
+		mismatch *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.Is(err, io.EOF):
+		return Errors{NewFieldError(BodyField, "required", "")}, true
+	case errors.As(err, &syntax), errors.Is(err, io.ErrUnexpectedEOF):
+		return Errors{NewFieldError(BodyField, "json", "")}, true
+	case errors.As(err, &mismatch):
+		field := mismatch.Field
+		if field == "" {
+			field = BodyField
+		}
+		return Errors{NewFieldError(field, "type", jsonType(mismatch.Type))}, true
+	}
+	return nil, false
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// jsonType — kiểu Go → tên kiểu JSON client hiểu được
+// VD: uint → number, []string → array, map[string]any → object
+func jsonType(t reflect.Type) string {
+	if t == nil {
+		return "value"
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return "string"
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "string" // []byte ↔ base64
+		}
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return t.String()
+}
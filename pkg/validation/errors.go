@@ -0,0 +1,61 @@
+package validation
+
+import (
+	"strings"
+)
+
+// FieldError — lỗi của 1 field, trả thẳng về client trong APIResponse.Errors
+//
+//	{"field": "name", "rule": "max", "param": "255", "message": "name tối đa 255 ký tự"}
+type FieldError struct {
+	Field   string `json:"field"`           // tên field theo json tag, lồng nhau: "items[0].name"
+	Rule    string `json:"rule"`            // tên rule: required, max, unique...
+	Param   string `json:"param,omitempty"` // tham số của rule: "255", "admin user"...
+	Message string `json:"message"`
+}
+
+// NewFieldError — tạo lỗi với message lấy từ bảng message theo DefaultLanguage
+// Dùng trong Validatable để lỗi tự định nghĩa có cùng format với lỗi từ tag
+func NewFieldError(field, rule, param string) FieldError {
+	return FieldError{
+		Field:   field,
+		Rule:    rule,
+		Param:   param,
+		Message: Message(DefaultLanguage, field, rule, param),
+	}
+}
+
+// Errors — tập lỗi validate, implement error
+// Caller phân biệt lỗi validate với lỗi khác bằng errors.As(err, &validation.Errors{})
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, fe := range e {
+		messages[i] = fe.Message
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// Localize — dựng lại message theo ngôn ngữ (vi, en), ngôn ngữ lạ → DefaultLanguage
+func (e Errors) Localize(lang string) Errors {
+	localized := make(Errors, len(e))
+	for i, fe := range e {
+		fe.Message = Message(lang, fe.Field, fe.Rule, fe.Param)
+		localized[i] = fe
+	}
+	return localized
+}
+
+// Prefix — thêm tiền tố cho tên field (dùng khi validate từng phần tử của bulk)
+// VD: Prefix("[3]"): "name" → "[3].name", lỗi cả body (BodyField) → "[3]"
+func (e Errors) Prefix(p string) Errors {
+	for i := range e {
+		if e[i].Field == BodyField {
+			e[i].Field = p
+		} else {
+			e[i].Field = p + "." + e[i].Field
+		}
+	}
+	return e
+}
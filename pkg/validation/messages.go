@@ -0,0 +1,158 @@
+package validation
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultLanguage — ngôn ngữ message mặc định khi chưa Localize
+const DefaultLanguage = "vi"
+
+// messages — bảng message theo ngôn ngữ → rule
+// {field}: tên field, {param}: tham số của rule
+var (
+	messagesMu sync.RWMutex
+	messages   = map[string]map[string]string{
+		"vi": {
+			"required":         "{field} là bắt buộc",
+			"required_if":      "{field} là bắt buộc",
+			"required_with":    "{field} là bắt buộc khi có {param}",
+			"required_without": "{field} là bắt buộc khi không có {param}",
+			"min":              "{field} tối thiểu {param} ký tự",
+			"max":              "{field} tối đa {param} ký tự",
+			"len":              "{field} phải đúng {param} ký tự",
+			"gt":               "{field} phải lớn hơn {param}",
+			"gte":              "{field} phải lớn hơn hoặc bằng {param}",
+			"lt":               "{field} phải nhỏ hơn {param}",
+			"lte":              "{field} phải nhỏ hơn hoặc bằng {param}",
+			"oneof":            "{field} phải là một trong: {param}",
+			"email":            "{field} không đúng định dạng email",
+			"url":              "{field} không đúng định dạng URL",
+			"uuid":             "{field} không đúng định dạng UUID",
+			"numeric":          "{field} phải là số",
+			"alphanum":         "{field} chỉ được chứa chữ và số",
+			"eqfield":          "{field} phải trùng với {param}",
+			"nefield":          "{field} phải khác {param}",
+			"gtfield":          "{field} phải lớn hơn {param}",
+			"gtefield":         "{field} phải lớn hơn hoặc bằng {param}",
+			"ltfield":          "{field} phải nhỏ hơn {param}",
+			"ltefield":         "{field} phải nhỏ hơn hoặc bằng {param}",
+			"unique":           "{field} đã tồn tại",
+			"slug":             "{field} chỉ gồm chữ thường, số và dấu gạch ngang",
+			"current_password": "{field} không đúng",
+			"exists":           "{field} không tồn tại",
+			"otp":              "{field} không đúng hoặc đã hết hạn",
+			"json":             "{field} không phải JSON hợp lệ",
+			"type":             "{field} phải có kiểu {param}",
+			"writable":         "{field} không tồn tại hoặc không được phép cập nhật",
+			"default":          "{field} không hợp lệ",
+		},
+		"en": {
+			"required":         "{field} is required",
+			"required_if":      "{field} is required",
+			"required_with":    "{field} is required when {param} is present",
+			"required_without": "{field} is required when {param} is absent",
+			"min":              "{field} must be at least {param} characters",
+			"max":              "{field} must be at most {param} characters",
+			"len":              "{field} must be exactly {param} characters",
+			"gt":               "{field} must be greater than {param}",
+			"gte":              "{field} must be greater than or equal to {param}",
+			"lt":               "{field} must be less than {param}",
+			"lte":              "{field} must be less than or equal to {param}",
+			"oneof":            "{field} must be one of: {param}",
+			"email":            "{field} must be a valid email",
+			"url":              "{field} must be a valid URL",
+			"uuid":             "{field} must be a valid UUID",
+			"numeric":          "{field} must be numeric",
+			"alphanum":         "{field} must contain only letters and numbers",
+			"eqfield":          "{field} must match {param}",
+			"nefield":          "{field} must differ from {param}",
+			"gtfield":          "{field} must be greater than {param}",
+			"gtefield":         "{field} must be greater than or equal to {param}",
+			"ltfield":          "{field} must be less than {param}",
+			"ltefield":         "{field} must be less than or equal to {param}",
+			"unique":           "{field} already exists",
+			"slug":             "{field} may only contain lowercase letters, numbers and dashes",
+			"current_password": "{field} is incorrect",
+			"exists":           "{field} does not exist",
+			"otp":              "{field} is invalid or expired",
+			"json":             "{field} must be valid JSON",
+			"type":             "{field} must be of type {param}",
+			"writable":         "{field} is unknown or not writable",
+			"default":          "{field} is invalid",
+		},
+	}
+)
+
+// RegisterMessage — thêm/ghi đè message cho 1 rule (rule tự định nghĩa, hoặc đổi câu chữ)
+// VD: RegisterMessage("vi", "phone", "{field} không phải số điện thoại hợp lệ")
+func RegisterMessage(lang, rule, message string) {
+	messagesMu.Lock()
+	defer messagesMu.Unlock()
+	if messages[lang] == nil {
+		messages[lang] = make(map[string]string)
+	}
+	messages[lang][rule] = message
+}
+
+// Message — dựng message cho 1 lỗi, fallback: rule → "default", lang → DefaultLanguage
+func Message(lang, field, rule, param string) string {
+	messagesMu.RLock()
+	table, ok := messages[normalizeLanguage(lang)]
+	if !ok {
+		table = messages[DefaultLanguage]
+	}
+	template, ok := table[rule]
+	if !ok {
+		template = table["default"]
+	}
+	messagesMu.RUnlock()
+
+	return strings.NewReplacer("{field}", field, "{param}", param).Replace(template)
+}
+
+// Language — chọn ngôn ngữ message từ header Accept-Language (theo q, giảm dần)
+// VD: "en-US,en;q=0.9,vi;q=0.8" → "en", "fr-FR" / "" / "*" → DefaultLanguage
+func Language(acceptLanguage string) string {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+	var candidates []candidate
+	for _, item := range strings.Split(acceptLanguage, ",") {
+		lang, params, _ := strings.Cut(strings.TrimSpace(item), ";")
+		q := 1.0
+		if raw, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{lang: normalizeLanguage(lang), q: q})
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b candidate) int { return cmp.Compare(b.q, a.q) })
+
+	messagesMu.RLock()
+	defer messagesMu.RUnlock()
+	for _, c := range candidates {
+		if _, ok := messages[c.lang]; ok {
+			return c.lang
+		}
+	}
+	return DefaultLanguage
+}
+
+// normalizeLanguage — "en-US" → "en", "VI" → "vi"
+func normalizeLanguage(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "-_"); i > 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
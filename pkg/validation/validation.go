@@ -0,0 +1,227 @@
+// Package validation — validate struct theo tag `validate`, trả về lỗi có cấu trúc
+//
+//	type UserCatalogue struct {
+//		Name string `validate:"required,min=2,max=255"`
+//		Slug string `validate:"omitempty,slug,unique"`
+//		Role string `validate:"omitempty,oneof=admin editor user"`
+//	}
+//
+// Dùng chung engine go-playground/validator với Gin nhưng tag riêng:
+// → `binding` của Gin validate DTO ở tầng HTTP
+// → `validate` ở đây chạy trong service → áp dụng cả khi gọi từ cronjob, CLI...
+//
+// Rule thêm ngoài bộ có sẵn:
+// → unique[=column]: chưa tồn tại trong bảng (cần checker trong ctx, xem WithUniqueChecker)
+// → slug: chữ thường, số, dấu gạch ngang
+//
+// Rule chéo giữa các field: dùng tag có sẵn (eqfield, gtfield, required_if, required_with...)
+// hoặc implement Validatable cho logic phức tạp hơn.
+package validation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+	"gorm.io/gorm/schema"
+)
+
+// Validatable — rule chéo nhiều field mà tag không diễn tả được
+// Chạy SAU khi tag đã pass, chỉ khi validate đầy đủ (Struct), KHÔNG chạy với Partial
+//
+// VD:
+//
+//	func (p *Promotion) Validate() validation.Errors {
+//		if p.Type == "percent" && p.Value > 100 {
+//			return validation.Errors{validation.NewFieldError("value", "lte", "100")}
+//		}
+//		return nil
+//	}
+type Validatable interface {
+	Validate() Errors
+}
+
+// UniqueChecker — kiểm tra giá trị đã tồn tại trong bảng chưa
+// Service tự dựng từ repository: ExistsByField khi tạo, ExistsByFieldExcept khi sửa
+type UniqueChecker func(column string, value any) (bool, error)
+
+type uniqueKey struct{}
+
+// uniqueState — checker + lỗi DB phát sinh trong lúc validate
+// (hàm rule của validator chỉ trả bool, lỗi DB phải gửi ra ngoài qua đây)
+type uniqueState struct {
+	check UniqueChecker
+	err   error
+}
+
+// WithUniqueChecker — gắn checker vào ctx cho rule unique
+// Không có checker → rule unique bỏ qua (pass)
+func WithUniqueChecker(ctx context.Context, check UniqueChecker) context.Context {
+	return context.WithValue(ctx, uniqueKey{}, &uniqueState{check: check})
+}
+
+var (
+	engine     *validator.Validate
+	engineOnce sync.Once
+	slugRegex  = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+	naming     = schema.NamingStrategy{}
+)
+
+// Engine — validator dùng chung (khởi tạo 1 lần, thread-safe)
+// Cần rule riêng → Engine().RegisterValidation(...) lúc khởi động + RegisterMessage
+func Engine() *validator.Validate {
+	engineOnce.Do(func() {
+		engine = validator.New(validator.WithRequiredStructEnabled())
+		engine.SetTagName("validate")
+
+		// Tên field trong lỗi lấy theo json tag → khớp với field client gửi lên
+		engine.RegisterTagNameFunc(FieldName)
+
+		_ = engine.RegisterValidation("slug", func(fl validator.FieldLevel) bool {
+			return slugRegex.MatchString(fl.Field().String())
+		})
+		_ = engine.RegisterValidationCtx("unique", validateUnique)
+	})
+	return engine
+}
+
+// FieldName — tên field theo json tag (bỏ option ",omitempty"), không có tag → tên Go
+// Đăng ký cho engine nào cũng được: RegisterTagNameFunc(validation.FieldName)
+func FieldName(f reflect.StructField) string {
+	name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return f.Name
+	}
+	return name
+}
+
+// validateUnique — unique hoặc unique=column
+// Không ghi column → suy ra từ tên field Go theo quy tắc của GORM (UserName → user_name)
+func validateUnique(ctx context.Context, fl validator.FieldLevel) bool {
+	state, ok := ctx.Value(uniqueKey{}).(*uniqueState)
+	if !ok || state.check == nil || fl.Field().IsZero() {
+		return true
+	}
+
+	column := fl.Param()
+	if column == "" {
+		column = naming.ColumnName("", fl.StructFieldName())
+	}
+	exists, err := state.check(column, fl.Field().Interface())
+	if err != nil {
+		state.err = err
+		return true
+	}
+	return !exists
+}
+
+// Struct — validate toàn bộ field (dùng cho Create)
+// Trả về Errors nếu data sai, error thường nếu lỗi hệ thống (DB lỗi khi check unique)
+func Struct(ctx context.Context, payload any) error {
+	if err := finish(ctx, Engine().StructCtx(ctx, payload)); err != nil {
+		return err
+	}
+	if v, ok := payload.(Validatable); ok {
+		if errs := v.Validate(); len(errs) > 0 {
+			return errs
+		}
+	}
+	return nil
+}
+
+// Partial — chỉ validate field KHÁC zero value (dùng cho Update dạng PATCH)
+// Khớp với BaseRepository.Update: field zero value bị GORM bỏ qua, không ghi xuống DB
+// → không đòi required cho field client không gửi
+func Partial(ctx context.Context, payload any) error {
+	fields := nonZeroFields(payload)
+	if len(fields) == 0 {
+		return nil
+	}
+	return finish(ctx, Engine().StructPartialCtx(ctx, payload, fields...))
+}
+
+// Each — validate từng phần tử của slice (dùng cho BulkCreate)
+// Tên field có tiền tố vị trí: "[3].name"
+func Each[T any](ctx context.Context, payloads []T) error {
+	var all Errors
+	for i := range payloads {
+		err := Struct(ctx, &payloads[i])
+		var errs Errors
+		switch {
+		case err == nil:
+			continue
+		case errors.As(err, &errs):
+			all = append(all, errs.Prefix(fmt.Sprintf("[%d]", i))...)
+		default:
+			return err
+		}
+	}
+	if len(all) > 0 {
+		return all
+	}
+	return nil
+}
+
+// finish — gom lỗi của validator thành Errors, ưu tiên báo lỗi DB của rule unique
+func finish(ctx context.Context, err error) error {
+	if state, ok := ctx.Value(uniqueKey{}).(*uniqueState); ok && state.err != nil {
+		return fmt.Errorf("unique check failed: %w", state.err)
+	}
+	if err == nil {
+		return nil
+	}
+
+	var invalid *validator.InvalidValidationError
+	if errors.As(err, &invalid) {
+		return fmt.Errorf("validation: %w", err)
+	}
+
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return err
+	}
+	return FromValidator(verrs)
+}
+
+// FromValidator — lỗi gốc của go-playground/validator → Errors
+// Dùng cho cả lỗi từ engine khác (VD: tag `binding` của Gin), field lấy theo tag name func của engine đó
+func FromValidator(verrs validator.ValidationErrors) Errors {
+	errs := make(Errors, 0, len(verrs))
+	for _, fe := range verrs {
+		errs = append(errs, NewFieldError(fieldPath(fe), fe.Tag(), fe.Param()))
+	}
+	return errs
+}
+
+// fieldPath — "UserCatalogue.items[0].name" → "items[0].name" (bỏ tên struct gốc)
+func fieldPath(fe validator.FieldError) string {
+	ns := fe.Namespace()
+	if i := strings.Index(ns, "."); i >= 0 {
+		return ns[i+1:]
+	}
+	return fe.Field()
+}
+
+// nonZeroFields — tên Go của các field top-level khác zero value
+func nonZeroFields(payload any) []string {
+	v := reflect.Indirect(reflect.ValueOf(payload))
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	t := v.Type()
+	fields := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() && !v.Field(i).IsZero() {
+			fields = append(fields, t.Field(i).Name)
+		}
+	}
+	return fields
+}
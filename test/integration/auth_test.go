@@ -0,0 +1,230 @@
+package integration
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+
+	"golang-base/internal/auth/apikey"
+	"golang-base/internal/auth/token"
+	"golang-base/internal/auth/totp"
+	"golang-base/pkg/secure"
+)
+
+func TestRegisterAlwaysHashesPassword(t *testing.T) {
+	s := newServer(t)
+
+	// Client gửi sẵn 1 bcrypt hash (cost 4) làm mật khẩu → vẫn bị hash lại như chuỗi thường
+	weak, err := bcrypt.GenerateFromPassword([]byte("short"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("bcrypt: %v", err)
+	}
+	s.do(http.MethodPost, "/v1/auth/register", "", map[string]any{
+		"name":                  "Hash Smuggler",
+		"email":                 "smuggler@example.com",
+		"password":              string(weak),
+		"password_confirmation": string(weak),
+	}).expect(http.StatusCreated)
+
+	var stored string
+	s.db.Raw("SELECT password FROM users WHERE email = ?", "smuggler@example.com").Scan(&stored)
+	if stored == string(weak) {
+		t.Fatal("client-supplied hash stored verbatim")
+	}
+	if cost, err := bcrypt.Cost([]byte(stored)); err != nil || cost != secure.PasswordCost {
+		t.Fatalf("expected bcrypt cost %d, got %d (%v)", secure.PasswordCost, cost, err)
+	}
+
+	s.do(http.MethodPost, "/v1/auth/login", "", map[string]any{"email": "smuggler@example.com", "password": "short"}).
+		expectError(http.StatusUnauthorized, "INVALID_CREDENTIALS")
+	s.do(http.MethodPost, "/v1/auth/login", "", map[string]any{"email": "smuggler@example.com", "password": string(weak)}).
+		expect(http.StatusOK)
+}
+
+func TestRefreshTokenReuseRevokesFamily(t *testing.T) {
+	s := newServer(t)
+	first := s.loginTokens(adminEmail)
+
+	// Lần đầu → cặp mới cùng family
+	var rotated tokens
+	s.do(http.MethodPost, "/v1/auth/refresh", "", map[string]any{"refresh_token": first.RefreshToken}).
+		expect(http.StatusOK).
+		decode(&rotated)
+	if rotated.RefreshToken == "" || rotated.RefreshToken == first.RefreshToken {
+		t.Fatalf("refresh did not rotate token: %+v", rotated)
+	}
+
+	// Gửi lại token đã dùng → reuse, cả family bị thu hồi
+	s.do(http.MethodPost, "/v1/auth/refresh", "", map[string]any{"refresh_token": first.RefreshToken}).
+		expectError(http.StatusUnauthorized, "REFRESH_TOKEN_REUSED")
+
+	// Token mới nhất của family (chưa dùng) cũng hết hiệu lực
+	s.do(http.MethodPost, "/v1/auth/refresh", "", map[string]any{"refresh_token": rotated.RefreshToken}).
+		expectError(http.StatusUnauthorized, "INVALID_REFRESH_TOKEN")
+
+	var active int64
+	s.db.Raw(`SELECT COUNT(*) FROM sessions s JOIN users u ON u.id = s.user_id
+		WHERE u.email = ? AND s.revoked_at IS NULL`, adminEmail).Scan(&active)
+	// Còn lại đúng 1 session: session của s.token (newServer), session vừa bị reuse đã thu hồi
+	if active != 1 {
+		t.Fatalf("expected 1 active session after reuse, got %d", active)
+	}
+}
+
+func TestTwoFactorCodesAreSingleUse(t *testing.T) {
+	s := newServer(t)
+	email := s.createUser("2fa@example.com", "member")
+	token := s.login(email)
+
+	var enrollment struct {
+		Secret string `json:"secret"`
+	}
+	s.do(http.MethodPost, "/v1/me/two-factor", token, nil).expect(http.StatusCreated).decode(&enrollment)
+
+	code, err := totp.Code(enrollment.Secret, time.Now())
+	if err != nil {
+		t.Fatalf("totp code: %v", err)
+	}
+	var confirmed struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+	s.do(http.MethodPost, "/v1/me/two-factor/confirm", token, map[string]any{"code": code}).
+		expect(http.StatusOK).
+		decode(&confirmed)
+	if len(confirmed.RecoveryCodes) == 0 {
+		t.Fatal("confirm returned no recovery codes")
+	}
+
+	// challenge — bước 1 đăng nhập, 2FA đã bật → pending token
+	challenge := func() string {
+		var body struct {
+			Challenge struct {
+				PendingToken string `json:"pending_token"`
+			} `json:"challenge"`
+		}
+		s.do(http.MethodPost, "/v1/auth/login", "", map[string]any{"email": email, "password": adminPassword}).
+			expect(http.StatusOK).
+			decode(&body)
+		if body.Challenge.PendingToken == "" {
+			t.Fatal("login did not return a two-factor challenge")
+		}
+		return body.Challenge.PendingToken
+	}
+	verify := func(pending string, fields map[string]any) *result {
+		fields["pending_token"] = pending
+		return s.do(http.MethodPost, "/v1/auth/two-factor/verify", "", fields)
+	}
+
+	// Mã TOTP đã dùng lúc confirm → không dùng lại được để đăng nhập
+	pending := challenge()
+	verify(pending, map[string]any{"code": code}).expectError(http.StatusUnauthorized, "INVALID_TWO_FACTOR_CODE")
+
+	// Mã dự phòng: không phân biệt hoa thường, dùng được đúng 1 lần
+	recovery := confirmed.RecoveryCodes[0]
+	verify(pending, map[string]any{"recovery_code": strings.ToUpper(recovery)}).expect(http.StatusOK)
+	verify(challenge(), map[string]any{"recovery_code": recovery}).expectError(http.StatusUnauthorized, "INVALID_TWO_FACTOR_CODE")
+}
+
+func TestRevokedSessionTokenRejected(t *testing.T) {
+	s := newServer(t)
+	email := s.createUser("revoke@example.com", "member")
+	phone, laptop, tablet := s.login(email), s.login(email), s.login(email)
+
+	// Đăng xuất → access token của session đó bị chặn ngay, chưa cần chờ hết hạn
+	s.do(http.MethodGet, "/v1/me", phone, nil).expect(http.StatusOK)
+	s.do(http.MethodPost, "/v1/auth/logout", phone, nil).expect(http.StatusOK)
+	s.do(http.MethodGet, "/v1/me", phone, nil).expectError(http.StatusUnauthorized, "SESSION_REVOKED")
+	s.do(http.MethodGet, "/v1/me", laptop, nil).expect(http.StatusOK)
+
+	// Thu hồi thẳng trong DB (instance khác, không có event) → session chưa cache cũng bị chặn
+	s.db.Exec("UPDATE sessions SET revoked_at = NOW() WHERE id = ?", sessionID(t, tablet))
+	s.do(http.MethodGet, "/v1/me", tablet, nil).expectError(http.StatusUnauthorized, "SESSION_REVOKED")
+
+	// Đăng xuất mọi nơi → token đang dùng cũng hết hiệu lực
+	s.do(http.MethodPost, "/v1/auth/logout-all", laptop, nil).expect(http.StatusOK)
+	s.do(http.MethodGet, "/v1/me", laptop, nil).expectError(http.StatusUnauthorized, "SESSION_REVOKED")
+}
+
+// sessionID — claim "sid" của access token (không verify chữ ký, token do chính server test cấp)
+func sessionID(t *testing.T, raw string) string {
+	t.Helper()
+	var claims token.Claims
+	if _, _, err := jwt.NewParser().ParseUnverified(raw, &claims); err != nil || claims.SessionID == "" {
+		t.Fatalf("access token has no sid: %v", err)
+	}
+	return claims.SessionID
+}
+
+func TestLoginThrottleRetryAfter(t *testing.T) {
+	s := newServer(t)
+	email := s.createUser("throttle@example.com", "member")
+	wrong := map[string]any{"email": email, "password": "wrong-password"}
+
+	// 3 lần sai đầu (FreeAttempts) + lần thứ 4 → vẫn là sai mật khẩu, lần 4 bắt đầu delay 1 giây
+	for i := 0; i < 4; i++ {
+		s.do(http.MethodPost, "/v1/auth/login", "", wrong).expectError(http.StatusUnauthorized, "INVALID_CREDENTIALS")
+	}
+	res := s.do(http.MethodPost, "/v1/auth/login", "", map[string]any{"email": email, "password": adminPassword}).
+		expectError(http.StatusTooManyRequests, "TOO_MANY_ATTEMPTS")
+	if got := res.header.Get("Retry-After"); got != "1" {
+		t.Fatalf("expected Retry-After 1, got %q", got)
+	}
+
+	s.do(http.MethodPost, "/v1/auth/unlock", "", map[string]any{"token": "not-a-real-token"}).
+		expectError(http.StatusBadRequest, "INVALID_UNLOCK_TOKEN")
+}
+
+func TestAPIKeyLifecycle(t *testing.T) {
+	s := newServer(t)
+	manager := s.login(s.createUser("keys@example.com", "keymaster", "apikey.manage", "catalogue.view", "catalogue.create"))
+
+	// Scope không tồn tại → 422, scope người tạo không có → 403
+	s.do(http.MethodPost, "/v1/api-keys", manager, map[string]any{"name": "worker", "scopes": []string{"nope.view"}}).
+		expectError(http.StatusUnprocessableEntity, "VALIDATION_FAILED")
+	s.do(http.MethodPost, "/v1/api-keys", manager, map[string]any{"name": "worker", "scopes": []string{"catalogue.delete"}}).
+		expectError(http.StatusForbidden, "FORBIDDEN")
+
+	var created struct {
+		Key    string `json:"key"`
+		APIKey struct {
+			ID     uint64 `json:"id"`
+			Prefix string `json:"prefix"`
+		} `json:"api_key"`
+	}
+	s.do(http.MethodPost, "/v1/api-keys", manager, map[string]any{"name": "worker", "scopes": []string{"catalogue.view"}}).
+		expect(http.StatusCreated).
+		decode(&created)
+	if prefix, ok := apikey.Split(created.Key); !ok || prefix != created.APIKey.Prefix {
+		t.Fatalf("unexpected key %q (prefix %q)", created.Key, created.APIKey.Prefix)
+	}
+
+	// DB chỉ có hash, tra theo prefix
+	var stored string
+	s.db.Raw("SELECT key_hash FROM api_keys WHERE prefix = ?", created.APIKey.Prefix).Scan(&stored)
+	if stored != secure.HashToken(created.Key) {
+		t.Fatalf("expected stored hash of key, got %q", stored)
+	}
+
+	withKey := func(method, path, key string) *result {
+		return s.doHeader(method, path, "", map[string]any{"name": "Via key", "slug": "via-key"}, http.Header{apikey.Header: {key}})
+	}
+	withKey(http.MethodGet, "/v1/user-catalogues", created.Key).expect(http.StatusOK)
+	withKey(http.MethodPost, "/v1/user-catalogues", created.Key).expectError(http.StatusForbidden, "PERMISSION_DENIED")
+
+	// Đúng prefix, sai secret
+	forged := created.Key[:len(created.Key)-4] + "AAAA"
+	if forged == created.Key {
+		forged = created.Key[:len(created.Key)-4] + "BBBB"
+	}
+	withKey(http.MethodGet, "/v1/user-catalogues", forged).expectError(http.StatusUnauthorized, "API_KEY_INVALID")
+
+	// Thu hồi → hết hiệu lực ngay (cache của Authenticator bị xóa qua event)
+	s.do(http.MethodDelete, path("/v1/api-keys/%d", created.APIKey.ID), manager, nil).expect(http.StatusOK)
+	withKey(http.MethodGet, "/v1/user-catalogues", created.Key).expectError(http.StatusUnauthorized, "API_KEY_INVALID")
+	s.do(http.MethodDelete, path("/v1/api-keys/%d", created.APIKey.ID), manager, nil).
+		expectError(http.StatusNotFound, "API_KEY_NOT_FOUND")
+}
@@ -0,0 +1,273 @@
+package integration
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// catalogue — field của serializer.UserCatalogue
+type catalogue struct {
+	ID               uint   `json:"id"`
+	Name             string `json:"name"`
+	Slug             string `json:"slug"`
+	Description      string `json:"description"`
+	Role             string `json:"role"`
+	Publish          uint   `json:"publish"`
+	RequireTwoFactor bool   `json:"require_two_factor"`
+}
+
+type cataloguePage struct {
+	Data  []catalogue `json:"data"`
+	Total int64       `json:"total"`
+	Meta  struct {
+		CurrentPage int `json:"current_page"`
+		LastPage    int `json:"last_page"`
+		From        int `json:"from"`
+		To          int `json:"to"`
+	} `json:"meta"`
+	Links struct {
+		Prev string `json:"prev"`
+		Next string `json:"next"`
+	} `json:"links"`
+}
+
+func (s *server) createCatalogue(body map[string]any) catalogue {
+	s.t.Helper()
+	var c catalogue
+	s.admin(http.MethodPost, "/v1/user-catalogues", body).expect(http.StatusCreated).decode(&c)
+	return c
+}
+
+func (s *server) listCatalogues(query string) cataloguePage {
+	s.t.Helper()
+	var page cataloguePage
+	s.admin(http.MethodGet, "/v1/user-catalogues"+query, nil).expect(http.StatusOK).decode(&page)
+	return page
+}
+
+func slugs(page cataloguePage) []string {
+	out := make([]string, len(page.Data))
+	for i, c := range page.Data {
+		out[i] = c.Slug
+	}
+	return out
+}
+
+func TestCatalogueCRUD(t *testing.T) {
+	s := newServer(t)
+
+	// Tạo: slug sinh từ name (bỏ dấu), role mặc định, id / timestamps client gửi bị bỏ qua
+	created := s.createCatalogue(map[string]any{"id": 999, "name": "Biên tập viên", "publish": 2})
+	if created.ID == 999 || created.Slug != "bien-tap-vien" || created.Role != "user" || created.Publish != 2 {
+		t.Fatalf("unexpected created catalogue: %+v", created)
+	}
+
+	var shown catalogue
+	s.admin(http.MethodGet, path("/v1/user-catalogues/%d", created.ID), nil).expect(http.StatusOK).decode(&shown)
+	if shown != created {
+		t.Fatalf("show = %+v, want %+v", shown, created)
+	}
+
+	// Slug trùng → 422 trên field slug
+	res := s.admin(http.MethodPost, "/v1/user-catalogues", map[string]any{"name": "Khác", "slug": "bien-tap-vien"}).
+		expectError(http.StatusUnprocessableEntity, "CATALOGUE_SLUG_TAKEN")
+	if fields := res.errorFields(); !slices.Contains(fields, "slug") {
+		t.Fatalf("expected slug error, got %v", fields)
+	}
+	s.admin(http.MethodPost, "/v1/user-catalogues", map[string]any{"name": "x"}).expectError(http.StatusUnprocessableEntity, "VALIDATION_FAILED")
+
+	// PATCH: chỉ field gửi lên
+	var patched catalogue
+	s.admin(http.MethodPatch, path("/v1/user-catalogues/%d", created.ID), map[string]any{"description": "Sửa bài viết"}).
+		expect(http.StatusOK).
+		decode(&patched)
+	if patched.Description != "Sửa bài viết" || patched.Name != created.Name || patched.Publish != 2 {
+		t.Fatalf("unexpected patched catalogue: %+v", patched)
+	}
+
+	// PUT: ghi đè toàn bộ, zero value (publish 0, require_two_factor false) được lưu
+	var replaced catalogue
+	s.admin(http.MethodPut, path("/v1/user-catalogues/%d", created.ID), map[string]any{
+		"name": "Editor", "slug": "editor", "role": "editor", "publish": 0,
+	}).expect(http.StatusOK).decode(&replaced)
+	if replaced.Slug != "editor" || replaced.Role != "editor" || replaced.Publish != 0 || replaced.Description != "" {
+		t.Fatalf("unexpected replaced catalogue: %+v", replaced)
+	}
+
+	s.admin(http.MethodDelete, path("/v1/user-catalogues/%d", created.ID), nil).expect(http.StatusOK)
+	s.admin(http.MethodGet, path("/v1/user-catalogues/%d", created.ID), nil).expectError(http.StatusNotFound, "NOT_FOUND")
+	s.admin(http.MethodDelete, path("/v1/user-catalogues/%d", created.ID), nil).expect(http.StatusNotFound)
+	s.admin(http.MethodPut, path("/v1/user-catalogues/%d", created.ID), map[string]any{"name": "Editor"}).expect(http.StatusNotFound)
+
+	// Audit hook ghi trong cùng transaction
+	var audits int64
+	s.db.Table("audit_logs").Where("auditable_type = ?", "user_catalogue").Count(&audits)
+	if audits < 4 {
+		t.Fatalf("expected audit logs for create / update / replace / delete, got %d", audits)
+	}
+}
+
+func TestCatalogueListing(t *testing.T) {
+	s := newServer(t)
+
+	s.createCatalogue(map[string]any{"name": "Quản trị nội dung", "role": "editor", "publish": 2})
+	s.createCatalogue(map[string]any{"name": "Biên tập nháp", "role": "editor", "publish": 1})
+	s.createCatalogue(map[string]any{"name": "Khách hàng", "role": "customer", "publish": 2})
+
+	if page := s.listCatalogues("?keyword=bien"); page.Total != 1 || page.Data[0].Slug != "bien-tap-nhap" {
+		t.Fatalf("keyword search: %+v", page)
+	}
+	if page := s.listCatalogues("?filter[role]=editor&sort=name"); !slices.Equal(slugs(page), []string{"bien-tap-nhap", "quan-tri-noi-dung"}) {
+		t.Fatalf("filter role: %v", slugs(page))
+	}
+	// it-admin (tạo bởi newServer) cũng publish = 2
+	if page := s.listCatalogues("?filter[publish]=2&filter[role][in]=editor,customer&sort=-name"); !slices.Equal(slugs(page), []string{"quan-tri-noi-dung", "khach-hang"}) {
+		t.Fatalf("filter publish + role in: %v", slugs(page))
+	}
+
+	page := s.listCatalogues("?limit=2&sort=id")
+	if page.Total != 4 || len(page.Data) != 2 || page.Data[0].Slug != "it-admin" {
+		t.Fatalf("pagination: total=%d slugs=%v", page.Total, slugs(page))
+	}
+
+	// Page mode: 4 record, 3 / trang → trang 2 chỉ còn 1
+	res := s.admin(http.MethodGet, "/v1/user-catalogues?page=2&per_page=3&sort=id", nil).expect(http.StatusOK)
+	res.decode(&page)
+	if page.Meta.CurrentPage != 2 || page.Meta.LastPage != 2 || page.Meta.From != 4 || page.Meta.To != 4 {
+		t.Fatalf("page meta: %+v", page.Meta)
+	}
+	if page.Links.Next != "" || !strings.Contains(page.Links.Prev, "page=1") {
+		t.Fatalf("page links: %+v", page.Links)
+	}
+	if link := res.header.Get("Link"); !strings.Contains(link, `rel="prev"`) || strings.Contains(link, `rel="next"`) {
+		t.Fatalf("Link header: %s", link)
+	}
+
+	s.admin(http.MethodGet, "/v1/user-catalogues?page=2&offset=3", nil).expect(http.StatusBadRequest)
+	s.admin(http.MethodGet, "/v1/user-catalogues?filter[slug]=x", nil).expectError(http.StatusBadRequest, "INVALID_QUERY")
+	s.admin(http.MethodGet, "/v1/user-catalogues?sort=password", nil).expect(http.StatusBadRequest)
+
+	// Keyset: cursor parse theo kiểu key (uint) giống path id
+	s.admin(http.MethodGet, "/v1/user-catalogues?cursor=abc", nil).expectError(http.StatusBadRequest, "INVALID_QUERY")
+	s.admin(http.MethodGet, "/v1/user-catalogues?cursor=1%20OR%201=1", nil).expectError(http.StatusBadRequest, "INVALID_QUERY")
+	all := s.listCatalogues("?sort=id")
+	next := s.listCatalogues(path("?cursor=%d&sort=id", all.Data[2].ID))
+	if !slices.Equal(slugs(next), slugs(all)[3:]) {
+		t.Fatalf("cursor page: %v", slugs(next))
+	}
+}
+
+func TestCatalogueBulk(t *testing.T) {
+	s := newServer(t)
+
+	var created []catalogue
+	s.admin(http.MethodPost, "/v1/user-catalogues/bulk", []map[string]any{
+		{"name": "Bulk One", "slug": "bulk-one", "publish": 1},
+		{"name": "Bulk Two", "slug": "bulk-two", "publish": 1},
+		{"name": "Bulk Three", "slug": "bulk-three", "publish": 1},
+	}).expect(http.StatusCreated).decode(&created)
+	if len(created) != 3 || created[0].ID == 0 {
+		t.Fatalf("bulk store: %+v", created)
+	}
+	ids := []uint{created[0].ID, created[1].ID}
+
+	var affected struct {
+		Affected int64 `json:"affected"`
+	}
+	s.admin(http.MethodPost, "/v1/user-catalogues/bulk/publish", map[string]any{"ids": ids}).expect(http.StatusOK).decode(&affected)
+	if affected.Affected != 2 {
+		t.Fatalf("publish affected = %d, want 2", affected.Affected)
+	}
+	if page := s.listCatalogues("?filter[publish]=1"); !slices.Equal(slugs(page), []string{"bulk-three"}) {
+		t.Fatalf("after publish, drafts = %v", slugs(page))
+	}
+
+	s.admin(http.MethodPost, "/v1/user-catalogues/bulk/unpublish", map[string]any{"ids": ids[:1]}).expect(http.StatusOK)
+	if page := s.listCatalogues("?filter[publish]=1&sort=id"); !slices.Equal(slugs(page), []string{"bulk-one", "bulk-three"}) {
+		t.Fatalf("after unpublish, drafts = %v", slugs(page))
+	}
+	s.admin(http.MethodPost, "/v1/user-catalogues/bulk/publish", map[string]any{"ids": []uint{}}).expect(http.StatusUnprocessableEntity)
+
+	// Bulk update generic: giá trị sai rule → 422
+	s.admin(http.MethodPatch, "/v1/user-catalogues/bulk", map[string]any{"ids": ids, "fields": map[string]any{"publish": 7}}).
+		expect(http.StatusUnprocessableEntity)
+
+	s.admin(http.MethodDelete, "/v1/user-catalogues/bulk", map[string]any{"ids": ids}).expect(http.StatusOK)
+	if page := s.listCatalogues("?keyword=bulk"); !slices.Equal(slugs(page), []string{"bulk-three"}) {
+		t.Fatalf("after bulk destroy = %v", slugs(page))
+	}
+}
+
+func TestCataloguePermissions(t *testing.T) {
+	s := newServer(t)
+
+	s.do(http.MethodGet, "/v1/user-catalogues", "", nil).expectError(http.StatusUnauthorized, "TOKEN_MISSING")
+	s.do(http.MethodGet, "/v1/user-catalogues", "not-a-jwt", nil).expectError(http.StatusUnauthorized, "TOKEN_INVALID")
+
+	viewer := s.login(s.createUser("viewer@example.com", "viewer", "catalogue.view"))
+	s.do(http.MethodGet, "/v1/user-catalogues", viewer, nil).expect(http.StatusOK)
+	s.do(http.MethodPost, "/v1/user-catalogues", viewer, map[string]any{"name": "Nope"}).expectError(http.StatusForbidden, "PERMISSION_DENIED")
+	s.do(http.MethodPost, "/v1/user-catalogues/bulk/publish", viewer, map[string]any{"ids": []uint{1}}).expect(http.StatusForbidden)
+
+	// role nội bộ chỉ admin thấy
+	var page struct {
+		Data []map[string]any `json:"data"`
+	}
+	s.do(http.MethodGet, "/v1/user-catalogues", viewer, nil).expect(http.StatusOK).decode(&page)
+	if len(page.Data) == 0 {
+		t.Fatal("viewer sees no catalogues")
+	}
+	if _, ok := page.Data[0]["role"]; ok {
+		t.Fatalf("role leaked to non-admin: %v", page.Data[0])
+	}
+	// Không thấy role thì cũng không filter theo role được (đếm kết quả → dò ra role)
+	s.do(http.MethodGet, "/v1/user-catalogues?filter[role]=admin", viewer, nil).expectError(http.StatusBadRequest, "INVALID_QUERY")
+	s.do(http.MethodGet, "/v1/user-catalogues?filter[role][in]=admin,editor", viewer, nil).expectError(http.StatusBadRequest, "INVALID_QUERY")
+	s.do(http.MethodGet, "/v1/user-catalogues?filter[publish]=2", viewer, nil).expect(http.StatusOK)
+	s.admin(http.MethodGet, "/v1/user-catalogues?filter[role]=admin", nil).expect(http.StatusOK)
+}
+
+func TestCatalogueConditionalRequests(t *testing.T) {
+	s := newServer(t)
+	created := s.createCatalogue(map[string]any{"name": "Kiểm duyệt"})
+	url := path("/v1/user-catalogues/%d", created.ID)
+
+	// GET → ETag, If-None-Match khớp → 304 không body
+	etag := s.admin(http.MethodGet, url, nil).expect(http.StatusOK).header.Get("ETag")
+	if etag == "" {
+		t.Fatal("expected ETag on show")
+	}
+	r := s.doHeader(http.MethodGet, url, s.token, nil, http.Header{"If-None-Match": {etag}}).expect(http.StatusNotModified)
+	if len(r.body) != 0 {
+		t.Fatalf("expected empty 304 body, got %s", r.body)
+	}
+
+	// If-Match khớp → ghi + ETag mới; ETag cũ giờ đã lệch → 412
+	// Ghi ngay trong cùng giây với lúc tạo: updated_at (TIMESTAMP, tới giây) chưa đổi, version đã tăng
+	next := s.doHeader(http.MethodPatch, url, s.token, map[string]any{"description": "v2"}, http.Header{"If-Match": {etag}}).
+		expect(http.StatusOK).header.Get("ETag")
+	if next == "" || next == etag {
+		t.Fatalf("expected new ETag after update, got %q", next)
+	}
+	s.doHeader(http.MethodPut, url, s.token, map[string]any{"name": "Kiểm duyệt"}, http.Header{"If-Match": {etag}}).
+		expectError(http.StatusPreconditionFailed, "PRECONDITION_FAILED")
+	s.doHeader(http.MethodDelete, url, s.token, nil, http.Header{"If-Match": {etag}}).
+		expectError(http.StatusPreconditionFailed, "PRECONDITION_FAILED")
+
+	// List: ETag theo nội dung trang
+	list := s.admin(http.MethodGet, "/v1/user-catalogues", nil).expect(http.StatusOK).header.Get("ETag")
+	s.doHeader(http.MethodGet, "/v1/user-catalogues", s.token, nil, http.Header{"If-None-Match": {list}}).expect(http.StatusNotModified)
+
+	// Trang đổi nội dung → ETag khác, If-None-Match cũ không còn 304
+	next = s.doHeader(http.MethodPatch, url, s.token, map[string]any{"name": "Kiểm duyệt v3"}, http.Header{"If-Match": {next}}).
+		expect(http.StatusOK).header.Get("ETag")
+	changed := s.doHeader(http.MethodGet, "/v1/user-catalogues", s.token, nil, http.Header{"If-None-Match": {list}}).
+		expect(http.StatusOK).header.Get("ETag")
+	if changed == "" || changed == list {
+		t.Fatalf("expected new list ETag after update, got %q", changed)
+	}
+
+	s.doHeader(http.MethodDelete, url, s.token, nil, http.Header{"If-Match": {next}}).expect(http.StatusOK)
+}
@@ -0,0 +1,101 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestErrorCatalog(t *testing.T) {
+	s := newServer(t)
+
+	var entries []struct {
+		Code   string `json:"code"`
+		Status int    `json:"status"`
+	}
+	s.do(http.MethodGet, "/v1/errors", "", nil).expect(http.StatusOK).decode(&entries)
+	codes := make(map[string]int, len(entries))
+	for _, e := range entries {
+		codes[e.Code] = e.Status
+	}
+	if codes["CATALOGUE_SLUG_TAKEN"] != http.StatusUnprocessableEntity || codes["NOT_FOUND"] != http.StatusNotFound {
+		t.Fatalf("catalog missing codes: %v", codes)
+	}
+
+	s.do(http.MethodGet, "/v1/does-not-exist", "", nil).expectError(http.StatusNotFound, "ROUTE_NOT_FOUND")
+
+	// Handler của module trả code trong catalog, không phải BAD_REQUEST / UNAUTHORIZED chung chung
+	s.admin(http.MethodGet, "/v1/user-catalogues/abc/permissions", nil).expectError(http.StatusBadRequest, "INVALID_ID")
+	manager := s.login(s.createUser("catalog@example.com", "keymaster", "apikey.manage"))
+	s.do(http.MethodDelete, "/v1/api-keys/abc", manager, nil).expectError(http.StatusBadRequest, "INVALID_ID")
+	s.do(http.MethodPost, "/v1/api-keys/-1/rotate", manager, nil).expectError(http.StatusBadRequest, "INVALID_ID")
+}
+
+// problem — body application/problem+json (RFC 7807)
+type problem struct {
+	Type     string          `json:"type"`
+	Title    string          `json:"title"`
+	Status   int             `json:"status"`
+	Instance string          `json:"instance"`
+	Code     string          `json:"code"`
+	Errors   json.RawMessage `json:"errors"`
+}
+
+func TestProblemDetails(t *testing.T) {
+	s := newServer(t)
+	accept := http.Header{"Accept": {"application/problem+json"}}
+
+	r := s.doHeader(http.MethodGet, "/v1/user-catalogues/999999", s.token, nil, accept).expect(http.StatusNotFound)
+	if ct := r.header.Get("Content-Type"); ct != "application/problem+json" {
+		t.Fatalf("expected problem content type, got %q", ct)
+	}
+	var p problem
+	if err := json.Unmarshal(r.body, &p); err != nil {
+		t.Fatalf("decode problem: %v: %s", err, r.body)
+	}
+	if p.Status != http.StatusNotFound || p.Code != "NOT_FOUND" ||
+		p.Type != "/v1/errors#NOT_FOUND" || p.Instance != "/v1/user-catalogues/999999" {
+		t.Fatalf("unexpected problem: %s", r.body)
+	}
+
+	// Lỗi field nằm trong extension "errors"
+	r = s.doHeader(http.MethodPost, "/v1/user-catalogues", s.token, map[string]any{"name": "x"}, accept).expect(http.StatusUnprocessableEntity)
+	p = problem{}
+	if err := json.Unmarshal(r.body, &p); err != nil {
+		t.Fatalf("decode problem: %v: %s", err, r.body)
+	}
+	if p.Code != "VALIDATION_FAILED" || len(p.Errors) == 0 {
+		t.Fatalf("expected validation problem, got %s", r.body)
+	}
+
+	// Không có Accept → envelope như cũ
+	r = s.admin(http.MethodGet, "/v1/user-catalogues/999999", nil).expectError(http.StatusNotFound, "NOT_FOUND")
+	if vary := r.header.Get("Vary"); vary != "Accept" {
+		t.Fatalf("expected Vary: Accept, got %q", vary)
+	}
+}
+
+func TestBindingErrors(t *testing.T) {
+	s := newServer(t)
+
+	// Sai kiểu / JSON hỏng → 400 INVALID_BODY, cùng format {field, rule, message} với lỗi validate
+	fields := s.admin(http.MethodPost, "/v1/user-catalogues", map[string]any{"name": 1}).
+		expectError(http.StatusBadRequest, "INVALID_BODY").errorFields()
+	if !slices.Equal(fields, []string{"name"}) {
+		t.Fatalf("expected type error on name, got %v", fields)
+	}
+	fields = s.admin(http.MethodPost, "/v1/user-catalogues/bulk", []any{map[string]any{"name": "Biên tập"}, map[string]any{"name": true}}).
+		expectError(http.StatusBadRequest, "INVALID_BODY").errorFields()
+	if !slices.Equal(fields, []string{"[1].name"}) {
+		t.Fatalf("expected type error on [1].name, got %v", fields)
+	}
+
+	// Message theo Accept-Language
+	r := s.doHeader(http.MethodPost, "/v1/user-catalogues", s.token, map[string]any{"name": "x"},
+		http.Header{"Accept-Language": {"en-US,en;q=0.9"}}).expectError(http.StatusUnprocessableEntity, "VALIDATION_FAILED")
+	if !strings.Contains(string(r.body), "name must be at least 2 characters") {
+		t.Fatalf("expected english message, got %s", r.body)
+	}
+}
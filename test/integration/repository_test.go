@@ -0,0 +1,86 @@
+package integration
+
+import (
+	"errors"
+	"testing"
+
+	"golang-base/internal/model"
+	"golang-base/internal/repository"
+)
+
+// MySQL: UPDATE ghi lại đúng giá trị cũ → RowsAffected = 0 nhưng record vẫn tồn tại
+func TestUpdateUnchangedRowIsNotNotFound(t *testing.T) {
+	s := newServer(t)
+	sid := sessionID(t, s.token)
+	sessions := repository.NewBaseRepository[model.Session, string](s.db)
+
+	const device = "integration-phone"
+	if err := sessions.UpdateFields(sid, map[string]any{"device": device}); err != nil {
+		t.Fatalf("UpdateFields: %v", err)
+	}
+
+	// sessions không có updated_at → lần ghi lặp lại không đổi cột nào
+	if err := sessions.UpdateFields(sid, map[string]any{"device": device}); err != nil {
+		t.Fatalf("UpdateFields unchanged: %v", err)
+	}
+	if err := sessions.Update(sid, &model.Session{Device: device}); err != nil {
+		t.Fatalf("Update unchanged: %v", err)
+	}
+
+	if err := sessions.UpdateFields("00000000-0000-0000-0000-000000000000", map[string]any{"device": device}); !errors.Is(err, repository.ErrNotFound) {
+		t.Fatalf("missing row: expected ErrNotFound, got %v", err)
+	}
+}
+
+// Version tăng ở mọi đường ghi, kể cả nhiều lần trong cùng 1 giây → ETag luôn đổi
+func TestVersionBumpsOnEveryWrite(t *testing.T) {
+	s := newServer(t)
+	catalogues := repository.NewBaseRepository[model.UserCatalogue, uint](s.db)
+
+	record := &model.UserCatalogue{Name: "Phiên bản", Slug: "phien-ban", Role: model.DefaultCatalogueRole}
+	if err := catalogues.Create(record); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	version := func() uint {
+		t.Helper()
+		current, err := catalogues.FindById(record.ID, nil)
+		if err != nil || current == nil {
+			t.Fatalf("FindById: %v", err)
+		}
+		return current.Version
+	}
+	if v := version(); v != 1 {
+		t.Fatalf("new record: expected version 1, got %d", v)
+	}
+
+	steps := []struct {
+		name  string
+		write func() error
+	}{
+		// Version trong payload bị bỏ qua, repository tự tăng
+		{"Update", func() error {
+			return catalogues.Update(record.ID, &model.UserCatalogue{Description: "v2", Version: 99})
+		}},
+		{"Replace", func() error {
+			return catalogues.Replace(record.ID, &model.UserCatalogue{Name: "Phiên bản", Slug: "phien-ban", Role: "user", Description: "v3"})
+		}},
+		{"UpdateFields", func() error { return catalogues.UpdateFields(record.ID, map[string]any{"description": "v4"}) }},
+		{"BulkUpdateFields", func() error {
+			_, err := catalogues.BulkUpdateFields(map[string]any{"id": []uint{record.ID}}, map[string]any{"publish": model.PublishDraft})
+			return err
+		}},
+		{"Save", func() error {
+			current, _ := catalogues.FindById(record.ID, nil)
+			current.Description = "v6"
+			return catalogues.Save(current)
+		}},
+	}
+	for i, step := range steps {
+		if err := step.write(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if v := version(); v != uint(i+2) {
+			t.Fatalf("%s: expected version %d, got %d", step.name, i+2, v)
+		}
+	}
+}
@@ -0,0 +1,325 @@
+// Package integration — test end-to-end qua HTTP trên schema MySQL thật (chạy toàn bộ migrations/)
+//
+// Chạy:
+//
+//	TEST_MYSQL_DSN="root:@tcp(127.0.0.1:3306)/golang_base_test" go test ./test/integration -v
+//
+// Không set TEST_MYSQL_DSN → skip toàn bộ
+// Database sẽ bị XÓA SẠCH rồi migrate lại → tên database bắt buộc chứa "test"
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-sql-driver/mysql"
+	"go.uber.org/zap"
+	gormmysql "gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"golang-base/internal/auth/apikey"
+	"golang-base/internal/auth/rbac"
+	"golang-base/internal/auth/session"
+	"golang-base/internal/auth/throttle"
+	"golang-base/internal/auth/token"
+	"golang-base/internal/auth/totp"
+	"golang-base/internal/event"
+	"golang-base/internal/routers"
+)
+
+const (
+	dsnEnv        = "TEST_MYSQL_DSN"
+	migrationsDir = "../../migrations"
+
+	adminEmail    = "admin@example.com"
+	adminPassword = "password123"
+)
+
+// server — app dựng đủ như production (routers.RegisterRoutes) trên DB test
+type server struct {
+	t      *testing.T
+	db     *gorm.DB
+	bus    *event.Bus
+	router *gin.Engine
+	token  string // access token của admin (đủ quyền catalogue.*)
+}
+
+// newServer — migrate lại từ đầu, dựng router, tạo admin + đăng nhập
+func newServer(t *testing.T) *server {
+	t.Helper()
+
+	raw := os.Getenv(dsnEnv)
+	if raw == "" {
+		t.Skipf("%s not set, skipping integration test", dsnEnv)
+	}
+	cfg, err := mysql.ParseDSN(raw)
+	if err != nil {
+		t.Fatalf("parse %s: %v", dsnEnv, err)
+	}
+	if !strings.Contains(cfg.DBName, "test") {
+		t.Fatalf("refusing to reset database %q: name must contain \"test\"", cfg.DBName)
+	}
+	cfg.MultiStatements = true // file migration có nhiều câu lệnh
+	cfg.ParseTime = true
+
+	db, err := gorm.Open(gormmysql.Open(cfg.FormatDSN()), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent), TranslateError: true})
+	if err != nil {
+		t.Fatalf("connect database: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	migrate(t, db)
+
+	log := zap.NewNop()
+	bus := event.NewBus(log)
+	tokens, err := token.NewManager(token.Config{Secret: "integration-test-secret-0123456789abcdef"})
+	if err != nil {
+		t.Fatalf("token manager: %v", err)
+	}
+	twoFactor, err := totp.NewManager(totp.Config{Issuer: "integration", EncryptionKey: "integration-test-2fa-key-0123456789abcdef"})
+	if err != nil {
+		t.Fatalf("totp manager: %v", err)
+	}
+	resolver := rbac.NewResolver(db, rbac.DefaultTTL)
+	resolver.Subscribe(bus)
+	revocations := session.NewRevocationList(db, session.DefaultAliveTTL, tokens.AccessTTL())
+	revocations.Subscribe(bus)
+	keys := apikey.NewAuthenticator(db, apikey.DefaultCacheTTL)
+	keys.Subscribe(bus)
+
+	router := routers.NewRouter(log)
+	routers.RegisterRoutes(router, routers.Deps{
+		Log:         log,
+		DB:          db,
+		Bus:         bus,
+		Tokens:      tokens,
+		Revocations: revocations,
+		RBAC:        resolver,
+		APIKeys:     keys,
+		TOTP:        twoFactor,
+		Throttle:    throttle.New(throttle.NewMemoryStore(), throttle.Config{}, bus),
+	})
+
+	s := &server{t: t, db: db, bus: bus, router: router}
+	s.token = s.login(s.createUser(adminEmail, "admin", "catalogue.view", "catalogue.create", "catalogue.update", "catalogue.delete"))
+	return s
+}
+
+// migrate — chạy *.down.sql (ngược) rồi *.up.sql, lỗi down bỏ qua (bảng chưa tồn tại)
+func migrate(t *testing.T, db *gorm.DB) {
+	t.Helper()
+
+	downs, _ := filepath.Glob(filepath.Join(migrationsDir, "*.down.sql"))
+	slices.Sort(downs)
+	slices.Reverse(downs)
+	for _, file := range downs {
+		sql, _ := os.ReadFile(file)
+		_ = db.Exec(string(sql)).Error
+	}
+
+	ups, err := filepath.Glob(filepath.Join(migrationsDir, "*.up.sql"))
+	if err != nil || len(ups) == 0 {
+		t.Fatalf("no migrations found in %s", migrationsDir)
+	}
+	slices.Sort(ups)
+	for _, file := range ups {
+		sql, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("read %s: %v", file, err)
+		}
+		if err := db.Exec(string(sql)).Error; err != nil {
+			t.Fatalf("migrate %s: %v", filepath.Base(file), err)
+		}
+	}
+}
+
+// createUser — đăng ký qua API, gán vào catalogue có role + các permission cho trước
+func (s *server) createUser(email, role string, permissions ...string) string {
+	s.t.Helper()
+
+	res := s.do(http.MethodPost, "/v1/auth/register", "", map[string]any{
+		"name":                  "Test " + role,
+		"email":                 email,
+		"password":              adminPassword,
+		"password_confirmation": adminPassword,
+	})
+	res.expect(http.StatusCreated)
+
+	slug := "it-" + role
+	db := s.db.Exec("INSERT INTO user_catalogues (name, slug, role, publish) VALUES (?, ?, ?, 2)", "IT "+role, slug, role)
+	if db.Error != nil {
+		s.t.Fatalf("create catalogue %s: %v", slug, db.Error)
+	}
+	if len(permissions) > 0 {
+		err := s.db.Exec(`INSERT INTO user_catalogue_permissions (user_catalogue_id, permission_id)
+			SELECT c.id, p.id FROM user_catalogues c JOIN permissions p ON p.name IN ? WHERE c.slug = ?`, permissions, slug).Error
+		if err != nil {
+			s.t.Fatalf("grant permissions: %v", err)
+		}
+	}
+	err := s.db.Exec("UPDATE users SET user_catalogue_id = (SELECT id FROM user_catalogues WHERE slug = ?) WHERE email = ?", slug, email).Error
+	if err != nil {
+		s.t.Fatalf("assign catalogue: %v", err)
+	}
+	return email
+}
+
+// tokens — cặp token trả về khi đăng nhập / refresh
+type tokens struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// login — access token của user
+func (s *server) login(email string) string {
+	s.t.Helper()
+	return s.loginTokens(email).AccessToken
+}
+
+// loginTokens — đăng nhập, trả cả access + refresh token
+func (s *server) loginTokens(email string) tokens {
+	s.t.Helper()
+
+	var body struct {
+		Tokens tokens `json:"tokens"`
+	}
+	s.do(http.MethodPost, "/v1/auth/login", "", map[string]any{"email": email, "password": adminPassword}).
+		expect(http.StatusOK).
+		decode(&body)
+	if body.Tokens.AccessToken == "" {
+		s.t.Fatalf("login %s: no access token", email)
+	}
+	return body.Tokens
+}
+
+// ============================================================
+// HTTP
+// ============================================================
+
+type result struct {
+	t      *testing.T
+	code   int
+	header http.Header
+	body   []byte
+}
+
+// envelope — APIResponse, data / errors giữ nguyên JSON để decode theo từng test
+type envelope struct {
+	Status    bool            `json:"status"`
+	Code      int             `json:"code"`
+	ErrorCode string          `json:"error_code"`
+	Data      json.RawMessage `json:"data"`
+	Errors    json.RawMessage `json:"errors"`
+}
+
+// do — gửi request qua router, body nil = không có body
+func (s *server) do(method, path, token string, body any) *result {
+	s.t.Helper()
+	return s.doHeader(method, path, token, body, nil)
+}
+
+// doHeader — như do, kèm header riêng (VD: Accept, If-None-Match)
+func (s *server) doHeader(method, path, token string, body any, header http.Header) *result {
+	s.t.Helper()
+
+	var reader *bytes.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			s.t.Fatalf("marshal body: %v", err)
+		}
+		reader = bytes.NewReader(raw)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+	return &result{t: s.t, code: w.Code, header: w.Header(), body: w.Body.Bytes()}
+}
+
+// admin — request bằng token admin
+func (s *server) admin(method, path string, body any) *result {
+	s.t.Helper()
+	return s.do(method, path, s.token, body)
+}
+
+func (r *result) expect(code int) *result {
+	r.t.Helper()
+	if r.code != code {
+		r.t.Fatalf("expected status %d, got %d: %s", code, r.code, r.body)
+	}
+	return r
+}
+
+// decode — data của envelope vào out
+func (r *result) decode(out any) *result {
+	r.t.Helper()
+	var env envelope
+	if err := json.Unmarshal(r.body, &env); err != nil {
+		r.t.Fatalf("decode envelope: %v: %s", err, r.body)
+	}
+	if err := json.Unmarshal(env.Data, out); err != nil {
+		r.t.Fatalf("decode data: %v: %s", err, env.Data)
+	}
+	return r
+}
+
+// expectError — status + error_code (apperror)
+func (r *result) expectError(code int, errorCode string) *result {
+	r.t.Helper()
+	r.expect(code)
+	var env envelope
+	if err := json.Unmarshal(r.body, &env); err != nil {
+		r.t.Fatalf("decode envelope: %v: %s", err, r.body)
+	}
+	if env.ErrorCode != errorCode {
+		r.t.Fatalf("expected error_code %s, got %q: %s", errorCode, env.ErrorCode, r.body)
+	}
+	return r
+}
+
+// errorFields — danh sách field lỗi validate (422)
+func (r *result) errorFields() []string {
+	r.t.Helper()
+	var env envelope
+	var errs []struct {
+		Field string `json:"field"`
+	}
+	if err := json.Unmarshal(r.body, &env); err != nil {
+		r.t.Fatalf("decode envelope: %v", err)
+	}
+	if err := json.Unmarshal(env.Errors, &errs); err != nil {
+		r.t.Fatalf("decode errors: %v: %s", err, env.Errors)
+	}
+	fields := make([]string, len(errs))
+	for i, e := range errs {
+		fields[i] = e.Field
+	}
+	return fields
+}
+
+func path(format string, args ...any) string {
+	return fmt.Sprintf(format, args...)
+}